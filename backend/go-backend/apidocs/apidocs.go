@@ -0,0 +1,93 @@
+package apidocs
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Spec OpenAPI 3 文档，新增或修改路由时需同步更新 openapi.json
+//
+//go:embed openapi.json
+var Spec []byte
+
+// SwaggerUIHTML 加载 /api/openapi.json 的 Swagger UI 页面
+const SwaggerUIHTML = `<!DOCTYPE html>
+<html lang="zh-CN">
+<head>
+  <meta charset="utf-8" />
+  <title>Hackathon Backend API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css" />
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js" crossorigin></script>
+  <script>
+    window.onload = function () {
+      window.ui = SwaggerUIBundle({ url: "/api/openapi.json", dom_id: "#swagger-ui" });
+    };
+  </script>
+</body>
+</html>
+`
+
+// document 只解析路由校验需要的部分
+type document struct {
+	Paths map[string]map[string]json.RawMessage `json:"paths"`
+}
+
+// operationKey 形如 "GET /api/events/{id}"
+func operationKey(method, path string) string {
+	return strings.ToUpper(method) + " " + path
+}
+
+// openAPIPath 将 Gin 路由参数 :id / *path 转为 OpenAPI 的 {id} / {path}
+func openAPIPath(ginPath string) string {
+	segments := strings.Split(ginPath, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// CheckRoutes 比对 Gin 已注册的路由与 OpenAPI 文档，返回未写入文档的路由和文档中不存在的路由
+func CheckRoutes(routes gin.RoutesInfo) (undocumented []string, missing []string, err error) {
+	var doc document
+	if err := json.Unmarshal(Spec, &doc); err != nil {
+		return nil, nil, fmt.Errorf("invalid openapi.json: %w", err)
+	}
+
+	documented := map[string]bool{}
+	for path, operations := range doc.Paths {
+		for method := range operations {
+			if method == "parameters" {
+				continue
+			}
+			documented[operationKey(method, path)] = true
+		}
+	}
+
+	registered := map[string]bool{}
+	for _, route := range routes {
+		key := operationKey(route.Method, openAPIPath(route.Path))
+		registered[key] = true
+		if !documented[key] {
+			undocumented = append(undocumented, key)
+		}
+	}
+	for key := range documented {
+		if !registered[key] {
+			missing = append(missing, key)
+		}
+	}
+
+	sort.Strings(undocumented)
+	sort.Strings(missing)
+	return undocumented, missing, nil
+}
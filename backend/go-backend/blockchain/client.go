@@ -2,6 +2,7 @@ package blockchain
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"math/big"
@@ -15,8 +16,12 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
 )
 
+// ErrSubscriptionsUnsupported 节点不支持日志订阅（WebSocket 不可用或 eth_subscribe 未实现）
+var ErrSubscriptionsUnsupported = errors.New("log subscriptions are not supported by the node")
+
 type BlockchainClient struct {
 	httpClient       *ethclient.Client
 	wsClient         *ethclient.Client
@@ -70,9 +75,9 @@ func Init(cfg *config.Config) error {
 	wsClient, err := ethclient.Dial(wsURL)
 	if err != nil {
 		log.Printf("⚠️ WebSocket connection failed: %v", err)
-		log.Printf("⚠️ Will use HTTP client for all operations")
-		// 使用 HTTP 客户端作为后备
-		wsClient = httpClient
+		log.Printf("⚠️ Log subscriptions unavailable, polling mode will be used")
+		// HTTP 客户端无法订阅，保持为 nil 由上层切换到轮询模式
+		wsClient = nil
 	} else {
 		log.Printf("✅ WebSocket connection established")
 	}
@@ -85,7 +90,6 @@ func Init(cfg *config.Config) error {
 	}
 
 	log.Printf("✅ Connected to blockchain (Chain ID: %s)", chainID.String())
-	log.Printf("✅ WebSocket connection established")
 
 	bc := &BlockchainClient{
 		httpClient:       httpClient,
@@ -102,10 +106,16 @@ func (bc *BlockchainClient) GetHTTPClient() *ethclient.Client {
 	return bc.httpClient
 }
 
+// GetWSClient 获取 WebSocket 客户端（WebSocket 不可用时为 nil）
 func (bc *BlockchainClient) GetWSClient() *ethclient.Client {
 	return bc.wsClient
 }
 
+// SupportsSubscriptions 是否具备日志订阅能力
+func (bc *BlockchainClient) SupportsSubscriptions() bool {
+	return bc.wsClient != nil
+}
+
 func (bc *BlockchainClient) GetHackathonAddress() common.Address {
 	return bc.hackathonAddress
 }
@@ -119,6 +129,24 @@ func (bc *BlockchainClient) GetLatestBlockNumber(ctx context.Context) (uint64, e
 	return bc.httpClient.BlockNumber(ctx)
 }
 
+// GetBlockHash 获取指定区块的哈希
+func (bc *BlockchainClient) GetBlockHash(ctx context.Context, number uint64) (common.Hash, error) {
+	header, err := bc.httpClient.HeaderByNumber(ctx, new(big.Int).SetUint64(number))
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return header.Hash(), nil
+}
+
+// GetBlockTime 获取指定区块的时间戳（Unix 秒）
+func (bc *BlockchainClient) GetBlockTime(ctx context.Context, number uint64) (int64, error) {
+	header, err := bc.httpClient.HeaderByNumber(ctx, new(big.Int).SetUint64(number))
+	if err != nil {
+		return 0, err
+	}
+	return int64(header.Time), nil
+}
+
 // GetEventLogs 获取事件日志
 func (bc *BlockchainClient) GetEventLogs(ctx context.Context, fromBlock uint64, toBlock uint64) ([]types.Log, error) {
 	query := ethereum.FilterQuery{
@@ -416,8 +444,42 @@ func (bc *BlockchainClient) GetTicket(ctx context.Context, tokenID *big.Int) (*C
 	return ticket, nil
 }
 
+// IsTicketValid 调用 NFT 合约的 isTicketValid：门票存在、未使用且在活动时间范围内
+func (bc *BlockchainClient) IsTicketValid(ctx context.Context, tokenID *big.Int) (bool, error) {
+	const contractABI = `[{"inputs":[{"internalType":"uint256","name":"_tokenId","type":"uint256"}],"name":"isTicketValid","outputs":[{"internalType":"bool","name":"","type":"bool"}],"stateMutability":"view","type":"function"}]`
+
+	parsedABI, err := abi.JSON(strings.NewReader(contractABI))
+	if err != nil {
+		return false, fmt.Errorf("failed to parse ABI: %w", err)
+	}
+
+	data, err := parsedABI.Pack("isTicketValid", tokenID)
+	if err != nil {
+		return false, fmt.Errorf("failed to pack isTicketValid: %w", err)
+	}
+
+	msg := ethereum.CallMsg{
+		To:   &bc.nftTicketAddress,
+		Data: data,
+	}
+	result, err := bc.httpClient.CallContract(ctx, msg, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to call isTicketValid: %w", err)
+	}
+
+	var valid bool
+	if err := parsedABI.UnpackIntoInterface(&valid, "isTicketValid", result); err != nil {
+		return false, fmt.Errorf("failed to unpack isTicketValid: %w", err)
+	}
+	return valid, nil
+}
+
 // SubscribeToLogs 订阅合约事件日志
 func (bc *BlockchainClient) SubscribeToLogs(ctx context.Context, addresses []common.Address) (chan types.Log, ethereum.Subscription, error) {
+	if bc.wsClient == nil {
+		return nil, nil, ErrSubscriptionsUnsupported
+	}
+
 	query := ethereum.FilterQuery{
 		Addresses: addresses,
 	}
@@ -427,6 +489,9 @@ func (bc *BlockchainClient) SubscribeToLogs(ctx context.Context, addresses []com
 	logs := make(chan types.Log)
 	sub, err := bc.wsClient.SubscribeFilterLogs(ctx, query, logs)
 	if err != nil {
+		if isNotificationsUnsupported(err) {
+			return nil, nil, fmt.Errorf("%w: %v", ErrSubscriptionsUnsupported, err)
+		}
 		return nil, nil, fmt.Errorf("failed to subscribe to logs: %w", err)
 	}
 
@@ -434,9 +499,24 @@ func (bc *BlockchainClient) SubscribeToLogs(ctx context.Context, addresses []com
 	return logs, sub, nil
 }
 
+// isNotificationsUnsupported 判断错误是否表示节点不支持 eth_subscribe
+func isNotificationsUnsupported(err error) bool {
+	if errors.Is(err, rpc.ErrNotificationsUnsupported) {
+		return true
+	}
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) {
+		// -32601: method not found
+		return rpcErr.ErrorCode() == -32601
+	}
+	return false
+}
+
 func (bc *BlockchainClient) Close() error {
 	bc.httpClient.Close()
-	bc.wsClient.Close()
+	if bc.wsClient != nil {
+		bc.wsClient.Close()
+	}
 	return nil
 }
 
@@ -0,0 +1,135 @@
+package blockchain
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"strings"
+
+	"hackathon-backend/config"
+	"hackathon-backend/models"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const checkInParticipantABI = `[{"inputs":[{"internalType":"uint256","name":"_eventId","type":"uint256"},{"internalType":"address","name":"_participant","type":"address"},{"internalType":"uint256","name":"_tokenId","type":"uint256"}],"name":"checkInParticipant","outputs":[],"stateMutability":"nonpayable","type":"function"}]`
+
+// relayPurpose 中继签到交易的用途标识
+const relayPurpose = "relay_checkin"
+
+// ErrCheckInReverted 模拟执行时合约拒绝了签到交易
+var ErrCheckInReverted = errors.New("check-in transaction would revert")
+
+// CheckInCall checkInParticipant 调用参数
+type CheckInCall struct {
+	EventID     *big.Int
+	Participant common.Address
+	TokenID     *big.Int // 为 0 时不标记门票
+}
+
+// Relayer 使用组织者委托的密钥代为提交签到交易，nonce、手续费和重发由 TxManager 负责。
+// 合约要求 msg.sender 为活动组织者，因此只能为组织者地址等于委托密钥地址的活动签到
+type Relayer struct {
+	txm       *TxManager
+	from      common.Address
+	hackathon common.Address
+	abi       abi.ABI
+}
+
+func NewRelayer(txm *TxManager, key *ecdsa.PrivateKey, hackathon common.Address) (*Relayer, error) {
+	parsedABI, err := abi.JSON(strings.NewReader(checkInParticipantABI))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ABI: %w", err)
+	}
+	return &Relayer{
+		txm:       txm,
+		from:      txm.AddSigner(key),
+		hackathon: hackathon,
+		abi:       parsedABI,
+	}, nil
+}
+
+// Address 委托密钥对应的地址
+func (r *Relayer) Address() common.Address {
+	return r.from
+}
+
+// SimulateCheckIn 以委托地址 eth_call 模拟签到，合约会拒绝时返回 ErrCheckInReverted
+func (r *Relayer) SimulateCheckIn(ctx context.Context, call CheckInCall) error {
+	data, err := r.packCheckIn(call)
+	if err != nil {
+		return err
+	}
+	if _, err := r.txm.Backend().CallContract(ctx, ethereum.CallMsg{From: r.from, To: &r.hackathon, Data: data}, nil); err != nil {
+		return fmt.Errorf("%w: %v", ErrCheckInReverted, err)
+	}
+	return nil
+}
+
+// SubmitCheckIn 通过 TxManager 发送签到交易，reference 用于重启后找回已发送的交易
+func (r *Relayer) SubmitCheckIn(ctx context.Context, call CheckInCall, reference string) (*models.ChainTransaction, error) {
+	data, err := r.packCheckIn(call)
+	if err != nil {
+		return nil, err
+	}
+	record, err := r.txm.Send(ctx, TxRequest{
+		From:      r.from,
+		To:        r.hackathon,
+		Data:      data,
+		Purpose:   relayPurpose,
+		Reference: reference,
+	})
+	if errors.Is(err, ErrGasEstimation) {
+		return nil, fmt.Errorf("%w: %v", ErrCheckInReverted, err)
+	}
+	return record, err
+}
+
+// Transaction 获取中继交易记录
+func (r *Relayer) Transaction(id uint) (*models.ChainTransaction, error) {
+	return r.txm.GetTransaction(id)
+}
+
+// FindTransaction 根据业务记录标识查找已发送的中继交易
+func (r *Relayer) FindTransaction(reference string) (*models.ChainTransaction, error) {
+	return r.txm.FindTransaction(reference)
+}
+
+func (r *Relayer) packCheckIn(call CheckInCall) ([]byte, error) {
+	tokenID := call.TokenID
+	if tokenID == nil {
+		tokenID = big.NewInt(0)
+	}
+	data, err := r.abi.Pack("checkInParticipant", call.EventID, call.Participant, tokenID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack checkInParticipant: %w", err)
+	}
+	return data, nil
+}
+
+// InitRelayer 根据配置创建中继器，未启用时返回 nil
+func InitRelayer(cfg *config.Config, bc *BlockchainClient, txm *TxManager) (*Relayer, error) {
+	if !cfg.RelayerEnabled {
+		return nil, nil
+	}
+	if cfg.RelayerPrivateKey == "" {
+		return nil, fmt.Errorf("RELAYER_PRIVATE_KEY is required when the relayer is enabled")
+	}
+	key, err := crypto.HexToECDSA(strings.TrimPrefix(cfg.RelayerPrivateKey, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid RELAYER_PRIVATE_KEY: %w", err)
+	}
+
+	relayer, err := NewRelayer(txm, key, bc.GetHackathonAddress())
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("🛰️ Check-in relayer enabled: %s", relayer.Address().Hex())
+	return relayer, nil
+}
@@ -0,0 +1,608 @@
+package blockchain
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"hackathon-backend/config"
+	"hackathon-backend/models"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const txPendingBatchSize = 100
+
+var (
+	// ErrUnknownSigner 交易管理器中没有该地址的私钥
+	ErrUnknownSigner = errors.New("unknown signer")
+	// ErrGasEstimation 预估 gas 失败，通常表示交易会回滚
+	ErrGasEstimation = errors.New("gas estimation failed")
+	// ErrBroadcastFailed 节点拒绝了交易，nonce 已释放
+	ErrBroadcastFailed = errors.New("transaction rejected by node")
+)
+
+// TxBackend 交易管理器需要的链上接口，ethclient.Client 和 backends.SimulatedBackend 均满足
+type TxBackend interface {
+	bind.ContractBackend
+	NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error)
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+}
+
+// TxStore 交易持久化接口，由 repositories.TransactionRepository 实现
+type TxStore interface {
+	CreateTransaction(tx *models.ChainTransaction) error
+	UpdateTransaction(tx *models.ChainTransaction) error
+	GetTransaction(id uint) (*models.ChainTransaction, error)
+	FindTransactionByReference(chainID uint64, reference string) (*models.ChainTransaction, error)
+	MaxNonce(chainID uint64, from string) (*uint64, error)
+	GetPendingTransactions(chainID uint64, limit int) ([]models.ChainTransaction, error)
+}
+
+// FeePolicy gas 与手续费策略
+type FeePolicy struct {
+	GasLimitBuffer    int64         // 预估 gas 上浮百分比，如 120
+	BaseFeeMultiplier int64         // maxFeePerGas = baseFee * multiplier + tip
+	MaxFeeCap         *big.Int      // maxFeePerGas（legacy 为 gasPrice）上限，nil 表示不限制
+	BumpPercent       int64         // 重发时手续费上调百分比
+	StuckAfter        time.Duration // 广播后超过该时间未打包则加价重发
+}
+
+// DefaultFeePolicy 各网络的默认策略
+func DefaultFeePolicy(chainID uint64) FeePolicy {
+	policy := FeePolicy{
+		GasLimitBuffer:    120,
+		BaseFeeMultiplier: 2,
+		BumpPercent:       15,
+		StuckAfter:        time.Minute,
+	}
+	switch chainID {
+	case 10143:
+		// Monad 按 gas limit 而不是实际用量收费，缓冲不宜过大
+		policy.GasLimitBuffer = 110
+	case 5003:
+		// Mantle 出块快，卡住的交易更早重发
+		policy.StuckAfter = 30 * time.Second
+	}
+	return policy
+}
+
+// TxRequest 待发送的交易
+type TxRequest struct {
+	From      common.Address
+	To        common.Address
+	Value     *big.Int
+	Data      []byte
+	Purpose   string // 用途，如 relay_checkin
+	Reference string // 业务记录标识，用于重启后找回已发送的交易
+}
+
+type txSigner struct {
+	key *ecdsa.PrivateKey
+	// 同一签名地址的 nonce 分配与重发串行执行
+	mu sync.Mutex
+}
+
+// TxManager 为后端的写操作分配 nonce、预估 gas 与手续费、加价重发卡住的交易，并持久化每笔交易及其回执状态。
+// 交易在广播前写入数据库，重启后从数据库恢复 nonce 并重新广播未确认的交易，不会产生 nonce 空洞
+type TxManager struct {
+	backend      TxBackend
+	store        TxStore
+	chainID      *big.Int
+	network      string
+	policy       FeePolicy
+	pollInterval time.Duration
+
+	mu      sync.RWMutex
+	signers map[common.Address]*txSigner
+
+	// 启动后的第一轮检查重新广播所有未确认交易（节点可能已丢弃）
+	rebroadcast bool
+}
+
+func NewTxManager(backend TxBackend, store TxStore, chainID *big.Int, network string, policy FeePolicy, pollInterval time.Duration) *TxManager {
+	if policy.BumpPercent < 10 {
+		policy.BumpPercent = 10 // 节点要求替换交易的手续费至少上调 10%
+	}
+	if pollInterval <= 0 {
+		pollInterval = 5 * time.Second
+	}
+	return &TxManager{
+		backend:      backend,
+		store:        store,
+		chainID:      chainID,
+		network:      network,
+		policy:       policy,
+		pollInterval: pollInterval,
+		signers:      map[common.Address]*txSigner{},
+		rebroadcast:  true,
+	}
+}
+
+// InitTxManager 根据配置为当前活动网络创建交易管理器
+func InitTxManager(cfg *config.Config, bc *BlockchainClient, store TxStore) *TxManager {
+	policy := DefaultFeePolicy(cfg.GetActiveChainID())
+	if cfg.TxBumpPercent > 0 {
+		policy.BumpPercent = int64(cfg.TxBumpPercent)
+	}
+	if cfg.TxStuckAfter > 0 {
+		policy.StuckAfter = time.Duration(cfg.TxStuckAfter) * time.Second
+	}
+	if maxFee := cfg.GetActiveTxMaxFeeGwei(); maxFee > 0 {
+		policy.MaxFeeCap = new(big.Int).Mul(big.NewInt(int64(maxFee)), big.NewInt(1e9))
+	}
+
+	return NewTxManager(bc.GetHTTPClient(), store, new(big.Int).SetUint64(cfg.GetActiveChainID()), cfg.GetActiveNetworkName(),
+		policy, time.Duration(cfg.TxPollInterval)*time.Second)
+}
+
+// AddSigner 注册签名私钥
+func (m *TxManager) AddSigner(key *ecdsa.PrivateKey) common.Address {
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.signers[addr]; !ok {
+		m.signers[addr] = &txSigner{key: key}
+	}
+	return addr
+}
+
+// Backend 链上接口
+func (m *TxManager) Backend() TxBackend {
+	return m.backend
+}
+
+// GetTransaction 获取交易记录
+func (m *TxManager) GetTransaction(id uint) (*models.ChainTransaction, error) {
+	return m.store.GetTransaction(id)
+}
+
+// FindTransaction 根据业务记录标识查找最近一笔交易
+func (m *TxManager) FindTransaction(reference string) (*models.ChainTransaction, error) {
+	return m.store.FindTransactionByReference(m.chainID.Uint64(), reference)
+}
+
+func (m *TxManager) signer(addr common.Address) (*txSigner, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	s, ok := m.signers[addr]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownSigner, addr.Hex())
+	}
+	return s, nil
+}
+
+// Send 预估 gas 与手续费、分配 nonce、签名并持久化后广播交易。
+// 节点拒绝时释放 nonce 并返回 ErrBroadcastFailed；网络错误时保留为 pending，由后台任务重新广播
+func (m *TxManager) Send(ctx context.Context, req TxRequest) (*models.ChainTransaction, error) {
+	signer, err := m.signer(req.From)
+	if err != nil {
+		return nil, err
+	}
+	signer.mu.Lock()
+	defer signer.mu.Unlock()
+
+	value := req.Value
+	if value == nil {
+		value = big.NewInt(0)
+	}
+
+	gas, err := m.backend.EstimateGas(ctx, ethereum.CallMsg{From: req.From, To: &req.To, Value: value, Data: req.Data})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrGasEstimation, err)
+	}
+	gas = gas * uint64(m.policy.GasLimitBuffer) / 100
+
+	fees, err := m.suggestFees(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce, err := m.nextNonce(ctx, req.From)
+	if err != nil {
+		return nil, err
+	}
+
+	record := &models.ChainTransaction{
+		ChainID:     m.chainID.Uint64(),
+		Network:     m.network,
+		FromAddress: req.From.Hex(),
+		Nonce:       &nonce,
+		ToAddress:   req.To.Hex(),
+		Value:       value.String(),
+		Data:        hexutil.Encode(req.Data),
+		Gas:         gas,
+		Status:      models.TxStatusPending,
+		Purpose:     req.Purpose,
+		Reference:   req.Reference,
+	}
+	fees.apply(record)
+
+	tx, err := m.sign(signer, record)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.recordBroadcast(record, tx); err != nil {
+		return nil, err
+	}
+	if err := m.store.CreateTransaction(record); err != nil {
+		return nil, fmt.Errorf("failed to save transaction: %w", err)
+	}
+
+	if err := m.backend.SendTransaction(ctx, tx); err != nil && !isAlreadyKnown(err) {
+		if !isNodeRejection(err) {
+			log.Printf("⚠️ Broadcast of tx %s (nonce %d) failed, will retry: %v", record.TxHash, nonce, err)
+			return record, nil
+		}
+		record.Status = models.TxStatusFailed
+		record.Nonce = nil
+		record.Error = err.Error()
+		if err := m.store.UpdateTransaction(record); err != nil {
+			return nil, fmt.Errorf("failed to save transaction: %w", err)
+		}
+		return record, fmt.Errorf("%w: %v", ErrBroadcastFailed, err)
+	}
+
+	log.Printf("📤 Sent tx %s from %s (nonce %d, %s)", record.TxHash, record.FromAddress, nonce, record.Purpose)
+	return record, nil
+}
+
+// nextNonce 取节点 pending nonce 与数据库中已占用的最大 nonce+1 的较大值，
+// 节点丢弃了已记录的交易时不会复用其 nonce（由后台任务重新广播补齐）
+func (m *TxManager) nextNonce(ctx context.Context, from common.Address) (uint64, error) {
+	nonce, err := m.backend.PendingNonceAt(ctx, from)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get pending nonce: %w", err)
+	}
+	stored, err := m.store.MaxNonce(m.chainID.Uint64(), from.Hex())
+	if err != nil {
+		return 0, fmt.Errorf("failed to load stored nonce: %w", err)
+	}
+	if stored != nil && *stored+1 > nonce {
+		nonce = *stored + 1
+	}
+	return nonce, nil
+}
+
+// txFees 交易手续费，legacy 交易只使用 gasPrice
+type txFees struct {
+	legacy   bool
+	gasPrice *big.Int
+	tipCap   *big.Int
+	feeCap   *big.Int
+}
+
+func (f txFees) apply(record *models.ChainTransaction) {
+	if f.legacy {
+		record.TxType = types.LegacyTxType
+		record.GasPrice = f.gasPrice.String()
+		record.GasTipCap, record.GasFeeCap = "", ""
+		return
+	}
+	record.TxType = types.DynamicFeeTxType
+	record.GasPrice = ""
+	record.GasTipCap = f.tipCap.String()
+	record.GasFeeCap = f.feeCap.String()
+}
+
+// suggestFees 根据最新区块的 baseFee 计算 EIP-1559 手续费，网络不支持时使用 legacy gasPrice
+func (m *TxManager) suggestFees(ctx context.Context) (txFees, error) {
+	head, err := m.backend.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return txFees{}, fmt.Errorf("failed to get latest header: %w", err)
+	}
+
+	if head.BaseFee == nil {
+		gasPrice, err := m.backend.SuggestGasPrice(ctx)
+		if err != nil {
+			return txFees{}, fmt.Errorf("failed to suggest gas price: %w", err)
+		}
+		return txFees{legacy: true, gasPrice: m.capFee(gasPrice)}, nil
+	}
+
+	tip, err := m.backend.SuggestGasTipCap(ctx)
+	if err != nil {
+		return txFees{}, fmt.Errorf("failed to suggest gas tip cap: %w", err)
+	}
+	feeCap := new(big.Int).Mul(head.BaseFee, big.NewInt(m.policy.BaseFeeMultiplier))
+	feeCap = m.capFee(feeCap.Add(feeCap, tip))
+	if tip.Cmp(feeCap) > 0 {
+		tip = new(big.Int).Set(feeCap)
+	}
+	return txFees{tipCap: tip, feeCap: feeCap}, nil
+}
+
+// capFee 应用 MaxFeeCap 上限
+func (m *TxManager) capFee(fee *big.Int) *big.Int {
+	if m.policy.MaxFeeCap != nil && fee.Cmp(m.policy.MaxFeeCap) > 0 {
+		return new(big.Int).Set(m.policy.MaxFeeCap)
+	}
+	return fee
+}
+
+// bumpFee 按 BumpPercent 上调手续费，且不低于当前建议值
+func (m *TxManager) bumpFee(old *big.Int, suggested *big.Int) *big.Int {
+	bumped := new(big.Int).Mul(old, big.NewInt(100+m.policy.BumpPercent))
+	bumped.Div(bumped, big.NewInt(100))
+	if bumped.Cmp(old) <= 0 {
+		bumped.Add(old, big.NewInt(1))
+	}
+	if suggested != nil && suggested.Cmp(bumped) > 0 {
+		bumped = new(big.Int).Set(suggested)
+	}
+	return m.capFee(bumped)
+}
+
+// sign 根据记录中的字段构造并签名交易
+func (m *TxManager) sign(signer *txSigner, record *models.ChainTransaction) (*types.Transaction, error) {
+	to := common.HexToAddress(record.ToAddress)
+	value, _ := new(big.Int).SetString(record.Value, 10)
+	data, err := hexutil.Decode(record.Data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid transaction data: %w", err)
+	}
+
+	var txData types.TxData
+	if record.TxType == types.DynamicFeeTxType {
+		tipCap, _ := new(big.Int).SetString(record.GasTipCap, 10)
+		feeCap, _ := new(big.Int).SetString(record.GasFeeCap, 10)
+		txData = &types.DynamicFeeTx{
+			ChainID:   m.chainID,
+			Nonce:     *record.Nonce,
+			GasTipCap: tipCap,
+			GasFeeCap: feeCap,
+			Gas:       record.Gas,
+			To:        &to,
+			Value:     value,
+			Data:      data,
+		}
+	} else {
+		gasPrice, _ := new(big.Int).SetString(record.GasPrice, 10)
+		txData = &types.LegacyTx{
+			Nonce:    *record.Nonce,
+			GasPrice: gasPrice,
+			Gas:      record.Gas,
+			To:       &to,
+			Value:    value,
+			Data:     data,
+		}
+	}
+
+	tx, err := types.SignNewTx(signer.key, types.LatestSignerForChainID(m.chainID), txData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign transaction: %w", err)
+	}
+	return tx, nil
+}
+
+// recordBroadcast 记录即将广播的签名交易
+func (m *TxManager) recordBroadcast(record *models.ChainTransaction, tx *types.Transaction) error {
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		return fmt.Errorf("failed to encode transaction: %w", err)
+	}
+	now := time.Now()
+	record.AddHash(tx.Hash().Hex())
+	record.RawTx = hexutil.Encode(raw)
+	record.Attempts++
+	record.LastSentAt = &now
+	return nil
+}
+
+// Run 定时查询未确认交易的回执，重新广播或加价重发卡住的交易
+func (m *TxManager) Run(ctx context.Context) {
+	log.Printf("📤 Transaction manager started (stuck after %s, bump %d%%)", m.policy.StuckAfter, m.policy.BumpPercent)
+
+	ticker := time.NewTicker(m.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := m.Check(ctx); err != nil {
+			log.Printf("❌ Transaction check failed: %v", err)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Check 执行一轮未确认交易检查
+func (m *TxManager) Check(ctx context.Context) error {
+	txs, err := m.store.GetPendingTransactions(m.chainID.Uint64(), txPendingBatchSize)
+	if err != nil {
+		return err
+	}
+
+	rebroadcast := m.rebroadcast
+	for i := range txs {
+		rpcCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		err := m.checkPending(rpcCtx, &txs[i], rebroadcast)
+		cancel()
+		if err != nil {
+			return fmt.Errorf("tx %d: %w", txs[i].ID, err)
+		}
+	}
+	m.rebroadcast = false
+	return nil
+}
+
+// checkPending 检查一笔未确认交易：任一广播过的哈希有回执则结算；nonce 已被占用则标记为 replaced；
+// 否则在重启后重新广播，或超过 StuckAfter 后加价重发
+func (m *TxManager) checkPending(ctx context.Context, record *models.ChainTransaction, rebroadcast bool) error {
+	for _, hash := range record.HashList() {
+		receipt, err := m.backend.TransactionReceipt(ctx, common.HexToHash(hash))
+		if errors.Is(err, ethereum.NotFound) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get receipt: %w", err)
+		}
+		return m.settle(record, hash, receipt)
+	}
+
+	from := common.HexToAddress(record.FromAddress)
+	confirmed, err := m.backend.NonceAt(ctx, from, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get nonce: %w", err)
+	}
+	if record.Nonce != nil && confirmed > *record.Nonce {
+		// 回执可能比 nonce 晚一个区块可见，下一轮再确认
+		if record.LastSentAt != nil && time.Since(*record.LastSentAt) < m.pollInterval*2 {
+			return nil
+		}
+		record.Status = models.TxStatusReplaced
+		record.Error = fmt.Sprintf("nonce %d was used by another transaction", *record.Nonce)
+		log.Printf("⚠️ Tx %s (nonce %d) was replaced by another transaction", record.TxHash, *record.Nonce)
+		return m.store.UpdateTransaction(record)
+	}
+
+	stuck := record.LastSentAt == nil || time.Since(*record.LastSentAt) >= m.policy.StuckAfter
+	switch {
+	case stuck:
+		return m.resubmit(ctx, record)
+	case rebroadcast:
+		return m.broadcastRaw(ctx, record)
+	}
+	return nil
+}
+
+// settle 根据回执更新交易状态
+func (m *TxManager) settle(record *models.ChainTransaction, hash string, receipt *types.Receipt) error {
+	now := time.Now()
+	record.TxHash = hash
+	record.GasUsed = receipt.GasUsed
+	if receipt.BlockNumber != nil {
+		record.BlockNumber = receipt.BlockNumber.Uint64()
+	}
+	if receipt.EffectiveGasPrice != nil {
+		record.EffectiveGasPrice = receipt.EffectiveGasPrice.String()
+	}
+	record.ConfirmedAt = &now
+	if receipt.Status == types.ReceiptStatusSuccessful {
+		record.Status = models.TxStatusConfirmed
+		log.Printf("✅ Tx %s confirmed in block %d", hash, record.BlockNumber)
+	} else {
+		record.Status = models.TxStatusReverted
+		record.Error = "execution reverted"
+		log.Printf("⚠️ Tx %s reverted in block %d", hash, record.BlockNumber)
+	}
+	return m.store.UpdateTransaction(record)
+}
+
+// broadcastRaw 重新广播当前签名交易
+func (m *TxManager) broadcastRaw(ctx context.Context, record *models.ChainTransaction) error {
+	raw, err := hexutil.Decode(record.RawTx)
+	if err != nil {
+		return fmt.Errorf("invalid raw transaction: %w", err)
+	}
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(raw); err != nil {
+		return fmt.Errorf("invalid raw transaction: %w", err)
+	}
+	if err := m.backend.SendTransaction(ctx, tx); err != nil && !isAlreadyKnown(err) {
+		log.Printf("⚠️ Rebroadcast of tx %s failed: %v", record.TxHash, err)
+	}
+	return nil
+}
+
+// resubmit 以相同 nonce 加价重发；没有该签名地址的私钥或手续费已达上限时只重新广播
+func (m *TxManager) resubmit(ctx context.Context, record *models.ChainTransaction) error {
+	signer, err := m.signer(common.HexToAddress(record.FromAddress))
+	if err != nil {
+		return m.broadcastRaw(ctx, record)
+	}
+	signer.mu.Lock()
+	defer signer.mu.Unlock()
+
+	suggested, err := m.suggestFees(ctx)
+	if err != nil {
+		return err
+	}
+
+	var bumped txFees
+	if record.TxType == types.DynamicFeeTxType {
+		oldTip, _ := new(big.Int).SetString(record.GasTipCap, 10)
+		oldFeeCap, _ := new(big.Int).SetString(record.GasFeeCap, 10)
+		bumped.tipCap = m.bumpFee(oldTip, suggested.tipCap)
+		bumped.feeCap = m.bumpFee(oldFeeCap, suggested.feeCap)
+		if bumped.tipCap.Cmp(bumped.feeCap) > 0 {
+			bumped.tipCap = new(big.Int).Set(bumped.feeCap)
+		}
+		if bumped.tipCap.Cmp(oldTip) <= 0 || bumped.feeCap.Cmp(oldFeeCap) <= 0 {
+			log.Printf("⚠️ Tx %s reached the fee cap, rebroadcasting without bump", record.TxHash)
+			record.LastSentAt = timePtr(time.Now())
+			if err := m.store.UpdateTransaction(record); err != nil {
+				return err
+			}
+			return m.broadcastRaw(ctx, record)
+		}
+	} else {
+		oldPrice, _ := new(big.Int).SetString(record.GasPrice, 10)
+		bumped.legacy = true
+		bumped.gasPrice = m.bumpFee(oldPrice, suggested.gasPrice)
+		if bumped.gasPrice.Cmp(oldPrice) <= 0 {
+			log.Printf("⚠️ Tx %s reached the fee cap, rebroadcasting without bump", record.TxHash)
+			record.LastSentAt = timePtr(time.Now())
+			if err := m.store.UpdateTransaction(record); err != nil {
+				return err
+			}
+			return m.broadcastRaw(ctx, record)
+		}
+	}
+	bumped.apply(record)
+
+	tx, err := m.sign(signer, record)
+	if err != nil {
+		return err
+	}
+	if err := m.recordBroadcast(record, tx); err != nil {
+		return err
+	}
+	// 先保存新哈希再广播，避免重启后丢失对替换交易的跟踪
+	if err := m.store.UpdateTransaction(record); err != nil {
+		return err
+	}
+	if err := m.backend.SendTransaction(ctx, tx); err != nil && !isAlreadyKnown(err) {
+		log.Printf("⚠️ Resubmit of tx %s (nonce %d) failed: %v", record.TxHash, *record.Nonce, err)
+		return nil
+	}
+
+	log.Printf("🔁 Resubmitted tx %s (nonce %d, attempt %d)", record.TxHash, *record.Nonce, record.Attempts)
+	return nil
+}
+
+// isAlreadyKnown 节点已有相同交易
+func isAlreadyKnown(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "already known") || strings.Contains(msg, "already imported")
+}
+
+// isNodeRejection 节点明确拒绝了交易（而不是网络错误），此时交易一定没有进入交易池
+func isNodeRejection(err error) bool {
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) {
+		return true
+	}
+	// SimulatedBackend 等本地后端直接返回普通错误
+	var netErr interface{ Timeout() bool }
+	return !errors.As(err, &netErr) && !errors.Is(err, context.DeadlineExceeded) && !errors.Is(err, context.Canceled)
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
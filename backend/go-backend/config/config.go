@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
@@ -42,8 +43,63 @@ type Config struct {
 	NFTTicketContractAddress string
 
 	// Server
-	ServerPort   int
-	SyncInterval int
+	ServerPort     int
+	SyncInterval   int
+	FrontendURL    string // 前端地址，用于日历和订阅源中的活动链接
+	PublicAPIURL   string // 后端对外地址，用于订阅源的 self 链接
+	AllowedOrigins string // 允许跨域访问和建立 WebSocket 连接的前端来源，逗号分隔，为空时取 FrontendURL
+
+	// Sync mode
+	SyncMode     string // auto, websocket, polling
+	PollInterval int    // 轮询间隔（秒）
+
+	// Health check
+	HealthMaxLagBlocks  int // 索引延迟超过该区块数时 readiness 失败
+	HealthMaxLogSilence int // 超过该秒数未收到日志时 readiness 失败，0 表示不检查
+	HealthCheckTimeout  int // 数据库/RPC 探测超时（秒）
+
+	// Sync log retention
+	SyncLogSuccessRetentionDays int    // 成功日志保留天数
+	SyncLogFailureRetentionDays int    // 失败日志保留天数
+	SyncLogCompactInterval      int    // 压缩任务间隔（分钟），0 表示禁用
+	SyncLogArchiveDir           string // 删除前归档目录，为空则不归档
+
+	// Webhook
+	WebhookMaxAttempts  int  // 最大投递次数（含首次）
+	WebhookRetryBase    int  // 重试退避基数（秒），每次翻倍
+	WebhookTimeout      int  // 单次请求超时（秒）
+	WebhookPollInterval int  // 扫描待重试投递的间隔（秒）
+	WebhookAllowPrivate bool // 是否允许投递到回环、内网和链路本地地址（仅用于本地开发）
+
+	// Auth (Sign-In with Ethereum)
+	AuthJWTSecret      string // JWT 签名密钥，为空时每次启动随机生成
+	AuthTokenTTL       int    // 登录令牌有效期（秒）
+	AuthNonceTTL       int    // nonce 有效期（秒）
+	AuthAllowedDomains string // 允许的 SIWE 消息 domain，逗号分隔
+	AuthAdminAddresses string // 管理员钱包地址，逗号分隔
+
+	// Check-in
+	CheckInSignerKey      string // 签发门票二维码的服务端私钥（十六进制），为空时每次启动随机生成
+	CheckInPassTTL        int    // 门票二维码有效期（秒）
+	CheckInVerifyOnChain  bool   // 核验时是否调用合约 isTicketValid 复核
+	OfflineSettleInterval int    // 离线签到交给中继器上链的间隔（秒）
+
+	// Relayer
+	RelayerEnabled      bool   // 是否启用签到中继
+	RelayerPrivateKey   string // 组织者委托给中继器的私钥（十六进制）
+	RelayerPollInterval int    // 提交排队交易和查询回执的间隔（秒）
+
+	// Transaction manager
+	TxPollInterval     int // 查询回执和检查卡住交易的间隔（秒）
+	TxStuckAfter       int // 交易广播后超过该秒数未打包则加价重发，0 表示使用网络默认值
+	TxBumpPercent      int // 重发时手续费上调百分比（节点要求至少 10）
+	TxMonadMaxFeeGwei  int // 各网络 maxFeePerGas 上限（gwei），0 表示不限制
+	TxMantleMaxFeeGwei int
+	TxSomniaMaxFeeGwei int
+
+	// Stats rollup
+	StatsRollupInterval     int // 平台统计汇总任务间隔（分钟），0 表示禁用
+	StatsRollupLookbackDays int // 每次重新计算最近多少天内的周期
 
 	// Log
 	LogLevel string
@@ -90,8 +146,63 @@ func LoadConfig() *Config {
 		NFTTicketContractAddress: getEnv("NFT_TICKET_CONTRACT_ADDRESS", ""),
 
 		// Server
-		ServerPort:   getEnvInt("SERVER_PORT", 8080),
-		SyncInterval: getEnvInt("SYNC_INTERVAL", 30),
+		ServerPort:     getEnvInt("SERVER_PORT", 8080),
+		SyncInterval:   getEnvInt("SYNC_INTERVAL", 30),
+		FrontendURL:    strings.TrimRight(getEnv("FRONTEND_URL", "http://localhost:5173"), "/"),
+		PublicAPIURL:   strings.TrimRight(getEnv("PUBLIC_API_URL", "http://localhost:8080"), "/"),
+		AllowedOrigins: getEnv("CORS_ALLOWED_ORIGINS", ""),
+
+		// Sync mode
+		SyncMode:     getEnv("SYNC_MODE", "auto"),
+		PollInterval: getEnvInt("POLL_INTERVAL", 5),
+
+		// Health check
+		HealthMaxLagBlocks:  getEnvInt("HEALTH_MAX_LAG_BLOCKS", 100),
+		HealthMaxLogSilence: getEnvInt("HEALTH_MAX_LOG_SILENCE", 0),
+		HealthCheckTimeout:  getEnvInt("HEALTH_CHECK_TIMEOUT", 3),
+
+		// Sync log retention
+		SyncLogSuccessRetentionDays: getEnvInt("SYNC_LOG_SUCCESS_RETENTION_DAYS", 7),
+		SyncLogFailureRetentionDays: getEnvInt("SYNC_LOG_FAILURE_RETENTION_DAYS", 30),
+		SyncLogCompactInterval:      getEnvInt("SYNC_LOG_COMPACT_INTERVAL", 60),
+		SyncLogArchiveDir:           getEnv("SYNC_LOG_ARCHIVE_DIR", "archive/sync_logs"),
+
+		// Webhook
+		WebhookMaxAttempts:  getEnvInt("WEBHOOK_MAX_ATTEMPTS", 8),
+		WebhookRetryBase:    getEnvInt("WEBHOOK_RETRY_BASE", 10),
+		WebhookTimeout:      getEnvInt("WEBHOOK_TIMEOUT", 10),
+		WebhookPollInterval: getEnvInt("WEBHOOK_POLL_INTERVAL", 5),
+		WebhookAllowPrivate: getEnvBool("WEBHOOK_ALLOW_PRIVATE", false),
+
+		// Auth (Sign-In with Ethereum)
+		AuthJWTSecret:      getEnv("AUTH_JWT_SECRET", ""),
+		AuthTokenTTL:       getEnvInt("AUTH_TOKEN_TTL", 86400),
+		AuthNonceTTL:       getEnvInt("AUTH_NONCE_TTL", 300),
+		AuthAllowedDomains: getEnv("AUTH_ALLOWED_DOMAINS", "localhost:5173,localhost:3000"),
+		AuthAdminAddresses: getEnv("AUTH_ADMIN_ADDRESSES", ""),
+
+		// Check-in
+		CheckInSignerKey:      getEnv("CHECKIN_SIGNER_KEY", ""),
+		CheckInPassTTL:        getEnvInt("CHECKIN_PASS_TTL", 600),
+		CheckInVerifyOnChain:  getEnvBool("CHECKIN_VERIFY_ONCHAIN", false),
+		OfflineSettleInterval: getEnvInt("OFFLINE_SETTLE_INTERVAL", 15),
+
+		// Relayer
+		RelayerEnabled:      getEnvBool("RELAYER_ENABLED", false),
+		RelayerPrivateKey:   getEnv("RELAYER_PRIVATE_KEY", ""),
+		RelayerPollInterval: getEnvInt("RELAYER_POLL_INTERVAL", 3),
+
+		// Transaction manager
+		TxPollInterval:     getEnvInt("TX_POLL_INTERVAL", 5),
+		TxStuckAfter:       getEnvInt("TX_STUCK_AFTER", 0),
+		TxBumpPercent:      getEnvInt("TX_BUMP_PERCENT", 15),
+		TxMonadMaxFeeGwei:  getEnvInt("MONAD_TX_MAX_FEE_GWEI", 0),
+		TxMantleMaxFeeGwei: getEnvInt("MANTLE_TX_MAX_FEE_GWEI", 0),
+		TxSomniaMaxFeeGwei: getEnvInt("SOMNIA_TX_MAX_FEE_GWEI", 0),
+
+		// Stats rollup
+		StatsRollupInterval:     getEnvInt("STATS_ROLLUP_INTERVAL", 15),
+		StatsRollupLookbackDays: getEnvInt("STATS_ROLLUP_LOOKBACK_DAYS", 35),
 
 		// Log
 		LogLevel: getEnv("LOG_LEVEL", "info"),
@@ -159,6 +270,18 @@ func (c *Config) GetActiveNFTTicketAddress() string {
 	}
 }
 
+// GetActiveTxMaxFeeGwei returns the maxFeePerGas cap (gwei) for the active network, 0 means unlimited
+func (c *Config) GetActiveTxMaxFeeGwei() int {
+	switch c.ActiveNetwork {
+	case "mantle":
+		return c.TxMantleMaxFeeGwei
+	case "somnia":
+		return c.TxSomniaMaxFeeGwei
+	default:
+		return c.TxMonadMaxFeeGwei
+	}
+}
+
 // GetActiveChainID returns the Chain ID for the active network
 func (c *Config) GetActiveChainID() uint64 {
 	switch c.ActiveNetwork {
@@ -171,11 +294,81 @@ func (c *Config) GetActiveChainID() uint64 {
 	}
 }
 
+// ChainIDForNetwork returns the Chain ID of a network name
+func ChainIDForNetwork(network string) (uint64, bool) {
+	switch network {
+	case "monad":
+		return 10143, true
+	case "mantle":
+		return 5003, true
+	case "somnia":
+		return 50312, true
+	default:
+		return 0, false
+	}
+}
+
+// IsSupportedChainID reports whether the chain ID belongs to a configured network
+func (c *Config) IsSupportedChainID(chainID uint64) bool {
+	switch chainID {
+	case 10143, 5003, 50312:
+		return true
+	default:
+		return false
+	}
+}
+
 // GetActiveNetworkName returns the network name for the active network
 func (c *Config) GetActiveNetworkName() string {
 	return c.ActiveNetwork
 }
 
+// EventURL returns the frontend page of an event
+func (c *Config) EventURL(eventID string) string {
+	return c.FrontendURL + "/events/" + eventID
+}
+
+// IsAllowedOrigin reports whether a browser Origin may call the API (CORS) or open a WebSocket;
+// CORS_ALLOWED_ORIGINS falls back to FRONTEND_URL when empty
+func (c *Config) IsAllowedOrigin(origin string) bool {
+	allowed := c.AllowedOrigins
+	if strings.TrimSpace(allowed) == "" {
+		allowed = c.FrontendURL
+	}
+	origin = strings.TrimRight(origin, "/")
+	for _, candidate := range strings.Split(allowed, ",") {
+		if candidate = strings.TrimRight(strings.TrimSpace(candidate), "/"); candidate != "" && strings.EqualFold(candidate, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// NativeTokenSymbol returns the native token symbol of a network (sponsor amounts are stored in its 18-decimal base unit)
+func NativeTokenSymbol(network string) string {
+	switch network {
+	case "mantle":
+		return "MNT"
+	case "somnia":
+		return "STT"
+	case "monad":
+		return "MON"
+	default:
+		return strings.ToUpper(network)
+	}
+}
+
+// Redacted 返回隐藏了密码和私钥的副本，用于打印日志
+func (c *Config) Redacted() Config {
+	redacted := *c
+	for _, secret := range []*string{&redacted.DBPassword, &redacted.AuthJWTSecret, &redacted.CheckInSignerKey, &redacted.RelayerPrivateKey} {
+		if *secret != "" {
+			*secret = "***"
+		}
+	}
+	return redacted
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -191,3 +384,12 @@ func getEnvInt(key string, defaultValue int) int {
 	}
 	return defaultValue
 }
+
+func getEnvBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if boolVal, err := strconv.ParseBool(value); err == nil {
+			return boolVal
+		}
+	}
+	return defaultValue
+}
@@ -0,0 +1,47 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"hackathon-backend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AnalyticsController struct {
+	service *services.AnalyticsService
+	events  *services.EventService
+}
+
+func NewAnalyticsController(service *services.AnalyticsService, events *services.EventService) *AnalyticsController {
+	return &AnalyticsController{service: service, events: events}
+}
+
+// GetEventAnalytics 获取活动的报名、签到、赞助和门票统计，interval 可选 hour、day
+func (c *AnalyticsController) GetEventAnalytics(ctx *gin.Context) {
+	event, err := c.events.FindEventOnActiveChain(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if event == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Event not found"})
+		return
+	}
+
+	analytics, err := c.service.GetEventAnalytics(event, ctx.Query("interval"))
+	if errors.Is(err, services.ErrInvalidInterval) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"data": analytics,
+	})
+}
@@ -0,0 +1,97 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"hackathon-backend/middleware"
+	"hackathon-backend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AuthController struct {
+	service *services.AuthService
+}
+
+func NewAuthController(service *services.AuthService) *AuthController {
+	return &AuthController{service: service}
+}
+
+// GetNonce 签发 SIWE 登录 nonce
+func (c *AuthController) GetNonce(ctx *gin.Context) {
+	nonce, err := c.service.IssueNonce()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Header("Cache-Control", "no-store")
+	ctx.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"data": gin.H{
+			"nonce":      nonce.Nonce,
+			"expires_at": nonce.ExpiresAt,
+		},
+	})
+}
+
+// Login 校验 SIWE 消息和签名，签发绑定钱包与链的登录令牌
+func (c *AuthController) Login(ctx *gin.Context) {
+	var req struct {
+		Message   string `json:"message" binding:"required"`
+		Signature string `json:"signature" binding:"required"`
+	}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	session, err := c.service.Login(req.Message, req.Signature)
+	switch {
+	case errors.Is(err, services.ErrInvalidSiweMessage):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, services.ErrInvalidSignature), errors.Is(err, services.ErrInvalidNonce):
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	setSessionCookie(ctx, session.Token, time.Until(session.ExpiresAt))
+	ctx.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"data": session,
+	})
+}
+
+// Logout 清除会话 Cookie；令牌本身无状态，客户端需自行丢弃
+func (c *AuthController) Logout(ctx *gin.Context) {
+	setSessionCookie(ctx, "", -time.Second)
+	ctx.JSON(http.StatusOK, gin.H{
+		"code":    0,
+		"message": "logged out",
+	})
+}
+
+// Me 获取当前登录的钱包
+func (c *AuthController) Me(ctx *gin.Context) {
+	session := middleware.CurrentSession(ctx)
+	ctx.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"data": gin.H{
+			"address":    session.Address,
+			"chain_id":   session.ChainID,
+			"expires_at": session.ExpiresAt.Time,
+		},
+	})
+}
+
+// setSessionCookie 写入 HttpOnly 会话 Cookie，maxAge 为负时删除
+func setSessionCookie(ctx *gin.Context, token string, maxAge time.Duration) {
+	ctx.SetSameSite(http.SameSiteLaxMode)
+	ctx.SetCookie(middleware.SessionCookie, token, int(maxAge.Seconds()), "/", "", ctx.Request.TLS != nil, true)
+}
@@ -0,0 +1,81 @@
+package controllers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"hackathon-backend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CalendarController struct {
+	service *services.CalendarService
+	events  *services.EventService
+}
+
+func NewCalendarController(service *services.CalendarService, events *services.EventService) *CalendarController {
+	return &CalendarController{service: service, events: events}
+}
+
+// respondCalendar 输出 text/calendar
+func respondCalendar(ctx *gin.Context, calendar *services.Calendar, filename string) {
+	ctx.Header("Content-Type", "text/calendar; charset=utf-8")
+	ctx.Header("Content-Disposition", `inline; filename="`+filename+`"`)
+	ctx.Status(http.StatusOK)
+	if err := calendar.Write(ctx.Writer); err != nil {
+		log.Printf("❌ Failed to write calendar %s: %v", filename, err)
+	}
+}
+
+// GetEventsCalendar 活动日历订阅（即将开始和最近 30 天内结束的活动），支持 network, organizer 过滤
+func (c *CalendarController) GetEventsCalendar(ctx *gin.Context) {
+	organizer, err := parseOptionalAddress(ctx, "organizer")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	calendar, err := c.service.EventsCalendar(ctx.Query("network"), organizer)
+	if errors.Is(err, services.ErrUnknownNetwork) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	respondCalendar(ctx, calendar, "events.ics")
+}
+
+// GetEventCalendar 单个活动的日历（当前网络）
+func (c *CalendarController) GetEventCalendar(ctx *gin.Context) {
+	event, err := c.events.FindEventOnActiveChain(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if event == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Event not found"})
+		return
+	}
+
+	respondCalendar(ctx, c.service.EventCalendar(event), "event-"+event.EventID+".ics")
+}
+
+// GetWalletCalendar 钱包报名的活动日历，地址不区分大小写
+func (c *CalendarController) GetWalletCalendar(ctx *gin.Context) {
+	calendar, err := c.service.WalletCalendar(ctx.Param("address"))
+	if errors.Is(err, services.ErrInvalidAddress) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	respondCalendar(ctx, calendar, "registrations.ics")
+}
@@ -0,0 +1,138 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"hackathon-backend/middleware"
+	"hackathon-backend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CheckInController struct {
+	service *services.CheckInService
+	policy  *services.AuthPolicy
+}
+
+func NewCheckInController(service *services.CheckInService, policy *services.AuthPolicy) *CheckInController {
+	return &CheckInController{service: service, policy: policy}
+}
+
+// respondCheckInError 根据错误类型返回 400/403/404/409/422/500
+func respondCheckInError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrForbidden), errors.Is(err, services.ErrUnauthenticated):
+		middleware.AbortWithAuthError(ctx, err)
+	case errors.Is(err, services.ErrTicketNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrPassReplayed):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrInvalidPass), errors.Is(err, services.ErrPassExpired), errors.Is(err, services.ErrTicketUnusable):
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// IssuePass 为当前登录钱包持有的门票签发二维码
+func (c *CheckInController) IssuePass(ctx *gin.Context) {
+	var req struct {
+		Signer string `json:"signer"`
+	}
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	if req.Signer == "" {
+		req.Signer = ctx.DefaultQuery("signer", services.PassSignerServer)
+	}
+	if req.Signer != services.PassSignerServer && req.Signer != services.PassSignerHolder {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "signer must be server or holder"})
+		return
+	}
+
+	pass, err := c.service.IssuePass(middleware.CurrentSession(ctx), ctx.Param("tokenId"), req.Signer)
+	if err != nil {
+		respondCheckInError(ctx, err)
+		return
+	}
+
+	data := gin.H{
+		"pass":    pass,
+		"message": pass.Message(),
+	}
+	// 持有者签名模式下，客户端需用钱包对 message 签名后填入 signature 再生成二维码
+	if pass.Signature != "" {
+		qr, err := json.Marshal(pass)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		data["qr"] = string(qr)
+	}
+
+	ctx.Header("Cache-Control", "no-store")
+	ctx.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"data": data,
+	})
+}
+
+// VerifyPass 扫码核验门票（仅活动组织者或管理员），同一门票只能核验一次
+func (c *CheckInController) VerifyPass(ctx *gin.Context) {
+	var req struct {
+		QR      string               `json:"qr"`
+		Pass    *services.TicketPass `json:"pass"`
+		OnChain bool                 `json:"onchain"`
+	}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	pass := req.Pass
+	if req.QR != "" {
+		pass = &services.TicketPass{}
+		if err := json.Unmarshal([]byte(req.QR), pass); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid QR payload"})
+			return
+		}
+	}
+	if pass == nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "qr or pass is required"})
+		return
+	}
+
+	check, err := c.service.CheckPass(ctx.Request.Context(), pass, req.OnChain)
+	if err != nil {
+		respondCheckInError(ctx, err)
+		return
+	}
+
+	session := middleware.CurrentSession(ctx)
+	if err := c.policy.AuthorizeEventManagement(session, check.Event); err != nil {
+		middleware.AbortWithAuthError(ctx, err)
+		return
+	}
+
+	result, err := c.service.RedeemPass(check, session.Address)
+	if err != nil {
+		respondCheckInError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"data": gin.H{
+			"valid":       true,
+			"ticket":      result.Ticket,
+			"participant": result.Participant,
+			"signer":      result.Signer,
+			"verified_at": result.VerifiedAt,
+		},
+	})
+}
@@ -0,0 +1,25 @@
+package controllers
+
+import (
+	"net/http"
+
+	"hackathon-backend/apidocs"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DocsController struct{}
+
+func NewDocsController() *DocsController {
+	return &DocsController{}
+}
+
+// OpenAPISpec 返回 OpenAPI 3 文档
+func (c *DocsController) OpenAPISpec(ctx *gin.Context) {
+	ctx.Data(http.StatusOK, "application/json; charset=utf-8", apidocs.Spec)
+}
+
+// SwaggerUI 返回 Swagger UI 页面
+func (c *DocsController) SwaggerUI(ctx *gin.Context) {
+	ctx.Data(http.StatusOK, "text/html; charset=utf-8", []byte(apidocs.SwaggerUIHTML))
+}
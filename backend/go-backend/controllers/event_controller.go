@@ -1,9 +1,13 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
+	"strings"
 
+	"hackathon-backend/middleware"
 	"hackathon-backend/models"
+	"hackathon-backend/repositories"
 	"hackathon-backend/services"
 
 	"github.com/gin-gonic/gin"
@@ -11,23 +15,110 @@ import (
 
 type EventController struct {
 	service *services.EventService
+	policy  *services.AuthPolicy
 }
 
-func NewEventController(service *services.EventService) *EventController {
-	return &EventController{service: service}
+func NewEventController(service *services.EventService, policy *services.AuthPolicy) *EventController {
+	return &EventController{service: service, policy: policy}
 }
 
-// GetAllEvents 获取所有活动
+// parseEventFilter 解析活动列表过滤参数：network, organizer, active, starts_after, starts_before
+func parseEventFilter(ctx *gin.Context) (repositories.EventFilter, error) {
+	filter := repositories.EventFilter{
+		Network: ctx.Query("network"),
+	}
+
+	var err error
+	if filter.Organizer, err = parseOptionalAddress(ctx, "organizer"); err != nil {
+		return filter, err
+	}
+	if filter.Active, err = parseOptionalBool(ctx, "active"); err != nil {
+		return filter, err
+	}
+	if filter.StartsAfter, err = parseOptionalInt64(ctx, "starts_after"); err != nil {
+		return filter, err
+	}
+	if filter.StartsBefore, err = parseOptionalInt64(ctx, "starts_before"); err != nil {
+		return filter, err
+	}
+	return filter, nil
+}
+
+// GetAllEvents 分页获取活动
 func (c *EventController) GetAllEvents(ctx *gin.Context) {
-	events, err := c.service.GetAllEvents()
+	filter, err := parseEventFilter(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	page, err := parsePageQuery(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	events, err := c.service.ListEvents(filter, page)
+	if err != nil {
+		respondListError(ctx, err)
+		return
+	}
+
+	respondPage(ctx, events)
+}
+
+// SearchEvents 全文检索活动：q 匹配标题、描述和地点，location 模糊匹配城市，可组合活动列表的过滤参数
+func (c *EventController) SearchEvents(ctx *gin.Context) {
+	keyword := strings.TrimSpace(ctx.Query("q"))
+	location := strings.TrimSpace(ctx.Query("location"))
+	if keyword == "" && location == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "q or location is required"})
+		return
+	}
+
+	filter, err := parseEventFilter(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	page, err := parsePageQuery(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if page.Limit == 0 {
+		page.Limit = repositories.DefaultPageLimit
+	}
+
+	offset, err := parseOffsetCursor(page.Cursor)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	results, total, err := c.service.SearchEvents(services.EventSearchQuery{
+		Keyword:  keyword,
+		Location: location,
+		Filter:   filter,
+		Limit:    page.Limit,
+		Offset:   offset,
+	})
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{
-		"code": 0,
-		"data": events,
+	nextCursor := ""
+	if next := offset + len(results); int64(next) < total {
+		nextCursor = offsetCursor(next)
+	}
+
+	respondPage(ctx, &repositories.Page[services.EventSearchResult]{
+		Items:      results,
+		Total:      total,
+		Limit:      page.Limit,
+		NextCursor: nextCursor,
 	})
 }
 
@@ -51,27 +142,35 @@ func (c *EventController) GetEventByID(ctx *gin.Context) {
 	})
 }
 
-// GetEventsByOrganizer 根据组织者获取活动
+// GetEventsByOrganizer 根据组织者分页获取活动
 func (c *EventController) GetEventsByOrganizer(ctx *gin.Context) {
-	organizer := ctx.Query("organizer")
-	if organizer == "" {
+	filter, err := parseEventFilter(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if filter.Organizer == "" {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Organizer address required"})
 		return
 	}
 
-	events, err := c.service.GetEventsByOrganizer(organizer)
+	page, err := parsePageQuery(ctx)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{
-		"code": 0,
-		"data": events,
-	})
+	events, err := c.service.ListEvents(filter, page)
+	if err != nil {
+		respondListError(ctx, err)
+		return
+	}
+
+	respondPage(ctx, events)
 }
 
-// GetEventParticipants 获取活动的参与者
+// GetEventParticipants 分页获取活动的参与者，支持 network, checked_in 过滤；
+// 只有活动组织者和管理员能看到完整的姓名和钱包地址
 func (c *EventController) GetEventParticipants(ctx *gin.Context) {
 	eventID := ctx.Param("id")
 	if eventID == "" {
@@ -79,19 +178,113 @@ func (c *EventController) GetEventParticipants(ctx *gin.Context) {
 		return
 	}
 
-	participants, err := c.service.GetEventParticipants(eventID)
+	checkedIn, err := parseOptionalBool(ctx, "checked_in")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	page, err := parsePageQuery(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	participants, err := c.service.ListEventParticipants(repositories.ParticipantFilter{
+		EventID:   eventID,
+		Network:   ctx.Query("network"),
+		CheckedIn: checkedIn,
+	}, page)
+	if err != nil {
+		respondListError(ctx, err)
+		return
+	}
+
+	if err := c.redactParticipants(middleware.CurrentSession(ctx), participants.Items); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	respondPage(ctx, participants)
+}
+
+// redactParticipants 对无权管理所属活动的钱包隐藏参与者信息
+func (c *EventController) redactParticipants(session *services.SessionClaims, participants []models.Participant) error {
+	if len(participants) == 0 || c.policy.IsAdmin(session) {
+		return nil
+	}
+
+	var events map[repositories.EventKey]*models.Event
+	if session != nil {
+		var keys []repositories.EventKey
+		seen := map[repositories.EventKey]bool{}
+		for i := range participants {
+			key := repositories.EventKey{ChainID: participants[i].ChainID, EventID: participants[i].EventID}
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+
+		found, err := c.service.GetEventsByKeys(keys)
+		if err != nil {
+			return err
+		}
+		events = make(map[repositories.EventKey]*models.Event, len(found))
+		for i := range found {
+			events[repositories.EventKey{ChainID: found[i].ChainID, EventID: found[i].EventID}] = &found[i]
+		}
+	}
+
+	for i := range participants {
+		event := events[repositories.EventKey{ChainID: participants[i].ChainID, EventID: participants[i].EventID}]
+		if !c.policy.CanManageEvent(session, event) {
+			c.policy.RedactParticipant(session, &participants[i])
+		}
+	}
+	return nil
+}
+
+// ReindexEvent 从当前网络的合约重新索引活动、参与者和赞助商（仅活动组织者或管理员）
+func (c *EventController) ReindexEvent(ctx *gin.Context) {
+	eventID := ctx.Param("id")
+	session := middleware.CurrentSession(ctx)
+
+	event, err := c.service.FindEventOnActiveChain(eventID)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	// 尚未索引的活动无法确定组织者，只有管理员可以触发
+	if event == nil && !c.policy.IsAdmin(session) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Event not found"})
+		return
+	}
+	if err := c.policy.AuthorizeEventManagement(session, event); err != nil {
+		middleware.AbortWithAuthError(ctx, err)
+		return
+	}
+
+	result, err := c.service.ReindexEvent(ctx.Request.Context(), eventID)
+	switch {
+	case errors.Is(err, services.ErrInvalidEventID):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, services.ErrEventNotOnChain):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
 
 	ctx.JSON(http.StatusOK, gin.H{
 		"code": 0,
-		"data": participants,
+		"data": result,
 	})
 }
 
-// GetEventSponsors 获取活动的赞助商
+// GetEventSponsors 分页获取活动的赞助商，支持 network 过滤
 func (c *EventController) GetEventSponsors(ctx *gin.Context) {
 	eventID := ctx.Param("id")
 	if eventID == "" {
@@ -99,19 +292,25 @@ func (c *EventController) GetEventSponsors(ctx *gin.Context) {
 		return
 	}
 
-	sponsors, err := c.service.GetEventSponsors(eventID)
+	page, err := parsePageQuery(ctx)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{
-		"code": 0,
-		"data": sponsors,
-	})
+	sponsors, err := c.service.ListEventSponsors(repositories.SponsorFilter{
+		EventID: eventID,
+		Network: ctx.Query("network"),
+	}, page)
+	if err != nil {
+		respondListError(ctx, err)
+		return
+	}
+
+	respondPage(ctx, sponsors)
 }
 
-// GetEventTickets 获取活动的 NFT 门票
+// GetEventTickets 分页获取活动的 NFT 门票，支持 network, used 过滤
 func (c *EventController) GetEventTickets(ctx *gin.Context) {
 	eventID := ctx.Param("id")
 	if eventID == "" {
@@ -119,7 +318,52 @@ func (c *EventController) GetEventTickets(ctx *gin.Context) {
 		return
 	}
 
-	tickets, err := c.service.GetEventTickets(eventID)
+	c.listTickets(ctx, repositories.TicketFilter{EventID: eventID})
+}
+
+// GetTicketsByHolder 分页获取持有者的 NFT 门票，支持 network, used 过滤
+func (c *EventController) GetTicketsByHolder(ctx *gin.Context) {
+	holder, err := parseOptionalAddress(ctx, "holder")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if holder == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "holder address is required"})
+		return
+	}
+
+	c.listTickets(ctx, repositories.TicketFilter{Holder: holder})
+}
+
+// listTickets 在基础过滤条件上解析 network, used 和分页参数并返回门票列表
+func (c *EventController) listTickets(ctx *gin.Context, filter repositories.TicketFilter) {
+	used, err := parseOptionalBool(ctx, "used")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	filter.Used = used
+	filter.Network = ctx.Query("network")
+
+	page, err := parsePageQuery(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tickets, err := c.service.ListTickets(filter, page)
+	if err != nil {
+		respondListError(ctx, err)
+		return
+	}
+
+	respondPage(ctx, tickets)
+}
+
+// GetSyncStats 获取同步统计
+func (c *EventController) GetSyncStats(ctx *gin.Context) {
+	stats, err := c.service.GetSyncStats()
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -127,34 +371,43 @@ func (c *EventController) GetEventTickets(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusOK, gin.H{
 		"code": 0,
-		"data": tickets,
+		"data": stats,
 	})
 }
 
-// GetTicketsByHolder 获取持有者的所有 NFT 门票
-func (c *EventController) GetTicketsByHolder(ctx *gin.Context) {
-	holder := ctx.Query("holder")
-	if holder == "" {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "holder address is required"})
+// GetSyncMode 获取当前同步模式与状态
+func (c *EventController) GetSyncMode(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"data": c.service.GetSyncStatus(),
+	})
+}
+
+// SetSyncMode 运行时切换同步模式
+func (c *EventController) SetSyncMode(ctx *gin.Context) {
+	var req struct {
+		Mode         string `json:"mode" binding:"required"`
+		PollInterval int    `json:"poll_interval"`
+	}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	repo := c.service.GetRepository()
-	tickets, err := repo.GetNFTTicketsByHolder(holder)
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := c.service.SetSyncMode(req.Mode, req.PollInterval); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
 	ctx.JSON(http.StatusOK, gin.H{
 		"code": 0,
-		"data": tickets,
+		"data": c.service.GetSyncStatus(),
 	})
 }
 
-// GetSyncStats 获取同步统计
-func (c *EventController) GetSyncStats(ctx *gin.Context) {
-	stats, err := c.service.GetSyncStats()
+// GetSyncCheckpoints 获取各网络的同步检查点与索引延迟
+func (c *EventController) GetSyncCheckpoints(ctx *gin.Context) {
+	checkpoints, err := c.service.GetIndexingLag(ctx.Request.Context())
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -162,17 +415,34 @@ func (c *EventController) GetSyncStats(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusOK, gin.H{
 		"code": 0,
-		"data": stats,
+		"data": checkpoints,
 	})
 }
 
-// Health 健康检查
+// Health 存活检查（liveness），进程能响应即返回 200
 func (c *EventController) Health(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, gin.H{
 		"status": "ok",
 	})
 }
 
+// Ready 就绪检查（readiness），任一检查失败返回 503
+func (c *EventController) Ready(ctx *gin.Context) {
+	report := c.service.CheckReadiness(ctx.Request.Context())
+
+	status := http.StatusOK
+	state := "ok"
+	if !report.Ready {
+		status = http.StatusServiceUnavailable
+		state = "unavailable"
+	}
+
+	ctx.JSON(status, gin.H{
+		"status": state,
+		"checks": report.Checks,
+	})
+}
+
 // CreateTestEvent 创建测试活动
 func (c *EventController) CreateTestEvent(ctx *gin.Context) {
 	event := &models.Event{
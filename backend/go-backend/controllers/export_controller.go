@@ -0,0 +1,60 @@
+package controllers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"hackathon-backend/middleware"
+	"hackathon-backend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ExportController struct {
+	service *services.ExportService
+	events  *services.EventService
+	policy  *services.AuthPolicy
+}
+
+func NewExportController(service *services.ExportService, events *services.EventService, policy *services.AuthPolicy) *ExportController {
+	return &ExportController{service: service, events: events, policy: policy}
+}
+
+// Export 导出活动的参与者、赞助或门票（仅活动组织者或管理员），format 可选 csv、xlsx，按批流式输出
+func (c *ExportController) Export(ctx *gin.Context) {
+	event, err := c.events.FindEventOnActiveChain(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if event == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Event not found"})
+		return
+	}
+	if err := c.policy.AuthorizeEventManagement(middleware.CurrentSession(ctx), event); err != nil {
+		middleware.AbortWithAuthError(ctx, err)
+		return
+	}
+
+	export, err := c.service.NewExport(event, ctx.Param("dataset"), ctx.Query("format"))
+	if errors.Is(err, services.ErrInvalidExport) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Header("Content-Type", export.ContentType)
+	ctx.Header("Content-Disposition", `attachment; filename="`+export.Filename+`"`)
+	ctx.Header("Cache-Control", "no-store")
+	ctx.Status(http.StatusOK)
+
+	// 响应头已发送，出错时中止连接，让客户端看到下载失败而不是一个被截断的文件
+	if err := export.Write(ctx.Writer); err != nil {
+		log.Printf("❌ Export %s of event %s failed: %v", export.Dataset, event.EventID, err)
+		panic(http.ErrAbortHandler)
+	}
+}
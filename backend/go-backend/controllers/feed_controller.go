@@ -0,0 +1,75 @@
+package controllers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"hackathon-backend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FeedController struct {
+	service *services.FeedService
+}
+
+func NewFeedController(service *services.FeedService) *FeedController {
+	return &FeedController{service: service}
+}
+
+// notModified 根据 If-None-Match / If-Modified-Since 判断客户端缓存是否仍然有效
+func notModified(ctx *gin.Context, etag string, updated time.Time) bool {
+	if match := ctx.GetHeader("If-None-Match"); match != "" {
+		for _, tag := range strings.Split(match, ",") {
+			tag = strings.TrimSpace(tag)
+			if tag == "*" || strings.TrimPrefix(tag, "W/") == strings.TrimPrefix(etag, "W/") {
+				return true
+			}
+		}
+		return false
+	}
+	if since := ctx.GetHeader("If-Modified-Since"); since != "" && !updated.IsZero() {
+		t, err := http.ParseTime(since)
+		return err == nil && !updated.Truncate(time.Second).After(t)
+	}
+	return false
+}
+
+// Feed 活动订阅源（kind: new|upcoming, format: atom|rss），支持 network, location 过滤
+func (c *FeedController) Feed(kind, format string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		feed, err := c.service.GetFeed(services.FeedQuery{
+			Kind:     kind,
+			Format:   format,
+			Network:  ctx.Query("network"),
+			Location: strings.TrimSpace(ctx.Query("location")),
+		})
+		if errors.Is(err, services.ErrUnknownNetwork) || errors.Is(err, services.ErrInvalidFeed) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.Header("ETag", feed.ETag)
+		ctx.Header("Cache-Control", "public, max-age=300")
+		if !feed.Updated.IsZero() {
+			ctx.Header("Last-Modified", feed.Updated.UTC().Format(http.TimeFormat))
+		}
+		if notModified(ctx, feed.ETag, feed.Updated) {
+			ctx.Status(http.StatusNotModified)
+			return
+		}
+
+		ctx.Header("Content-Type", feed.ContentType)
+		ctx.Status(http.StatusOK)
+		if err := feed.Write(ctx.Writer); err != nil {
+			log.Printf("❌ Failed to write %s %s feed: %v", kind, format, err)
+		}
+	}
+}
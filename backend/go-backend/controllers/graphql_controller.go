@@ -0,0 +1,60 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"hackathon-backend/graph"
+	"hackathon-backend/middleware"
+	"hackathon-backend/services"
+
+	"github.com/gin-gonic/gin"
+	graphql "github.com/graph-gophers/graphql-go"
+)
+
+type GraphQLController struct {
+	schema  *graphql.Schema
+	service *services.EventService
+	policy  *services.AuthPolicy
+}
+
+func NewGraphQLController(service *services.EventService, policy *services.AuthPolicy) *GraphQLController {
+	return &GraphQLController{schema: graph.NewSchema(service), service: service, policy: policy}
+}
+
+// graphqlRequest GraphQL 请求体
+type graphqlRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// Query 执行 GraphQL 查询：POST JSON 请求体，或 GET ?query=&operationName=&variables=
+func (c *GraphQLController) Query(ctx *gin.Context) {
+	var req graphqlRequest
+	if ctx.Request.Method == http.MethodGet {
+		req.Query = ctx.Query("query")
+		req.OperationName = ctx.Query("operationName")
+		if variables := ctx.Query("variables"); variables != "" {
+			if err := json.Unmarshal([]byte(variables), &req.Variables); err != nil {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "variables must be a JSON object"})
+				return
+			}
+		}
+	} else if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if req.Query == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "query is required"})
+		return
+	}
+
+	// 每个请求使用独立的 dataloader，缓存只在本次请求内有效
+	reqCtx := graph.WithLoaders(ctx.Request.Context(), graph.NewLoaders(ctx.Request.Context(), c.service))
+	reqCtx = graph.WithViewer(reqCtx, middleware.CurrentSession(ctx), c.policy)
+	response := c.schema.Exec(reqCtx, req.Query, req.OperationName, req.Variables)
+
+	ctx.JSON(http.StatusOK, response)
+}
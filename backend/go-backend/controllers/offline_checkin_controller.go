@@ -0,0 +1,174 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"hackathon-backend/middleware"
+	"hackathon-backend/models"
+	"hackathon-backend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type OfflineCheckInController struct {
+	service *services.OfflineCheckInService
+	events  *services.EventService
+	policy  *services.AuthPolicy
+}
+
+func NewOfflineCheckInController(service *services.OfflineCheckInService, events *services.EventService, policy *services.AuthPolicy) *OfflineCheckInController {
+	return &OfflineCheckInController{service: service, events: events, policy: policy}
+}
+
+// respondOfflineCheckInError 根据错误类型返回 400/404/500
+func respondOfflineCheckInError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidOfflineRecord):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrDeviceNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// managedEvent 加载当前网络中的活动并校验组织者/管理员权限，失败时已写入响应
+func (c *OfflineCheckInController) managedEvent(ctx *gin.Context) (*models.Event, bool) {
+	event, err := c.events.FindEventOnActiveChain(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	if event == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Event not found"})
+		return nil, false
+	}
+	if err := c.policy.AuthorizeEventManagement(middleware.CurrentSession(ctx), event); err != nil {
+		middleware.AbortWithAuthError(ctx, err)
+		return nil, false
+	}
+	return event, true
+}
+
+// GetDevices 获取活动授权的扫码设备（仅活动组织者或管理员）
+func (c *OfflineCheckInController) GetDevices(ctx *gin.Context) {
+	event, ok := c.managedEvent(ctx)
+	if !ok {
+		return
+	}
+
+	devices, err := c.service.GetDevices(event)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"data": devices,
+	})
+}
+
+// AuthorizeDevice 授权扫码设备密钥（仅活动组织者或管理员），重复授权会取消撤销
+func (c *OfflineCheckInController) AuthorizeDevice(ctx *gin.Context) {
+	var req struct {
+		Address string `json:"address" binding:"required"`
+		Label   string `json:"label"`
+	}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	address, err := models.ParseAddress(req.Address)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	event, ok := c.managedEvent(ctx)
+	if !ok {
+		return
+	}
+
+	device, err := c.service.AuthorizeDevice(event, address, req.Label, middleware.CurrentSession(ctx).Address)
+	if err != nil {
+		respondOfflineCheckInError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{
+		"code": 0,
+		"data": device,
+	})
+}
+
+// RevokeDevice 撤销扫码设备（仅活动组织者或管理员）
+func (c *OfflineCheckInController) RevokeDevice(ctx *gin.Context) {
+	address, err := models.ParseAddress(ctx.Param("address"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	event, ok := c.managedEvent(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.service.RevokeDevice(event, address); err != nil {
+		respondOfflineCheckInError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"code": 0, "message": "Device revoked"})
+}
+
+// GetOfflineCheckIns 获取活动的离线签到记录及结算状态（仅活动组织者或管理员）
+func (c *OfflineCheckInController) GetOfflineCheckIns(ctx *gin.Context) {
+	event, ok := c.managedEvent(ctx)
+	if !ok {
+		return
+	}
+
+	status := ctx.Query("status")
+	switch status {
+	case "", models.OfflineCheckInProvisional, models.OfflineCheckInSubmitted, models.OfflineCheckInSettled, models.OfflineCheckInConflict:
+	default:
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status"})
+		return
+	}
+
+	checkIns, err := c.service.ListOfflineCheckIns(event, status)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"data": checkIns,
+	})
+}
+
+// Upload 上传扫码设备离线记录的签到，每条由设备密钥签名，无需登录
+func (c *OfflineCheckInController) Upload(ctx *gin.Context) {
+	var req struct {
+		Records []services.OfflineCheckInRecord `json:"records" binding:"required"`
+	}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := c.service.Upload(req.Records)
+	if err != nil {
+		respondOfflineCheckInError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"data": result,
+	})
+}
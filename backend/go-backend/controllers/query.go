@@ -0,0 +1,125 @@
+package controllers
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"hackathon-backend/models"
+	"hackathon-backend/repositories"
+
+	"github.com/gin-gonic/gin"
+)
+
+// parsePageQuery 解析分页和排序参数：limit, cursor, sort, order(asc|desc，默认 desc)
+func parsePageQuery(ctx *gin.Context) (repositories.PageQuery, error) {
+	page := repositories.PageQuery{
+		Cursor: ctx.Query("cursor"),
+		Sort:   ctx.Query("sort"),
+		Desc:   true,
+	}
+
+	if limit := ctx.Query("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n <= 0 || n > repositories.MaxPageLimit {
+			return page, fmt.Errorf("limit must be between 1 and %d", repositories.MaxPageLimit)
+		}
+		page.Limit = n
+	}
+
+	switch ctx.DefaultQuery("order", "desc") {
+	case "asc":
+		page.Desc = false
+	case "desc":
+		page.Desc = true
+	default:
+		return page, fmt.Errorf("order must be asc or desc")
+	}
+
+	return page, nil
+}
+
+// parseOptionalBool 解析可选的布尔查询参数
+func parseOptionalBool(ctx *gin.Context, name string) (*bool, error) {
+	value := ctx.Query(name)
+	if value == "" {
+		return nil, nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, fmt.Errorf("%s must be true or false", name)
+	}
+	return &b, nil
+}
+
+// parseOptionalInt64 解析可选的整数查询参数
+func parseOptionalInt64(ctx *gin.Context, name string) (*int64, error) {
+	value := ctx.Query(name)
+	if value == "" {
+		return nil, nil
+	}
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%s must be an integer", name)
+	}
+	return &n, nil
+}
+
+// parseOptionalAddress 解析可选的地址查询参数，返回校验和格式
+func parseOptionalAddress(ctx *gin.Context, name string) (models.Address, error) {
+	value := ctx.Query(name)
+	if value == "" {
+		return "", nil
+	}
+	address, err := models.ParseAddress(value)
+	if err != nil {
+		return "", fmt.Errorf("%s must be a valid address", name)
+	}
+	return address, nil
+}
+
+// respondPage 返回分页结果：data 为当前页数据，pagination 包含总数和下一页游标
+func respondPage[T any](ctx *gin.Context, page *repositories.Page[T]) {
+	ctx.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"data": page.Items,
+		"pagination": gin.H{
+			"total":       page.Total,
+			"limit":       page.Limit,
+			"next_cursor": page.NextCursor,
+			"has_more":    page.NextCursor != "",
+		},
+	})
+}
+
+// respondListError 列表查询错误：参数错误返回 400，其他返回 500
+func respondListError(ctx *gin.Context, err error) {
+	if errors.Is(err, repositories.ErrInvalidCursor) || errors.Is(err, repositories.ErrInvalidSort) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
+
+// parseOffsetCursor 解析基于偏移量的游标（用于按相关度排序等无法使用 keyset 的列表）
+func parseOffsetCursor(cursor string) (int, error) {
+	if cursor == "" {
+		return 0, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, repositories.ErrInvalidCursor
+	}
+	offset, err := strconv.Atoi(string(data))
+	if err != nil || offset < 0 {
+		return 0, repositories.ErrInvalidCursor
+	}
+	return offset, nil
+}
+
+// offsetCursor 生成下一页的偏移量游标
+func offsetCursor(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(offset)))
+}
@@ -0,0 +1,187 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"hackathon-backend/middleware"
+	"hackathon-backend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RelayerController struct {
+	service *services.RelayerService
+	events  *services.EventService
+	policy  *services.AuthPolicy
+}
+
+func NewRelayerController(service *services.RelayerService, events *services.EventService, policy *services.AuthPolicy) *RelayerController {
+	return &RelayerController{service: service, events: events, policy: policy}
+}
+
+// respondRelayerError 根据错误类型返回 400/404/409/422/503/500
+func respondRelayerError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrRelayerDisabled):
+		ctx.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrInvalidRelayRequest):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrRelayJobNotFound), errors.Is(err, services.ErrParticipantNotFound), errors.Is(err, services.ErrTicketNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrRelayPending), errors.Is(err, services.ErrAlreadyCheckedIn):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrNotDelegated), errors.Is(err, services.ErrTicketUnusable):
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// GetStatus 获取中继器状态和委托地址
+func (c *RelayerController) GetStatus(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"data": c.service.Status(),
+	})
+}
+
+// CreateCheckIn 提交中继签到（仅活动组织者或管理员），交易在后台按顺序提交
+func (c *RelayerController) CreateCheckIn(ctx *gin.Context) {
+	var req struct {
+		EventID     string `json:"event_id" binding:"required"`
+		Participant string `json:"participant" binding:"required"`
+		TokenID     string `json:"token_id"`
+	}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	event, err := c.events.FindEventOnActiveChain(req.EventID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if event == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Event not found"})
+		return
+	}
+	session := middleware.CurrentSession(ctx)
+	if err := c.policy.AuthorizeEventManagement(session, event); err != nil {
+		middleware.AbortWithAuthError(ctx, err)
+		return
+	}
+
+	job, err := c.service.EnqueueCheckIn(event, services.RelayCheckInInput{
+		EventID:     req.EventID,
+		Participant: req.Participant,
+		TokenID:     req.TokenID,
+	}, session.Address)
+	if err != nil {
+		respondRelayerError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, gin.H{
+		"code": 0,
+		"data": job,
+	})
+}
+
+// CreateBatch 批量提交中继签到（仅活动组织者或管理员），逐条校验并返回每条的结果
+func (c *RelayerController) CreateBatch(ctx *gin.Context) {
+	var req struct {
+		EventID string `json:"event_id" binding:"required"`
+		Items   []struct {
+			Participant string `json:"participant"`
+			TokenID     string `json:"token_id"`
+		} `json:"items" binding:"required"`
+	}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	event, err := c.events.FindEventOnActiveChain(req.EventID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if event == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Event not found"})
+		return
+	}
+	session := middleware.CurrentSession(ctx)
+	if err := c.policy.AuthorizeEventManagement(session, event); err != nil {
+		middleware.AbortWithAuthError(ctx, err)
+		return
+	}
+
+	items := make([]services.RelayCheckInInput, 0, len(req.Items))
+	for _, item := range req.Items {
+		items = append(items, services.RelayCheckInInput{EventID: req.EventID, Participant: item.Participant, TokenID: item.TokenID})
+	}
+
+	result, err := c.service.EnqueueBatch(event, items, session.Address)
+	if err != nil {
+		respondRelayerError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, gin.H{
+		"code": 0,
+		"data": result,
+	})
+}
+
+// GetBatch 查询批量签到中每条任务的状态（仅活动组织者或管理员）
+func (c *RelayerController) GetBatch(ctx *gin.Context) {
+	jobs, err := c.service.GetBatch(ctx.Param("batchId"))
+	if err != nil {
+		respondRelayerError(ctx, err)
+		return
+	}
+	event, err := c.events.FindEventOnChain(jobs[0].ChainID, jobs[0].EventID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if err := c.policy.AuthorizeEventManagement(middleware.CurrentSession(ctx), event); err != nil {
+		middleware.AbortWithAuthError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"data": jobs,
+	})
+}
+
+// GetCheckIn 查询中继签到状态（仅活动组织者或管理员）
+func (c *RelayerController) GetCheckIn(ctx *gin.Context) {
+	id, ok := parseUintParam(ctx, "id")
+	if !ok {
+		return
+	}
+
+	job, err := c.service.GetCheckIn(id)
+	if err != nil {
+		respondRelayerError(ctx, err)
+		return
+	}
+	event, err := c.events.FindEventOnChain(job.ChainID, job.EventID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if err := c.policy.AuthorizeEventManagement(middleware.CurrentSession(ctx), event); err != nil {
+		middleware.AbortWithAuthError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"data": job,
+	})
+}
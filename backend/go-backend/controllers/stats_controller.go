@@ -0,0 +1,81 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"hackathon-backend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type StatsController struct {
+	service *services.StatsRollupService
+}
+
+func NewStatsController(service *services.StatsRollupService) *StatsController {
+	return &StatsController{service: service}
+}
+
+// GetTimeseries 获取跨链平台统计时间序列，interval 可选 day、week、month，network 为空时返回所有链
+func (c *StatsController) GetTimeseries(ctx *gin.Context) {
+	var from, to int64
+	for name, target := range map[string]*int64{"from": &from, "to": &to} {
+		raw := ctx.Query(name)
+		if raw == "" {
+			continue
+		}
+		value, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || value < 0 {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + name})
+			return
+		}
+		*target = value
+	}
+
+	series, err := c.service.GetTimeseries(ctx.Query("interval"), ctx.Query("network"), from, to)
+	if errors.Is(err, services.ErrInvalidRollupInterval) || errors.Is(err, services.ErrUnknownNetwork) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"data": series,
+	})
+}
+
+// Rollup 手动触发统计汇总，from 为空时重新计算回溯窗口，from=0 时全量重建
+func (c *StatsController) Rollup(ctx *gin.Context) {
+	var req struct {
+		From *int64 `json:"from"`
+	}
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	since := c.service.LookbackStart()
+	if req.From != nil {
+		since = time.Unix(*req.From, 0)
+	}
+
+	result, err := c.service.Rollup(since)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"data": result,
+	})
+}
@@ -0,0 +1,293 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"hackathon-backend/config"
+	"hackathon-backend/services"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	streamHeartbeatInterval = 15 * time.Second // SSE 注释心跳 / WebSocket ping 间隔
+	wsWriteTimeout          = 10 * time.Second
+	wsPongTimeout           = 2 * streamHeartbeatInterval
+)
+
+var wsUpgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	CheckOrigin:     checkOrigin,
+}
+
+// checkOrigin 与全局 CORS 设置一致，只允许 CORS_ALLOWED_ORIGINS 中的前端来源；
+// 不带 Origin 的请求来自非浏览器客户端，不受跨站请求影响，直接放行
+func checkOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	return origin == "" || config.AppConfig.IsAllowedOrigin(origin)
+}
+
+type StreamController struct {
+	bus *services.EventBus
+}
+
+func NewStreamController(bus *services.EventBus) *StreamController {
+	return &StreamController{bus: bus}
+}
+
+// streamCommand WebSocket 客户端发送的订阅指令
+type streamCommand struct {
+	Action   string   `json:"action"` // subscribe, unsubscribe
+	EventIDs []string `json:"event_ids"`
+	Wallets  []string `json:"wallets"`
+}
+
+// buildTopics 根据活动 ID 和钱包地址构造订阅主题
+func buildTopics(eventIDs []string, wallets []string) ([]string, error) {
+	var topics []string
+	for _, id := range eventIDs {
+		if id == "" {
+			continue
+		}
+		if _, err := strconv.ParseUint(id, 10, 64); err != nil {
+			return nil, fmt.Errorf("invalid event_id: %s", id)
+		}
+		topics = append(topics, services.EventTopic(id))
+	}
+	for _, wallet := range wallets {
+		if wallet == "" {
+			continue
+		}
+		if !common.IsHexAddress(wallet) {
+			return nil, fmt.Errorf("invalid wallet: %s", wallet)
+		}
+		topics = append(topics, services.WalletTopic(wallet))
+	}
+	return topics, nil
+}
+
+// parseStreamTopics 解析 event_id / wallet 查询参数，支持重复参数或逗号分隔，都为空时订阅全部
+func parseStreamTopics(ctx *gin.Context) ([]string, error) {
+	split := func(values []string) []string {
+		var out []string
+		for _, v := range values {
+			for _, part := range strings.Split(v, ",") {
+				out = append(out, strings.TrimSpace(part))
+			}
+		}
+		return out
+	}
+	return buildTopics(split(ctx.QueryArray("event_id")), split(ctx.QueryArray("wallet")))
+}
+
+// StreamSSE 通过 Server-Sent Events 推送领域事件，支持 Last-Event-ID 断线续传
+func (c *StreamController) StreamSSE(ctx *gin.Context) {
+	topics, err := parseStreamTopics(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var lastID uint64
+	if v := ctx.GetHeader("Last-Event-ID"); v != "" {
+		lastID, _ = strconv.ParseUint(v, 10, 64)
+	}
+
+	sub, missed := c.bus.SubscribeSince(topics, lastID)
+	defer sub.Close()
+
+	header := ctx.Writer.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	// 禁用 nginx 缓冲
+	header.Set("X-Accel-Buffering", "no")
+	ctx.Status(http.StatusOK)
+
+	write := func(evt *services.DomainEvent) bool {
+		// 补发与实时推送可能重叠，按序号去重
+		if evt.ID <= lastID {
+			return true
+		}
+		data, err := json.Marshal(evt)
+		if err != nil {
+			log.Printf("⚠️ Failed to encode domain event #%d: %v", evt.ID, err)
+			return true
+		}
+		if _, err := fmt.Fprintf(ctx.Writer, "id: %d\nevent: %s\ndata: %s\n\n", evt.ID, evt.Type, data); err != nil {
+			return false
+		}
+		lastID = evt.ID
+		ctx.Writer.Flush()
+		return true
+	}
+
+	fmt.Fprintf(ctx.Writer, "retry: %d\n\n", (5 * time.Second).Milliseconds())
+	ctx.Writer.Flush()
+	for _, evt := range missed {
+		if !write(evt) {
+			return
+		}
+	}
+
+	heartbeat := time.NewTicker(streamHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case evt, ok := <-sub.Events():
+			if !ok || !write(evt) {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(ctx.Writer, ": ping\n\n"); err != nil {
+				return
+			}
+			ctx.Writer.Flush()
+		case <-ctx.Request.Context().Done():
+			return
+		}
+	}
+}
+
+// StreamWebSocket 通过 WebSocket 推送领域事件，连接后可发送 subscribe/unsubscribe 指令调整订阅
+func (c *StreamController) StreamWebSocket(ctx *gin.Context) {
+	topics, err := parseStreamTopics(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	conn, err := wsUpgrader.Upgrade(ctx.Writer, ctx.Request, nil)
+	if err != nil {
+		log.Printf("⚠️ WebSocket upgrade failed: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	sub := c.bus.Subscribe(topics)
+	defer sub.Close()
+
+	// gorilla/websocket 只允许一个并发写者，读协程通过 replies 交给写循环发送
+	replies := make(chan interface{}, 8)
+	done := make(chan struct{})
+	stop := make(chan struct{})
+	defer close(stop)
+	go c.readCommands(conn, sub, replies, done, stop)
+
+	replies <- gin.H{"type": "subscribed", "topics": sub.Topics()}
+
+	heartbeat := time.NewTicker(streamHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		var msg interface{}
+		select {
+		case evt, ok := <-sub.Events():
+			if !ok {
+				return
+			}
+			msg = evt
+		case reply := <-replies:
+			msg = reply
+		case <-heartbeat.C:
+			conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+			continue
+		case <-done:
+			return
+		}
+
+		conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+		if err := conn.WriteJSON(msg); err != nil {
+			return
+		}
+	}
+}
+
+// readCommands 读取客户端的订阅指令，连接断开时关闭 done
+func (c *StreamController) readCommands(conn *websocket.Conn, sub *services.Subscription, replies chan<- interface{}, done chan<- struct{}, stop <-chan struct{}) {
+	defer close(done)
+
+	reply := func(msg interface{}) bool {
+		select {
+		case replies <- msg:
+			return true
+		case <-stop:
+			return false
+		}
+	}
+
+	conn.SetReadLimit(4096)
+	conn.SetReadDeadline(time.Now().Add(wsPongTimeout))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(wsPongTimeout))
+	})
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		conn.SetReadDeadline(time.Now().Add(wsPongTimeout))
+
+		var cmd streamCommand
+		if err := json.Unmarshal(data, &cmd); err != nil {
+			if !reply(gin.H{"type": "error", "error": "invalid message"}) {
+				return
+			}
+			continue
+		}
+
+		topics, err := buildTopics(cmd.EventIDs, cmd.Wallets)
+		if err != nil {
+			if !reply(gin.H{"type": "error", "error": err.Error()}) {
+				return
+			}
+			continue
+		}
+
+		current := map[string]bool{}
+		for _, topic := range sub.Topics() {
+			current[topic] = true
+		}
+
+		switch cmd.Action {
+		case "subscribe":
+			// 指定具体主题后不再接收全部事件
+			delete(current, services.TopicAll)
+			for _, topic := range topics {
+				current[topic] = true
+			}
+		case "unsubscribe":
+			for _, topic := range topics {
+				delete(current, topic)
+			}
+		default:
+			if !reply(gin.H{"type": "error", "error": "action must be subscribe or unsubscribe"}) {
+				return
+			}
+			continue
+		}
+
+		next := make([]string, 0, len(current))
+		for topic := range current {
+			next = append(next, topic)
+		}
+		sub.SetTopics(next)
+		if !reply(gin.H{"type": "subscribed", "topics": sub.Topics()}) {
+			return
+		}
+	}
+}
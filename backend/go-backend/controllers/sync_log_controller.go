@@ -0,0 +1,54 @@
+package controllers
+
+import (
+	"net/http"
+	"time"
+
+	"hackathon-backend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SyncLogController struct {
+	service *services.SyncLogService
+}
+
+func NewSyncLogController(service *services.SyncLogService) *SyncLogController {
+	return &SyncLogController{service: service}
+}
+
+// GetDailyStats 获取同步日志每日汇总
+func (c *SyncLogController) GetDailyStats(ctx *gin.Context) {
+	since := ctx.Query("since")
+	if since != "" {
+		if _, err := time.Parse("2006-01-02", since); err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "since must be YYYY-MM-DD"})
+			return
+		}
+	}
+
+	stats, err := c.service.GetDailyStats(ctx.Query("network"), since)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"data": stats,
+	})
+}
+
+// Compact 手动触发同步日志压缩
+func (c *SyncLogController) Compact(ctx *gin.Context) {
+	result, err := c.service.Compact()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"data": result,
+	})
+}
@@ -0,0 +1,45 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"hackathon-backend/middleware"
+	"hackathon-backend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WalletController struct {
+	service *services.WalletService
+	policy  *services.AuthPolicy
+}
+
+func NewWalletController(service *services.WalletService, policy *services.AuthPolicy) *WalletController {
+	return &WalletController{service: service, policy: policy}
+}
+
+// GetProfile 获取钱包在各链上的报名、签到、赞助、组织和持票记录，地址不区分大小写
+func (c *WalletController) GetProfile(ctx *gin.Context) {
+	profile, err := c.service.GetProfile(ctx.Param("address"), ctx.Query("network"))
+	if errors.Is(err, services.ErrInvalidAddress) || errors.Is(err, services.ErrUnknownNetwork) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// 报名时填写的姓名只对钱包本人和管理员可见
+	if !c.policy.CanViewWallet(middleware.CurrentSession(ctx), profile.Address) {
+		for i := range profile.Registrations {
+			profile.Registrations[i].Name = ""
+		}
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"data": profile,
+	})
+}
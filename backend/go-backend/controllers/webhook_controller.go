@@ -0,0 +1,281 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"hackathon-backend/middleware"
+	"hackathon-backend/models"
+	"hackathon-backend/repositories"
+	"hackathon-backend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WebhookController struct {
+	service *services.WebhookService
+	policy  *services.AuthPolicy
+}
+
+func NewWebhookController(service *services.WebhookService, policy *services.AuthPolicy) *WebhookController {
+	return &WebhookController{service: service, policy: policy}
+}
+
+// webhookRequest 创建/更新订阅的请求体
+type webhookRequest struct {
+	Organizer  string   `json:"organizer"`
+	EventID    string   `json:"event_id"`
+	URL        string   `json:"url"`
+	Secret     string   `json:"secret"`
+	EventTypes []string `json:"event_types"`
+	Active     *bool    `json:"active"`
+}
+
+// webhookView 订阅响应，密钥只在创建或更换时返回一次
+func webhookView(sub *models.WebhookSubscription, withSecret bool) gin.H {
+	view := gin.H{
+		"id":          sub.ID,
+		"organizer":   sub.Organizer,
+		"event_id":    sub.EventID,
+		"url":         sub.URL,
+		"event_types": sub.EventTypeList(),
+		"active":      sub.Active,
+		"created_at":  sub.CreatedAt,
+		"updated_at":  sub.UpdatedAt,
+	}
+	if withSecret {
+		view["secret"] = sub.Secret
+	}
+	return view
+}
+
+// respondWebhookError 根据错误类型返回 400/404/500
+func respondWebhookError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrWebhookNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrInvalidWebhook):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		respondListError(ctx, err)
+	}
+}
+
+// parseUintParam 解析路径中的数字 ID
+func parseUintParam(ctx *gin.Context, name string) (uint, bool) {
+	id, err := strconv.ParseUint(ctx.Param(name), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + name})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// organizerParam 解析请求中的组织者地址，未指定时使用当前登录的钱包；地址非法时返回 400
+func organizerParam(ctx *gin.Context, value string) (models.Address, bool) {
+	if value == "" {
+		return models.Address(middleware.CurrentWallet(ctx)), true
+	}
+	organizer, err := models.ParseAddress(value)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return "", false
+	}
+	return organizer, true
+}
+
+// authorizeSubscription 加载订阅并校验当前钱包是否为其组织者
+func (c *WebhookController) authorizeSubscription(ctx *gin.Context) (*models.WebhookSubscription, bool) {
+	id, ok := parseUintParam(ctx, "id")
+	if !ok {
+		return nil, false
+	}
+
+	sub, err := c.service.GetSubscription(id)
+	if err != nil {
+		respondWebhookError(ctx, err)
+		return nil, false
+	}
+	if err := c.policy.AuthorizeOrganizer(middleware.CurrentSession(ctx), sub.Organizer); err != nil {
+		middleware.AbortWithAuthError(ctx, err)
+		return nil, false
+	}
+	return sub, true
+}
+
+// CreateWebhook 创建 Webhook 订阅
+func (c *WebhookController) CreateWebhook(ctx *gin.Context) {
+	var req webhookRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	session := middleware.CurrentSession(ctx)
+	organizer, ok := organizerParam(ctx, req.Organizer)
+	if !ok {
+		return
+	}
+	if err := c.policy.AuthorizeOrganizer(session, organizer); err != nil {
+		middleware.AbortWithAuthError(ctx, err)
+		return
+	}
+
+	sub, err := c.service.CreateSubscription(services.WebhookInput{
+		Organizer:  organizer,
+		EventID:    req.EventID,
+		URL:        req.URL,
+		Secret:     req.Secret,
+		EventTypes: req.EventTypes,
+		Active:     req.Active,
+	})
+	if err != nil {
+		respondWebhookError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{"code": 0, "data": webhookView(sub, true)})
+}
+
+// GetWebhooks 获取组织者的 Webhook 订阅，organizer 默认为当前登录的钱包
+func (c *WebhookController) GetWebhooks(ctx *gin.Context) {
+	session := middleware.CurrentSession(ctx)
+	organizer, ok := organizerParam(ctx, ctx.Query("organizer"))
+	if !ok {
+		return
+	}
+	if err := c.policy.AuthorizeOrganizer(session, organizer); err != nil {
+		middleware.AbortWithAuthError(ctx, err)
+		return
+	}
+
+	subs, err := c.service.GetSubscriptionsByOrganizer(organizer)
+	if err != nil {
+		respondWebhookError(ctx, err)
+		return
+	}
+
+	views := make([]gin.H, 0, len(subs))
+	for i := range subs {
+		views = append(views, webhookView(&subs[i], false))
+	}
+	ctx.JSON(http.StatusOK, gin.H{"code": 0, "data": views})
+}
+
+// GetWebhook 获取单个 Webhook 订阅
+func (c *WebhookController) GetWebhook(ctx *gin.Context) {
+	sub, ok := c.authorizeSubscription(ctx)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"code": 0, "data": webhookView(sub, false)})
+}
+
+// UpdateWebhook 更新 Webhook 订阅的地址、密钥、事件类型或启用状态
+func (c *WebhookController) UpdateWebhook(ctx *gin.Context) {
+	sub, ok := c.authorizeSubscription(ctx)
+	if !ok {
+		return
+	}
+
+	var req webhookRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	sub, err := c.service.UpdateSubscription(sub.ID, services.WebhookInput{
+		URL:        req.URL,
+		Secret:     req.Secret,
+		EventTypes: req.EventTypes,
+		Active:     req.Active,
+	})
+	if err != nil {
+		respondWebhookError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"code": 0, "data": webhookView(sub, req.Secret != "")})
+}
+
+// DeleteWebhook 删除 Webhook 订阅
+func (c *WebhookController) DeleteWebhook(ctx *gin.Context) {
+	sub, ok := c.authorizeSubscription(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.service.DeleteSubscription(sub.ID); err != nil {
+		respondWebhookError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"code": 0, "message": "Webhook deleted"})
+}
+
+// GetDeliveries 分页获取 Webhook 投递记录，可按 status 过滤
+func (c *WebhookController) GetDeliveries(ctx *gin.Context) {
+	sub, ok := c.authorizeSubscription(ctx)
+	if !ok {
+		return
+	}
+
+	page, err := parsePageQuery(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	deliveries, err := c.service.ListDeliveries(repositories.DeliveryFilter{
+		SubscriptionID: sub.ID,
+		Status:         ctx.Query("status"),
+	}, page)
+	if err != nil {
+		respondWebhookError(ctx, err)
+		return
+	}
+
+	respondPage(ctx, deliveries)
+}
+
+// GetDeliveryAttempts 获取某次投递的请求日志
+func (c *WebhookController) GetDeliveryAttempts(ctx *gin.Context) {
+	sub, ok := c.authorizeSubscription(ctx)
+	if !ok {
+		return
+	}
+	deliveryID, ok := parseUintParam(ctx, "deliveryId")
+	if !ok {
+		return
+	}
+
+	attempts, err := c.service.GetDeliveryAttempts(sub.ID, deliveryID)
+	if err != nil {
+		respondWebhookError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"code": 0, "data": attempts})
+}
+
+// Redeliver 重新投递某次投递的请求体
+func (c *WebhookController) Redeliver(ctx *gin.Context) {
+	sub, ok := c.authorizeSubscription(ctx)
+	if !ok {
+		return
+	}
+	deliveryID, ok := parseUintParam(ctx, "deliveryId")
+	if !ok {
+		return
+	}
+
+	delivery, err := c.service.Redeliver(sub.ID, deliveryID)
+	if err != nil {
+		respondWebhookError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, gin.H{"code": 0, "data": delivery})
+}
@@ -34,6 +34,11 @@ func Init(cfg *config.Config) error {
 		return fmt.Errorf("failed to migrate database: %w", err)
 	}
 
+	// 地址统一为校验和格式
+	if err := models.NormalizeAddresses(db); err != nil {
+		return fmt.Errorf("failed to normalize addresses: %w", err)
+	}
+
 	log.Println("✅ Database migration completed")
 	return nil
 }
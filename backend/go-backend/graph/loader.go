@@ -0,0 +1,179 @@
+package graph
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"hackathon-backend/models"
+	"hackathon-backend/repositories"
+	"hackathon-backend/services"
+)
+
+const (
+	loaderWait     = 2 * time.Millisecond // 收集同一批 key 的等待时间
+	loaderMaxBatch = 500                  // 单批最多 key 数，达到后立即查询
+)
+
+// loaderResult 单个 key 的加载结果
+type loaderResult[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+// loader 请求级 dataloader：在短时间窗口内收集并发解析器的 key，合并为一次批量查询，并缓存结果
+type loader[K comparable, V any] struct {
+	ctx   context.Context
+	fetch func(ctx context.Context, keys []K) (map[K]V, error)
+
+	mu      sync.Mutex
+	cache   map[K]*loaderResult[V]
+	pending []K
+	timer   *time.Timer
+}
+
+func newLoader[K comparable, V any](ctx context.Context, fetch func(ctx context.Context, keys []K) (map[K]V, error)) *loader[K, V] {
+	return &loader[K, V]{
+		ctx:   ctx,
+		fetch: fetch,
+		cache: map[K]*loaderResult[V]{},
+	}
+}
+
+// Load 加载单个 key，key 不存在时返回零值
+func (l *loader[K, V]) Load(ctx context.Context, key K) (V, error) {
+	l.mu.Lock()
+	result, ok := l.cache[key]
+	if !ok {
+		result = &loaderResult[V]{done: make(chan struct{})}
+		l.cache[key] = result
+		l.pending = append(l.pending, key)
+
+		if len(l.pending) >= loaderMaxBatch {
+			l.dispatchLocked()
+		} else if l.timer == nil {
+			l.timer = time.AfterFunc(loaderWait, l.dispatch)
+		}
+	}
+	l.mu.Unlock()
+
+	select {
+	case <-result.done:
+		return result.value, result.err
+	case <-ctx.Done():
+		var zero V
+		return zero, ctx.Err()
+	}
+}
+
+func (l *loader[K, V]) dispatch() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.dispatchLocked()
+}
+
+// dispatchLocked 取出待查询的 key 并在后台执行批量查询，调用方需持有锁
+func (l *loader[K, V]) dispatchLocked() {
+	if l.timer != nil {
+		l.timer.Stop()
+		l.timer = nil
+	}
+	if len(l.pending) == 0 {
+		return
+	}
+
+	keys := l.pending
+	l.pending = nil
+	results := make([]*loaderResult[V], len(keys))
+	for i, key := range keys {
+		results[i] = l.cache[key]
+	}
+
+	go func() {
+		values, err := l.fetch(l.ctx, keys)
+		for i, key := range keys {
+			results[i].value, results[i].err = values[key], err
+			close(results[i].done)
+		}
+	}()
+}
+
+// Loaders 一次 GraphQL 请求使用的 dataloader 集合
+type Loaders struct {
+	events       *loader[repositories.EventKey, *models.Event]
+	participants *loader[repositories.EventKey, []models.Participant]
+	sponsors     *loader[repositories.EventKey, []models.Sponsor]
+	tickets      *loader[repositories.EventKey, []models.NFTTicket]
+}
+
+// NewLoaders 为一次请求创建 dataloader，按 (chain_id, event_id) 批量读取活动及其关联数据
+func NewLoaders(ctx context.Context, service *services.EventService) *Loaders {
+	return &Loaders{
+		events: newLoader(ctx, func(ctx context.Context, keys []repositories.EventKey) (map[repositories.EventKey]*models.Event, error) {
+			events, err := service.GetEventsByKeys(keys)
+			if err != nil {
+				return nil, err
+			}
+			byKey := make(map[repositories.EventKey]*models.Event, len(events))
+			for i := range events {
+				// 同一链上多个合约有相同活动 ID 时取最新同步的记录
+				byKey[eventKeyOf(events[i].ChainID, events[i].EventID)] = &events[i]
+			}
+			return byKey, nil
+		}),
+		participants: newLoader(ctx, func(ctx context.Context, keys []repositories.EventKey) (map[repositories.EventKey][]models.Participant, error) {
+			participants, err := service.GetParticipantsByEventKeys(keys)
+			if err != nil {
+				return nil, err
+			}
+			return groupByEvent(participants, func(p *models.Participant) repositories.EventKey {
+				return eventKeyOf(p.ChainID, p.EventID)
+			}), nil
+		}),
+		sponsors: newLoader(ctx, func(ctx context.Context, keys []repositories.EventKey) (map[repositories.EventKey][]models.Sponsor, error) {
+			sponsors, err := service.GetSponsorsByEventKeys(keys)
+			if err != nil {
+				return nil, err
+			}
+			return groupByEvent(sponsors, func(s *models.Sponsor) repositories.EventKey {
+				return eventKeyOf(s.ChainID, s.EventID)
+			}), nil
+		}),
+		tickets: newLoader(ctx, func(ctx context.Context, keys []repositories.EventKey) (map[repositories.EventKey][]models.NFTTicket, error) {
+			tickets, err := service.GetTicketsByEventKeys(keys)
+			if err != nil {
+				return nil, err
+			}
+			return groupByEvent(tickets, func(t *models.NFTTicket) repositories.EventKey {
+				return eventKeyOf(t.ChainID, t.EventID)
+			}), nil
+		}),
+	}
+}
+
+func eventKeyOf(chainID uint64, eventID string) repositories.EventKey {
+	return repositories.EventKey{ChainID: chainID, EventID: eventID}
+}
+
+// groupByEvent 按活动分组
+func groupByEvent[T any](items []T, key func(item *T) repositories.EventKey) map[repositories.EventKey][]T {
+	grouped := map[repositories.EventKey][]T{}
+	for i := range items {
+		k := key(&items[i])
+		grouped[k] = append(grouped[k], items[i])
+	}
+	return grouped
+}
+
+type loadersKey struct{}
+
+// WithLoaders 将 dataloader 放入请求上下文
+func WithLoaders(ctx context.Context, loaders *Loaders) context.Context {
+	return context.WithValue(ctx, loadersKey{}, loaders)
+}
+
+// loadersFrom 从请求上下文获取 dataloader
+func loadersFrom(ctx context.Context) *Loaders {
+	return ctx.Value(loadersKey{}).(*Loaders)
+}
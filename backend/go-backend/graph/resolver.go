@@ -0,0 +1,320 @@
+package graph
+
+import (
+	"context"
+	_ "embed"
+
+	"hackathon-backend/config"
+	"hackathon-backend/models"
+	"hackathon-backend/repositories"
+	"hackathon-backend/services"
+
+	graphql "github.com/graph-gophers/graphql-go"
+)
+
+//go:embed schema.graphql
+var schemaSDL string
+
+// maxQueryDepth 查询最大嵌套深度，防止 event -> participants -> event ... 的深层查询
+const maxQueryDepth = 8
+
+// NewSchema 解析 GraphQL schema 并绑定解析器
+func NewSchema(service *services.EventService) *graphql.Schema {
+	return graphql.MustParseSchema(schemaSDL, &Resolver{service: service},
+		graphql.MaxDepth(maxQueryDepth),
+		// 列表中各元素的关联字段并发解析，dataloader 才能把它们合并为一次查询
+		graphql.MaxParallelism(repositories.MaxPageLimit),
+	)
+}
+
+// Resolver Query 根解析器
+type Resolver struct {
+	service *services.EventService
+}
+
+// pageInput 分页参数
+type pageInput struct {
+	Limit  *int32
+	Cursor *string
+	Sort   *string
+	Order  *string
+}
+
+func (p *pageInput) toPageQuery() repositories.PageQuery {
+	page := repositories.PageQuery{Desc: true}
+	if p == nil {
+		return page
+	}
+	if p.Limit != nil {
+		page.Limit = int(*p.Limit)
+	}
+	if p.Cursor != nil {
+		page.Cursor = *p.Cursor
+	}
+	if p.Sort != nil {
+		page.Sort = *p.Sort
+	}
+	if p.Order != nil {
+		page.Desc = *p.Order != "ASC"
+	}
+	return page
+}
+
+type eventFilterInput struct {
+	Network      *string
+	Organizer    *string
+	Active       *bool
+	StartsAfter  *Long
+	StartsBefore *Long
+}
+
+type participantFilterInput struct {
+	EventID   *string
+	Network   *string
+	CheckedIn *bool
+}
+
+type sponsorFilterInput struct {
+	EventID *string
+	Network *string
+}
+
+type ticketFilterInput struct {
+	EventID *string
+	Holder  *string
+	Network *string
+	Used    *bool
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// addressValue 解析可选的地址参数，格式错误时返回错误
+func addressValue(s *string) (models.Address, error) {
+	if s == nil || *s == "" {
+		return "", nil
+	}
+	return models.ParseAddress(*s)
+}
+
+func int64Ptr(l *Long) *int64 {
+	if l == nil {
+		return nil
+	}
+	v := int64(*l)
+	return &v
+}
+
+// Events 分页获取活动
+func (r *Resolver) Events(args struct {
+	Filter *eventFilterInput
+	Page   *pageInput
+}) (*connectionResolver[*eventResolver], error) {
+	filter := repositories.EventFilter{}
+	if f := args.Filter; f != nil {
+		organizer, err := addressValue(f.Organizer)
+		if err != nil {
+			return nil, err
+		}
+		filter = repositories.EventFilter{
+			Network:      stringValue(f.Network),
+			Organizer:    organizer,
+			Active:       f.Active,
+			StartsAfter:  int64Ptr(f.StartsAfter),
+			StartsBefore: int64Ptr(f.StartsBefore),
+		}
+	}
+
+	page, err := r.service.ListEvents(filter, args.Page.toPageQuery())
+	if err != nil {
+		return nil, err
+	}
+	return newConnection(page, newEventResolver), nil
+}
+
+// Event 根据链上活动 ID 获取活动
+func (r *Resolver) Event(ctx context.Context, args struct {
+	EventID string
+	ChainID *Long
+}) (*eventResolver, error) {
+	chainID := config.AppConfig.GetActiveChainID()
+	if args.ChainID != nil {
+		chainID = uint64(*args.ChainID)
+	}
+
+	event, err := loadersFrom(ctx).events.Load(ctx, eventKeyOf(chainID, args.EventID))
+	if err != nil || event == nil {
+		return nil, err
+	}
+	return &eventResolver{event}, nil
+}
+
+// Participants 分页获取参与者
+func (r *Resolver) Participants(args struct {
+	Filter *participantFilterInput
+	Page   *pageInput
+}) (*connectionResolver[*participantResolver], error) {
+	filter := repositories.ParticipantFilter{}
+	if f := args.Filter; f != nil {
+		filter = repositories.ParticipantFilter{
+			EventID:   stringValue(f.EventID),
+			Network:   stringValue(f.Network),
+			CheckedIn: f.CheckedIn,
+		}
+	}
+
+	page, err := r.service.ListEventParticipants(filter, args.Page.toPageQuery())
+	if err != nil {
+		return nil, err
+	}
+	return newConnection(page, newParticipantResolver), nil
+}
+
+// Sponsors 分页获取赞助商
+func (r *Resolver) Sponsors(args struct {
+	Filter *sponsorFilterInput
+	Page   *pageInput
+}) (*connectionResolver[*sponsorResolver], error) {
+	filter := repositories.SponsorFilter{}
+	if f := args.Filter; f != nil {
+		filter = repositories.SponsorFilter{
+			EventID: stringValue(f.EventID),
+			Network: stringValue(f.Network),
+		}
+	}
+
+	page, err := r.service.ListEventSponsors(filter, args.Page.toPageQuery())
+	if err != nil {
+		return nil, err
+	}
+	return newConnection(page, newSponsorResolver), nil
+}
+
+// Tickets 分页获取 NFT 门票
+func (r *Resolver) Tickets(args struct {
+	Filter *ticketFilterInput
+	Page   *pageInput
+}) (*connectionResolver[*ticketResolver], error) {
+	filter := repositories.TicketFilter{}
+	if f := args.Filter; f != nil {
+		holder, err := addressValue(f.Holder)
+		if err != nil {
+			return nil, err
+		}
+		filter = repositories.TicketFilter{
+			EventID: stringValue(f.EventID),
+			Holder:  holder,
+			Network: stringValue(f.Network),
+			Used:    f.Used,
+		}
+	}
+
+	page, err := r.service.ListTickets(filter, args.Page.toPageQuery())
+	if err != nil {
+		return nil, err
+	}
+	return newConnection(page, newTicketResolver), nil
+}
+
+// SyncStatus 同步状态和各合约检查点
+func (r *Resolver) SyncStatus(ctx context.Context) (*syncStatusResolver, error) {
+	checkpoints, err := r.service.GetIndexingLag(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &syncStatusResolver{status: r.service.GetSyncStatus(), checkpoints: checkpoints}, nil
+}
+
+// connectionResolver 分页结果
+type connectionResolver[R any] struct {
+	items    []R
+	pageInfo *pageInfoResolver
+}
+
+func newConnection[T any, R any](page *repositories.Page[T], wrap func(item *T) R) *connectionResolver[R] {
+	items := make([]R, 0, len(page.Items))
+	for i := range page.Items {
+		items = append(items, wrap(&page.Items[i]))
+	}
+	return &connectionResolver[R]{
+		items: items,
+		pageInfo: &pageInfoResolver{
+			totalCount: page.Total,
+			limit:      page.Limit,
+			nextCursor: page.NextCursor,
+		},
+	}
+}
+
+func (c *connectionResolver[R]) Items() []R                  { return c.items }
+func (c *connectionResolver[R]) PageInfo() *pageInfoResolver { return c.pageInfo }
+
+type pageInfoResolver struct {
+	totalCount int64
+	limit      int
+	nextCursor string
+}
+
+func (p *pageInfoResolver) TotalCount() Long { return Long(p.totalCount) }
+func (p *pageInfoResolver) Limit() int32     { return int32(p.limit) }
+func (p *pageInfoResolver) HasMore() bool    { return p.nextCursor != "" }
+func (p *pageInfoResolver) NextCursor() *string {
+	if p.nextCursor == "" {
+		return nil
+	}
+	return &p.nextCursor
+}
+
+// syncStatusResolver 同步状态
+type syncStatusResolver struct {
+	status      *services.SyncStatus
+	checkpoints []services.CheckpointLag
+}
+
+func (s *syncStatusResolver) Mode() string         { return s.status.Mode }
+func (s *syncStatusResolver) ActiveMode() string   { return s.status.ActiveMode }
+func (s *syncStatusResolver) PollInterval() int32  { return int32(s.status.PollInterval) }
+func (s *syncStatusResolver) LastBlock() Long      { return Long(s.status.LastBlock) }
+func (s *syncStatusResolver) SubscriptionOk() bool { return s.status.SubscriptionOK }
+func (s *syncStatusResolver) Checkpoints() []*checkpointResolver {
+	resolvers := make([]*checkpointResolver, 0, len(s.checkpoints))
+	for i := range s.checkpoints {
+		resolvers = append(resolvers, &checkpointResolver{&s.checkpoints[i]})
+	}
+	return resolvers
+}
+
+type checkpointResolver struct {
+	cp *services.CheckpointLag
+}
+
+func (c *checkpointResolver) ChainID() Long           { return Long(c.cp.ChainID) }
+func (c *checkpointResolver) Network() string         { return c.cp.Network }
+func (c *checkpointResolver) ContractAddress() string { return c.cp.ContractAddress }
+func (c *checkpointResolver) LastBlock() Long         { return Long(c.cp.LastBlock) }
+func (c *checkpointResolver) BlockHash() string       { return c.cp.BlockHash }
+func (c *checkpointResolver) UpdatedAt() graphql.Time { return graphql.Time{Time: c.cp.UpdatedAt} }
+func (c *checkpointResolver) HeadBlock() *Long        { return uint64Ptr(c.cp.HeadBlock) }
+func (c *checkpointResolver) Lag() *Long              { return uint64Ptr(c.cp.Lag) }
+
+func uint64Ptr(v *uint64) *Long {
+	if v == nil {
+		return nil
+	}
+	l := Long(*v)
+	return &l
+}
+
+// findParticipant 在活动参与者中查找钱包
+func findParticipant(participants []models.Participant, wallet models.Address) *models.Participant {
+	for i := range participants {
+		if participants[i].Wallet.Equal(wallet) {
+			return &participants[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package graph
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// Long 64 位整数标量（区块号、Unix 时间戳、链ID等超出 GraphQL Int 32 位范围的字段）
+type Long int64
+
+// ImplementsGraphQLType 对应 schema 中的 scalar Long
+func (Long) ImplementsGraphQLType(name string) bool {
+	return name == "Long"
+}
+
+// UnmarshalGraphQL 解析输入，支持数字和数字字符串
+func (l *Long) UnmarshalGraphQL(input interface{}) error {
+	switch v := input.(type) {
+	case int32:
+		*l = Long(v)
+	case int64:
+		*l = Long(v)
+	case float64:
+		*l = Long(v)
+	case string:
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid Long: %s", v)
+		}
+		*l = Long(n)
+	default:
+		return fmt.Errorf("wrong type for Long: %T", input)
+	}
+	return nil
+}
+
+// MarshalJSON 输出为 JSON 数字
+func (l Long) MarshalJSON() ([]byte, error) {
+	return json.Marshal(int64(l))
+}
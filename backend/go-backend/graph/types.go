@@ -0,0 +1,207 @@
+package graph
+
+import (
+	"context"
+	"strconv"
+
+	"hackathon-backend/models"
+
+	graphql "github.com/graph-gophers/graphql-go"
+)
+
+func modelID(id uint) graphql.ID {
+	return graphql.ID(strconv.FormatUint(uint64(id), 10))
+}
+
+// eventResolver 活动
+type eventResolver struct {
+	e *models.Event
+}
+
+func newEventResolver(e *models.Event) *eventResolver { return &eventResolver{e} }
+
+func (r *eventResolver) ID() graphql.ID          { return modelID(r.e.ID) }
+func (r *eventResolver) ChainID() Long           { return Long(r.e.ChainID) }
+func (r *eventResolver) Network() string         { return r.e.Network }
+func (r *eventResolver) ContractAddress() string { return r.e.ContractAddress }
+func (r *eventResolver) EventID() string         { return r.e.EventID }
+func (r *eventResolver) Organizer() string       { return r.e.Organizer.String() }
+func (r *eventResolver) Title() string           { return r.e.Title }
+func (r *eventResolver) Description() string     { return r.e.Description }
+func (r *eventResolver) StartTime() Long         { return Long(r.e.StartTime) }
+func (r *eventResolver) EndTime() Long           { return Long(r.e.EndTime) }
+func (r *eventResolver) Location() string        { return r.e.Location }
+func (r *eventResolver) MaxParticipants() Long   { return Long(r.e.MaxParticipants) }
+func (r *eventResolver) ParticipantCount() Long  { return Long(r.e.ParticipantCount) }
+func (r *eventResolver) Active() bool            { return r.e.Active }
+func (r *eventResolver) CreatedAt() graphql.Time { return graphql.Time{Time: r.e.CreatedAt} }
+func (r *eventResolver) UpdatedAt() graphql.Time { return graphql.Time{Time: r.e.UpdatedAt} }
+func (r *eventResolver) SyncedAt() graphql.Time  { return graphql.Time{Time: r.e.SyncedAt} }
+
+// Participants 活动参与者（通过 dataloader 批量加载），可按签到状态过滤
+func (r *eventResolver) Participants(ctx context.Context, args struct{ CheckedIn *bool }) ([]*participantResolver, error) {
+	participants, err := loadersFrom(ctx).participants.Load(ctx, eventKeyOf(r.e.ChainID, r.e.EventID))
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]*participantResolver, 0, len(participants))
+	for i := range participants {
+		if args.CheckedIn != nil && participants[i].CheckedIn != *args.CheckedIn {
+			continue
+		}
+		resolvers = append(resolvers, newParticipantResolver(&participants[i]))
+	}
+	return resolvers, nil
+}
+
+// Sponsors 活动赞助商（通过 dataloader 批量加载）
+func (r *eventResolver) Sponsors(ctx context.Context) ([]*sponsorResolver, error) {
+	sponsors, err := loadersFrom(ctx).sponsors.Load(ctx, eventKeyOf(r.e.ChainID, r.e.EventID))
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]*sponsorResolver, 0, len(sponsors))
+	for i := range sponsors {
+		resolvers = append(resolvers, newSponsorResolver(&sponsors[i]))
+	}
+	return resolvers, nil
+}
+
+// Tickets 活动门票（通过 dataloader 批量加载），可按持有者过滤
+func (r *eventResolver) Tickets(ctx context.Context, args struct{ Holder *string }) ([]*ticketResolver, error) {
+	tickets, err := loadersFrom(ctx).tickets.Load(ctx, eventKeyOf(r.e.ChainID, r.e.EventID))
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]*ticketResolver, 0, len(tickets))
+	for i := range tickets {
+		if args.Holder != nil && !tickets[i].Holder.Equal(models.Address(*args.Holder)) {
+			continue
+		}
+		resolvers = append(resolvers, newTicketResolver(&tickets[i]))
+	}
+	return resolvers, nil
+}
+
+// loadEvent 通过 dataloader 加载关联活动
+func loadEvent(ctx context.Context, chainID uint64, eventID string) (*eventResolver, error) {
+	event, err := loadersFrom(ctx).events.Load(ctx, eventKeyOf(chainID, eventID))
+	if err != nil || event == nil {
+		return nil, err
+	}
+	return newEventResolver(event), nil
+}
+
+// participantResolver 参与者
+type participantResolver struct {
+	p *models.Participant
+}
+
+func newParticipantResolver(p *models.Participant) *participantResolver {
+	return &participantResolver{p}
+}
+
+func (r *participantResolver) ID() graphql.ID          { return modelID(r.p.ID) }
+func (r *participantResolver) ChainID() Long           { return Long(r.p.ChainID) }
+func (r *participantResolver) Network() string         { return r.p.Network }
+func (r *participantResolver) ContractAddress() string { return r.p.ContractAddress }
+func (r *participantResolver) EventID() string         { return r.p.EventID }
+func (r *participantResolver) RegisteredAt() Long      { return Long(r.p.RegisteredAt) }
+func (r *participantResolver) CheckedIn() bool         { return r.p.CheckedIn }
+func (r *participantResolver) CheckInTime() Long       { return Long(r.p.CheckInTime) }
+func (r *participantResolver) CheckInStatus() string   { return r.p.CheckInStatus }
+func (r *participantResolver) CreatedAt() graphql.Time { return graphql.Time{Time: r.p.CreatedAt} }
+func (r *participantResolver) UpdatedAt() graphql.Time { return graphql.Time{Time: r.p.UpdatedAt} }
+
+func (r *participantResolver) Wallet() string { return r.p.Wallet.String() }
+
+// Name 无权查看时返回空字符串
+func (r *participantResolver) Name(ctx context.Context) (string, error) {
+	ok, err := canViewParticipant(ctx, r.p)
+	if err != nil || ok {
+		return r.p.Name, err
+	}
+	return "", nil
+}
+
+func (r *participantResolver) Event(ctx context.Context) (*eventResolver, error) {
+	return loadEvent(ctx, r.p.ChainID, r.p.EventID)
+}
+
+// Ticket 参与者在该活动的门票
+func (r *participantResolver) Ticket(ctx context.Context) (*ticketResolver, error) {
+	tickets, err := loadersFrom(ctx).tickets.Load(ctx, eventKeyOf(r.p.ChainID, r.p.EventID))
+	if err != nil {
+		return nil, err
+	}
+	for i := range tickets {
+		if tickets[i].Holder.Equal(r.p.Wallet) {
+			return newTicketResolver(&tickets[i]), nil
+		}
+	}
+	return nil, nil
+}
+
+// sponsorResolver 赞助商
+type sponsorResolver struct {
+	s *models.Sponsor
+}
+
+func newSponsorResolver(s *models.Sponsor) *sponsorResolver { return &sponsorResolver{s} }
+
+func (r *sponsorResolver) ID() graphql.ID          { return modelID(r.s.ID) }
+func (r *sponsorResolver) ChainID() Long           { return Long(r.s.ChainID) }
+func (r *sponsorResolver) Network() string         { return r.s.Network }
+func (r *sponsorResolver) ContractAddress() string { return r.s.ContractAddress }
+func (r *sponsorResolver) EventID() string         { return r.s.EventID }
+func (r *sponsorResolver) Wallet() string          { return r.s.Wallet.String() }
+func (r *sponsorResolver) Name() string            { return r.s.Name }
+func (r *sponsorResolver) Amount() string          { return r.s.Amount }
+func (r *sponsorResolver) SponsoredAt() Long       { return Long(r.s.SponsoredAt) }
+func (r *sponsorResolver) CreatedAt() graphql.Time { return graphql.Time{Time: r.s.CreatedAt} }
+
+func (r *sponsorResolver) Event(ctx context.Context) (*eventResolver, error) {
+	return loadEvent(ctx, r.s.ChainID, r.s.EventID)
+}
+
+// ticketResolver NFT 门票
+type ticketResolver struct {
+	t *models.NFTTicket
+}
+
+func newTicketResolver(t *models.NFTTicket) *ticketResolver { return &ticketResolver{t} }
+
+func (r *ticketResolver) ID() graphql.ID          { return modelID(r.t.ID) }
+func (r *ticketResolver) ChainID() Long           { return Long(r.t.ChainID) }
+func (r *ticketResolver) Network() string         { return r.t.Network }
+func (r *ticketResolver) ContractAddress() string { return r.t.ContractAddress }
+func (r *ticketResolver) TokenID() string         { return r.t.TokenID }
+func (r *ticketResolver) EventID() string         { return r.t.EventID }
+func (r *ticketResolver) Holder() string          { return r.t.Holder.String() }
+func (r *ticketResolver) EventTitle() string      { return r.t.EventTitle }
+func (r *ticketResolver) Location() string        { return r.t.Location }
+func (r *ticketResolver) StartTime() Long         { return Long(r.t.StartTime) }
+func (r *ticketResolver) EndTime() Long           { return Long(r.t.EndTime) }
+func (r *ticketResolver) Used() bool              { return r.t.Used }
+func (r *ticketResolver) IssuedAt() Long          { return Long(r.t.IssuedAt) }
+func (r *ticketResolver) CreatedAt() graphql.Time { return graphql.Time{Time: r.t.CreatedAt} }
+func (r *ticketResolver) UpdatedAt() graphql.Time { return graphql.Time{Time: r.t.UpdatedAt} }
+
+func (r *ticketResolver) Event(ctx context.Context) (*eventResolver, error) {
+	return loadEvent(ctx, r.t.ChainID, r.t.EventID)
+}
+
+// Participant 持有者在该活动的参与记录
+func (r *ticketResolver) Participant(ctx context.Context) (*participantResolver, error) {
+	participants, err := loadersFrom(ctx).participants.Load(ctx, eventKeyOf(r.t.ChainID, r.t.EventID))
+	if err != nil {
+		return nil, err
+	}
+	if p := findParticipant(participants, r.t.Holder); p != nil {
+		return newParticipantResolver(p), nil
+	}
+	return nil, nil
+}
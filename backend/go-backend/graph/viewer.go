@@ -0,0 +1,38 @@
+package graph
+
+import (
+	"context"
+
+	"hackathon-backend/models"
+	"hackathon-backend/services"
+)
+
+// viewer 发起 GraphQL 请求的登录钱包和授权规则
+type viewer struct {
+	session *services.SessionClaims
+	policy  *services.AuthPolicy
+}
+
+type viewerKey struct{}
+
+// WithViewer 将当前登录信息放入请求上下文，session 为 nil 表示匿名
+func WithViewer(ctx context.Context, session *services.SessionClaims, policy *services.AuthPolicy) context.Context {
+	return context.WithValue(ctx, viewerKey{}, &viewer{session: session, policy: policy})
+}
+
+// canViewParticipant 参与者本人、活动组织者和管理员可以看到参与者姓名
+func canViewParticipant(ctx context.Context, p *models.Participant) (bool, error) {
+	v, _ := ctx.Value(viewerKey{}).(*viewer)
+	if v == nil || v.session == nil {
+		return false, nil
+	}
+	if p.Wallet.Equal(v.session.Wallet()) || v.policy.IsAdmin(v.session) {
+		return true, nil
+	}
+
+	event, err := loadersFrom(ctx).events.Load(ctx, eventKeyOf(p.ChainID, p.EventID))
+	if err != nil {
+		return false, err
+	}
+	return v.policy.IsOrganizer(v.session, event), nil
+}
@@ -6,9 +6,9 @@ import (
 	"log"
 	"time"
 
+	"hackathon-backend/apidocs"
 	"hackathon-backend/blockchain"
 	"hackathon-backend/config"
-	"hackathon-backend/controllers"
 	"hackathon-backend/database"
 	"hackathon-backend/repositories"
 	"hackathon-backend/services"
@@ -19,7 +19,7 @@ import (
 func main() {
 	// 加载配置
 	cfg := config.LoadConfig()
-	log.Printf("📋 Config loaded: %+v", cfg)
+	log.Printf("📋 Config loaded: %+v", cfg.Redacted())
 
 	// 初始化数据库
 	if err := database.Init(cfg); err != nil {
@@ -33,63 +33,23 @@ func main() {
 	}
 	defer blockchain.BlockchainClientInstance.Close()
 
-	// 初始化 MVC 层
-	db := database.GetDB()
-	eventRepo := repositories.NewEventRepository(db)
-	eventService := services.NewEventService(eventRepo)
-	eventController := controllers.NewEventController(eventService)
+	// 初始化交易管理器和签到中继器（可选）
+	txManager := blockchain.InitTxManager(cfg, blockchain.GetInstance(), repositories.NewTransactionRepository(database.GetDB()))
+	relayer, err := blockchain.InitRelayer(cfg, blockchain.GetInstance(), txManager)
+	if err != nil {
+		log.Fatalf("❌ Failed to initialize relayer: %v", err)
+	}
 
-	// 启动事件订阅 (WebSocket)
-	go func() {
-		log.Println("🚀 Starting WebSocket event listener...")
-		for {
-			if err := eventService.SubscribeEvents(context.Background()); err != nil {
-				log.Printf("❌ Event subscription failed: %v. Retrying in 5 seconds...", err)
-				time.Sleep(5 * time.Second)
-			}
-		}
-	}()
+	// 初始化 MVC 层并启动后台任务
+	router, tasks := newRouter(database.GetDB(), txManager, relayer)
+	for _, task := range tasks {
+		go task(context.Background())
+	}
 
 	// 启动同步 goroutine (Deprecated)
 	// go startSyncWorker(eventService, cfg.SyncInterval)
 
-	// 设置 Gin 路由
-	router := gin.Default()
-
-	// 启用 CORS
-	router.Use(func(c *gin.Context) {
-		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
-		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE")
-
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(204)
-			return
-		}
-
-		c.Next()
-	})
-
-	// 健康检查
-	router.GET("/health", eventController.Health)
-
-	// 活动相关 API
-	router.GET("/api/events", eventController.GetAllEvents)
-	router.GET("/api/events/:id", eventController.GetEventByID)
-	router.GET("/api/events/organizer", eventController.GetEventsByOrganizer)
-	router.GET("/api/events/:id/participants", eventController.GetEventParticipants)
-	router.GET("/api/events/:id/sponsors", eventController.GetEventSponsors)
-	router.GET("/api/events/:id/tickets", eventController.GetEventTickets)
-
-	// 门票相关 API
-	router.GET("/api/tickets", eventController.GetTicketsByHolder)
-
-	// 统计 API
-	router.GET("/api/stats", eventController.GetSyncStats)
-
-	// 测试 API
-	router.POST("/api/test/event", eventController.CreateTestEvent)
+	checkAPIDocs(router)
 
 	// 启动服务器
 	log.Printf("🚀 Server starting on port %d", cfg.ServerPort)
@@ -99,6 +59,24 @@ func main() {
 	}
 }
 
+// checkAPIDocs 校验已注册路由与 OpenAPI 文档一致，不一致时输出警告
+func checkAPIDocs(router *gin.Engine) {
+	undocumented, missing, err := apidocs.CheckRoutes(router.Routes())
+	if err != nil {
+		log.Printf("⚠️ Failed to check OpenAPI spec: %v", err)
+		return
+	}
+	for _, route := range undocumented {
+		log.Printf("⚠️ Route not documented in openapi.json: %s", route)
+	}
+	for _, route := range missing {
+		log.Printf("⚠️ openapi.json documents unregistered route: %s", route)
+	}
+	if len(undocumented) == 0 && len(missing) == 0 {
+		log.Printf("📘 OpenAPI spec matches %d registered routes", len(router.Routes()))
+	}
+}
+
 // startSyncWorker 启动同步 worker
 func startSyncWorker(service *services.EventService, interval int) {
 	ticker := time.NewTicker(time.Duration(interval) * time.Second)
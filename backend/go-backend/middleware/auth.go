@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"hackathon-backend/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// SessionCookie 登录后写入的会话 Cookie，EventSource 等无法设置请求头的客户端可依赖它
+	SessionCookie = "siwe_session"
+
+	sessionKey = "auth.session"
+)
+
+// bearerToken 从 Authorization: Bearer 请求头或会话 Cookie 中读取令牌
+func bearerToken(ctx *gin.Context) string {
+	if header := ctx.GetHeader("Authorization"); header != "" {
+		if scheme, token, ok := strings.Cut(header, " "); ok && strings.EqualFold(scheme, "Bearer") {
+			return strings.TrimSpace(token)
+		}
+		return ""
+	}
+	if cookie, err := ctx.Cookie(SessionCookie); err == nil {
+		return cookie
+	}
+	return ""
+}
+
+// OptionalAuth 携带有效令牌时写入登录钱包，未携带或令牌无效时按匿名处理；
+// 无效令牌（如过期的 Cookie）不拦截请求，以免影响公开接口和重新登录
+func OptionalAuth(auth *services.AuthService) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if token := bearerToken(ctx); token != "" {
+			if claims, err := auth.ParseToken(token); err == nil {
+				ctx.Set(sessionKey, claims)
+			}
+		}
+		ctx.Next()
+	}
+}
+
+// RequireAuth 要求携带有效令牌，否则返回 401
+func RequireAuth(auth *services.AuthService) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		// 已由 OptionalAuth 解析过
+		if CurrentSession(ctx) != nil {
+			ctx.Next()
+			return
+		}
+
+		token := bearerToken(ctx)
+		if token == "" {
+			AbortWithAuthError(ctx, services.ErrUnauthenticated)
+			return
+		}
+
+		claims, err := auth.ParseToken(token)
+		if err != nil {
+			ctx.Header("WWW-Authenticate", `Bearer realm="api", error="invalid_token"`)
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.Set(sessionKey, claims)
+		ctx.Next()
+	}
+}
+
+// RequireAdmin 要求当前钱包为管理员，需在 RequireAuth 之后使用
+func RequireAdmin(policy *services.AuthPolicy) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if err := policy.AuthorizeAdmin(CurrentSession(ctx)); err != nil {
+			AbortWithAuthError(ctx, err)
+			return
+		}
+		ctx.Next()
+	}
+}
+
+// AbortWithAuthError 未登录返回 401，无权限返回 403
+func AbortWithAuthError(ctx *gin.Context, err error) {
+	status := http.StatusForbidden
+	if errors.Is(err, services.ErrUnauthenticated) {
+		ctx.Header("WWW-Authenticate", `Bearer realm="api"`)
+		status = http.StatusUnauthorized
+	}
+	ctx.AbortWithStatusJSON(status, gin.H{"error": err.Error()})
+}
+
+// CurrentSession 返回当前请求的登录信息，未登录时返回 nil
+func CurrentSession(ctx *gin.Context) *services.SessionClaims {
+	if value, ok := ctx.Get(sessionKey); ok {
+		return value.(*services.SessionClaims)
+	}
+	return nil
+}
+
+// CurrentWallet 返回当前请求的登录钱包地址（EIP-55 格式），未登录时返回空字符串
+func CurrentWallet(ctx *gin.Context) string {
+	if session := CurrentSession(ctx); session != nil {
+		return session.Address
+	}
+	return ""
+}
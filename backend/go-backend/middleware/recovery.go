@@ -0,0 +1,18 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Recovery 与 gin.Recovery 相同，但放行 http.ErrAbortHandler：
+// 响应头已发送后处理函数用它中止请求，net/http 会直接断开连接，客户端不会把不完整的响应当成成功
+func Recovery() gin.HandlerFunc {
+	return gin.CustomRecovery(func(ctx *gin.Context, err any) {
+		if err == http.ErrAbortHandler {
+			panic(err)
+		}
+		ctx.AbortWithStatus(http.StatusInternalServerError)
+	})
+}
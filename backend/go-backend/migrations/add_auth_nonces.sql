@@ -0,0 +1,14 @@
+-- 添加 SIWE 登录 nonce 表
+-- 执行日期: 2026-10-18
+-- 注意：GORM 自动迁移会创建该表，此脚本用于手动建表
+
+CREATE TABLE IF NOT EXISTS `auth_nonces` (
+  `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  `nonce` VARCHAR(64) NOT NULL,
+  `expires_at` DATETIME(3) NULL,
+  `used_at` DATETIME(3) NULL COMMENT '登录成功后标记，防止重放',
+  `created_at` DATETIME(3) NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_auth_nonces_nonce` (`nonce`),
+  INDEX `idx_auth_nonces_expires_at` (`expires_at`)
+);
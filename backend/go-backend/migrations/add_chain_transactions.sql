@@ -0,0 +1,42 @@
+-- 添加链上交易表（交易管理器），并为中继签到任务关联交易记录
+-- 执行日期: 2026-10-18
+-- 注意：GORM 自动迁移会创建该表和字段，此脚本用于手动迁移
+
+CREATE TABLE IF NOT EXISTS `chain_transactions` (
+  `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  `chain_id` BIGINT UNSIGNED NULL,
+  `network` VARCHAR(50) NULL,
+  `from_address` VARCHAR(42) NULL,
+  `nonce` BIGINT UNSIGNED NULL COMMENT '广播失败释放后为 NULL',
+  `to_address` VARCHAR(42) NULL,
+  `value` VARCHAR(100) NULL,
+  `data` MEDIUMTEXT NULL,
+  `gas` BIGINT UNSIGNED NULL,
+  `tx_type` TINYINT UNSIGNED NULL COMMENT '0 legacy, 2 EIP-1559',
+  `gas_price` VARCHAR(100) NULL,
+  `gas_tip_cap` VARCHAR(100) NULL,
+  `gas_fee_cap` VARCHAR(100) NULL,
+  `tx_hash` VARCHAR(66) NULL COMMENT '当前（最近一次广播的）哈希',
+  `hashes` TEXT NULL COMMENT '所有广播过的哈希，逗号分隔',
+  `raw_tx` MEDIUMTEXT NULL,
+  `status` VARCHAR(20) NULL COMMENT 'pending, confirmed, reverted, failed, replaced',
+  `purpose` VARCHAR(50) NULL,
+  `reference` VARCHAR(100) NULL,
+  `attempts` BIGINT NULL,
+  `last_sent_at` DATETIME(3) NULL,
+  `block_number` BIGINT UNSIGNED NULL,
+  `gas_used` BIGINT UNSIGNED NULL,
+  `effective_gas_price` VARCHAR(100) NULL,
+  `error` TEXT NULL,
+  `confirmed_at` DATETIME(3) NULL,
+  `created_at` DATETIME(3) NULL,
+  `updated_at` DATETIME(3) NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_chain_tx_nonce` (`chain_id`, `from_address`, `nonce`),
+  INDEX `idx_chain_tx_status` (`chain_id`, `status`),
+  INDEX `idx_chain_transactions_tx_hash` (`tx_hash`),
+  INDEX `idx_chain_transactions_reference` (`reference`)
+);
+
+ALTER TABLE `relayed_checkins` ADD COLUMN `tx_id` BIGINT UNSIGNED NULL AFTER `status`;
+CREATE INDEX `idx_relayed_checkins_tx_id` ON `relayed_checkins` (`tx_id`);
@@ -0,0 +1,10 @@
+-- 为 events 表添加全文索引，用于 /api/events/search
+-- 执行日期: 2026-10-18
+-- 注意：需要 MySQL 5.7.6+（ngram 解析器支持中文分词），GORM 自动迁移也会创建该索引
+
+ALTER TABLE `events` ADD FULLTEXT INDEX `idx_event_fulltext` (`title`, `description`, `location`) WITH PARSER ngram;
+
+-- 验证
+-- SHOW INDEX FROM events WHERE Key_name = 'idx_event_fulltext';
+-- SELECT id, title, MATCH(title, description, location) AGAINST ('黑客松' IN NATURAL LANGUAGE MODE) AS score
+--   FROM events WHERE MATCH(title, description, location) AGAINST ('黑客松' IN NATURAL LANGUAGE MODE);
@@ -0,0 +1,48 @@
+-- 添加离线签到：扫码设备授权表、离线签到记录表，参与者增加签到状态
+-- 执行日期: 2026-10-18
+-- 注意：GORM 自动迁移会创建表和列，此脚本用于手动迁移
+
+ALTER TABLE `participants`
+  ADD COLUMN `check_in_status` VARCHAR(20) NULL COMMENT 'provisional（离线签到尚未上链）, confirmed' AFTER `check_in_time`;
+
+-- 已从链上同步的签到标记为 confirmed
+UPDATE `participants` SET `check_in_status` = 'confirmed' WHERE `checked_in` = 1;
+
+CREATE TABLE IF NOT EXISTS `checkin_devices` (
+  `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  `chain_id` BIGINT UNSIGNED NULL,
+  `event_id` VARCHAR(100) NULL,
+  `address` VARCHAR(42) NULL COMMENT '设备密钥地址',
+  `label` VARCHAR(100) NULL,
+  `authorized_by` VARCHAR(42) NULL COMMENT '授权的组织者/管理员钱包',
+  `revoked_at` DATETIME(3) NULL,
+  `created_at` DATETIME(3) NULL,
+  `updated_at` DATETIME(3) NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_checkin_device` (`chain_id`, `event_id`, `address`)
+);
+
+CREATE TABLE IF NOT EXISTS `offline_checkins` (
+  `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  `chain_id` BIGINT UNSIGNED NULL,
+  `event_id` VARCHAR(100) NULL,
+  `participant` VARCHAR(42) NULL,
+  `token_id` VARCHAR(100) NULL,
+  `device` VARCHAR(42) NULL COMMENT '签名的设备密钥地址',
+  `scanned_at` BIGINT NULL COMMENT '设备记录的签到时间（Unix 秒）',
+  `nonce` VARCHAR(64) NULL,
+  `signature` VARCHAR(132) NULL,
+  `status` VARCHAR(20) NULL COMMENT 'provisional, submitted, settled, conflict',
+  `relay_job_id` BIGINT UNSIGNED NULL COMMENT '中继签到任务 ID',
+  `tx_hash` VARCHAR(66) NULL,
+  `error` TEXT NULL,
+  `settled_at` DATETIME(3) NULL,
+  `created_at` DATETIME(3) NULL,
+  `updated_at` DATETIME(3) NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_offline_checkin_participant` (`chain_id`, `event_id`, `participant`),
+  UNIQUE INDEX `idx_offline_checkins_nonce` (`nonce`),
+  INDEX `idx_offline_checkin_status` (`chain_id`, `status`),
+  INDEX `idx_offline_checkins_token_id` (`token_id`),
+  INDEX `idx_offline_checkins_device` (`device`)
+);
@@ -0,0 +1,7 @@
+-- 中继签到任务增加批次 ID
+-- 执行日期: 2026-10-18
+-- 注意：GORM 自动迁移会添加该列，此脚本用于手动迁移
+
+ALTER TABLE `relayed_checkins`
+  ADD COLUMN `batch_id` VARCHAR(32) NULL COMMENT '批量签到 ID' AFTER `relayer`,
+  ADD INDEX `idx_relayed_checkins_batch_id` (`batch_id`);
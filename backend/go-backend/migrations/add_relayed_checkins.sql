@@ -0,0 +1,27 @@
+-- 添加中继签到任务表
+-- 执行日期: 2026-10-18
+-- 注意：GORM 自动迁移会创建该表，此脚本用于手动建表
+
+CREATE TABLE IF NOT EXISTS `relayed_checkins` (
+  `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  `chain_id` BIGINT UNSIGNED NULL,
+  `event_id` VARCHAR(100) NULL,
+  `participant` VARCHAR(42) NULL,
+  `token_id` VARCHAR(100) NULL,
+  `requested_by` VARCHAR(42) NULL COMMENT '发起请求的组织者/管理员钱包',
+  `relayer` VARCHAR(42) NULL COMMENT '签名交易的委托地址',
+  `status` VARCHAR(20) NULL COMMENT 'queued, submitted, confirmed, failed',
+  `tx_hash` VARCHAR(66) NULL,
+  `nonce` BIGINT UNSIGNED NULL,
+  `block_number` BIGINT UNSIGNED NULL,
+  `gas_used` BIGINT UNSIGNED NULL,
+  `error` TEXT NULL,
+  `submitted_at` DATETIME(3) NULL,
+  `confirmed_at` DATETIME(3) NULL,
+  `created_at` DATETIME(3) NULL,
+  `updated_at` DATETIME(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_relay_checkin_participant` (`chain_id`, `event_id`, `participant`),
+  INDEX `idx_relayed_checkins_status` (`status`),
+  INDEX `idx_relayed_checkins_tx_hash` (`tx_hash`)
+);
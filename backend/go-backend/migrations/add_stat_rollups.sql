@@ -0,0 +1,22 @@
+-- 添加平台统计汇总表（GET /api/stats/timeseries）
+-- 执行日期: 2026-10-18
+-- 注意：GORM 自动迁移会创建该表，此脚本用于手动建表；服务启动后汇总任务发现表为空会全量计算
+
+CREATE TABLE IF NOT EXISTS `stat_rollups` (
+  `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  `interval` VARCHAR(10) NULL COMMENT 'day, week, month',
+  `period_start` BIGINT NULL COMMENT '周期起始时间（UTC，Unix 秒）',
+  `chain_id` BIGINT UNSIGNED NULL,
+  `network` VARCHAR(50) NULL,
+  `events_started` BIGINT NULL,
+  `active_participants` BIGINT NULL,
+  `registrations` BIGINT NULL,
+  `check_ins` BIGINT NULL,
+  `cohort_registrations` BIGINT NULL,
+  `cohort_check_ins` BIGINT NULL,
+  `sponsor_count` BIGINT NULL,
+  `sponsor_amount` VARCHAR(78) NULL COMMENT '赞助总额（原生代币最小单位）',
+  `updated_at` DATETIME(3) NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_stat_rollup` (`interval`, `period_start`, `chain_id`)
+);
@@ -0,0 +1,33 @@
+-- 添加同步检查点表（每条链每个合约一行）
+-- 执行日期: 2026-10-18
+-- 注意：GORM 自动迁移会创建该表，此脚本用于手动建表并从 sync_logs 初始化检查点
+
+-- 1. 创建 sync_checkpoints 表
+CREATE TABLE IF NOT EXISTS `sync_checkpoints` (
+  `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  `chain_id` BIGINT UNSIGNED NOT NULL COMMENT '链ID',
+  `network` VARCHAR(50) NOT NULL COMMENT '网络名称',
+  `contract_address` VARCHAR(42) NOT NULL COMMENT '合约地址',
+  `last_block` BIGINT UNSIGNED NOT NULL DEFAULT 0 COMMENT '最后处理的区块',
+  `block_hash` VARCHAR(66) NOT NULL DEFAULT '' COMMENT '最后处理区块的哈希',
+  `updated_at` DATETIME(3) NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_checkpoint_chain_contract` (`chain_id`, `contract_address`),
+  INDEX `idx_sync_checkpoints_network` (`network`)
+);
+
+-- 2. 从已有数据初始化检查点（取每条链每个合约已入库记录的最大区块）
+-- sync_logs 不区分合约，这里用该链最高的成功区块作为所有合约的初始检查点
+INSERT INTO `sync_checkpoints` (`chain_id`, `network`, `contract_address`, `last_block`, `updated_at`)
+SELECT e.`chain_id`, e.`network`, e.`contract_address`, MAX(l.`block_number`), NOW(3)
+FROM (
+    SELECT DISTINCT `chain_id`, `network`, `contract_address` FROM `events`
+    UNION
+    SELECT DISTINCT `chain_id`, `network`, `contract_address` FROM `nft_tickets`
+) e
+JOIN `sync_logs` l ON l.`chain_id` = e.`chain_id` AND l.`status` = 'success'
+GROUP BY e.`chain_id`, e.`network`, e.`contract_address`
+ON DUPLICATE KEY UPDATE `last_block` = GREATEST(`last_block`, VALUES(`last_block`));
+
+-- 3. 验证
+-- SELECT * FROM sync_checkpoints;
@@ -0,0 +1,19 @@
+-- 添加门票二维码核验记录表
+-- 执行日期: 2026-10-18
+-- 注意：GORM 自动迁移会创建该表，此脚本用于手动建表
+
+CREATE TABLE IF NOT EXISTS `ticket_pass_uses` (
+  `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  `nonce` VARCHAR(64) NULL COMMENT '二维码一次性 nonce，防止重放',
+  `chain_id` BIGINT UNSIGNED NULL,
+  `token_id` VARCHAR(100) NULL,
+  `event_id` VARCHAR(100) NULL,
+  `holder` VARCHAR(42) NULL,
+  `signer_type` VARCHAR(20) NULL COMMENT 'holder, server',
+  `verified_by` VARCHAR(42) NULL COMMENT '核验的组织者/管理员钱包',
+  `created_at` DATETIME(3) NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_ticket_pass_uses_nonce` (`nonce`),
+  UNIQUE INDEX `idx_ticket_pass_use_token` (`chain_id`, `token_id`),
+  INDEX `idx_ticket_pass_uses_event_id` (`event_id`)
+);
@@ -0,0 +1,23 @@
+-- 添加门票转让记录表（活动分析中的转让统计）
+-- 执行日期: 2026-10-18
+-- 注意：GORM 自动迁移会创建该表，此脚本用于手动建表；此前的转让不会回填
+
+CREATE TABLE IF NOT EXISTS `ticket_transfers` (
+  `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  `chain_id` BIGINT UNSIGNED NULL,
+  `network` VARCHAR(50) NULL,
+  `contract_address` VARCHAR(42) NULL,
+  `event_id` VARCHAR(100) NULL,
+  `token_id` VARCHAR(100) NULL,
+  `from_address` VARCHAR(42) NULL,
+  `to_address` VARCHAR(42) NULL,
+  `block_number` BIGINT UNSIGNED NULL,
+  `tx_hash` VARCHAR(66) NULL,
+  `log_index` BIGINT UNSIGNED NULL,
+  `transferred_at` BIGINT NULL COMMENT '区块时间（Unix 秒）',
+  `created_at` DATETIME(3) NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_ticket_transfer_log` (`chain_id`, `tx_hash`, `log_index`),
+  INDEX `idx_ticket_transfer_event` (`chain_id`, `event_id`),
+  INDEX `idx_ticket_transfers_token_id` (`token_id`)
+);
@@ -0,0 +1,52 @@
+-- 添加 Webhook 订阅、投递和投递日志表
+-- 执行日期: 2026-10-18
+-- 注意：GORM 自动迁移会创建这些表，此脚本用于手动建表
+
+CREATE TABLE IF NOT EXISTS `webhook_subscriptions` (
+  `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  `organizer` VARCHAR(42) NOT NULL COMMENT '组织者钱包地址',
+  `event_id` VARCHAR(100) NOT NULL DEFAULT '' COMMENT '为空时订阅该组织者的所有活动',
+  `url` VARCHAR(1024) NOT NULL COMMENT '回调地址',
+  `secret` VARCHAR(128) NOT NULL COMMENT 'HMAC 签名密钥',
+  `event_types` VARCHAR(500) NOT NULL DEFAULT '' COMMENT '逗号分隔的事件类型，为空时接收所有类型',
+  `active` TINYINT(1) NOT NULL DEFAULT 1,
+  `created_at` DATETIME(3) NULL,
+  `updated_at` DATETIME(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_webhook_subscriptions_organizer` (`organizer`),
+  INDEX `idx_webhook_subscriptions_event_id` (`event_id`)
+);
+
+CREATE TABLE IF NOT EXISTS `webhook_deliveries` (
+  `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  `subscription_id` BIGINT UNSIGNED NOT NULL,
+  `event_type` VARCHAR(50) NOT NULL,
+  `event_id` VARCHAR(100) NOT NULL DEFAULT '',
+  `payload` MEDIUMTEXT NOT NULL COMMENT '请求体 JSON',
+  `status` VARCHAR(20) NOT NULL COMMENT 'pending, succeeded, failed',
+  `attempts` BIGINT NOT NULL DEFAULT 0,
+  `next_attempt_at` DATETIME(3) NULL,
+  `last_attempt_at` DATETIME(3) NULL,
+  `response_status` BIGINT NOT NULL DEFAULT 0,
+  `last_error` TEXT NULL,
+  `redelivery_of` BIGINT UNSIGNED NULL COMMENT '手动重新投递时指向原投递',
+  `created_at` DATETIME(3) NULL,
+  `updated_at` DATETIME(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_webhook_deliveries_subscription_id` (`subscription_id`),
+  INDEX `idx_webhook_delivery_due` (`status`, `next_attempt_at`),
+  INDEX `idx_webhook_deliveries_redelivery_of` (`redelivery_of`)
+);
+
+CREATE TABLE IF NOT EXISTS `webhook_delivery_attempts` (
+  `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+  `delivery_id` BIGINT UNSIGNED NOT NULL,
+  `attempt` BIGINT NOT NULL,
+  `response_status` BIGINT NOT NULL DEFAULT 0,
+  `response_body` TEXT NULL COMMENT '截断后的响应内容',
+  `error` TEXT NULL,
+  `duration_ms` BIGINT NOT NULL DEFAULT 0,
+  `created_at` DATETIME(3) NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_webhook_delivery_attempts_delivery_id` (`delivery_id`)
+);
@@ -0,0 +1,19 @@
+-- 地址统一为 EIP-55 校验和格式
+-- 执行日期: 2026-10-18
+-- 注意：校验和需要 keccak256，无法在 SQL 中计算；改写由服务启动时的 models.NormalizeAddresses 完成，
+-- 已规范化的数据不会被修改。此脚本用于迁移前后检查非校验和格式的地址
+
+-- 1. 统计全小写或全大写的地址（启动后应为 0，纯数字地址除外）
+SELECT 'events.organizer' AS col, COUNT(*) FROM `events` WHERE `organizer` <> '' AND BINARY `organizer` IN (BINARY LOWER(`organizer`), BINARY CONCAT('0x', UPPER(SUBSTRING(`organizer`, 3))))
+UNION ALL
+SELECT 'participants.wallet', COUNT(*) FROM `participants` WHERE `wallet` <> '' AND BINARY `wallet` IN (BINARY LOWER(`wallet`), BINARY CONCAT('0x', UPPER(SUBSTRING(`wallet`, 3))))
+UNION ALL
+SELECT 'sponsors.wallet', COUNT(*) FROM `sponsors` WHERE `wallet` <> '' AND BINARY `wallet` IN (BINARY LOWER(`wallet`), BINARY CONCAT('0x', UPPER(SUBSTRING(`wallet`, 3))))
+UNION ALL
+SELECT 'nft_tickets.holder', COUNT(*) FROM `nft_tickets` WHERE `holder` <> '' AND BINARY `holder` IN (BINARY LOWER(`holder`), BINARY CONCAT('0x', UPPER(SUBSTRING(`holder`, 3))));
+
+-- 2. 规范化后同一活动可能出现大小写不同的重复参与者，检查后保留 id 较大的一条
+SELECT `chain_id`, `contract_address`, `event_id`, LOWER(`wallet`) AS wallet, COUNT(*)
+FROM `participants`
+GROUP BY `chain_id`, `contract_address`, `event_id`, LOWER(`wallet`)
+HAVING COUNT(*) > 1;
@@ -0,0 +1,129 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/ethereum/go-ethereum/common"
+	"gorm.io/gorm"
+)
+
+var ErrInvalidAddress = errors.New("invalid address")
+
+// Address 钱包地址，统一以 EIP-55 校验和格式存储和查询，比较时不受输入大小写影响
+type Address string
+
+// ParseAddress 校验并规范化十六进制地址
+func ParseAddress(s string) (Address, error) {
+	if !common.IsHexAddress(s) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidAddress, s)
+	}
+	return Address(common.HexToAddress(s).Hex()), nil
+}
+
+// AddressFrom 由 go-ethereum 地址构造
+func AddressFrom(a common.Address) Address {
+	return Address(a.Hex())
+}
+
+// Canonical 返回校验和格式；空值和非法地址原样返回
+func (a Address) Canonical() Address {
+	if a == "" || !common.IsHexAddress(string(a)) {
+		return a
+	}
+	return Address(common.HexToAddress(string(a)).Hex())
+}
+
+// Common 转换为 go-ethereum 地址
+func (a Address) Common() common.Address {
+	return common.HexToAddress(string(a))
+}
+
+// Equal 不区分大小写比较，非法地址不相等
+func (a Address) Equal(other Address) bool {
+	return common.IsHexAddress(string(a)) && common.IsHexAddress(string(other)) && a.Common() == other.Common()
+}
+
+func (a Address) String() string {
+	return string(a)
+}
+
+// Value 写入和查询参数统一使用校验和格式
+func (a Address) Value() (driver.Value, error) {
+	return string(a.Canonical()), nil
+}
+
+// Scan 读取时规范化历史数据中的小写地址
+func (a *Address) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*a = ""
+	case string:
+		*a = Address(v).Canonical()
+	case []byte:
+		*a = Address(v).Canonical()
+	default:
+		return fmt.Errorf("cannot scan %T into Address", value)
+	}
+	return nil
+}
+
+// UnmarshalJSON 请求体中的地址同样规范化
+func (a *Address) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	*a = Address(s).Canonical()
+	return nil
+}
+
+// addressColumns 需要规范化的地址列
+var addressColumns = []struct {
+	table  string
+	column string
+}{
+	{"events", "organizer"},
+	{"participants", "wallet"},
+	{"sponsors", "wallet"},
+	{"nft_tickets", "holder"},
+	{"ticket_transfers", "from_address"},
+	{"ticket_transfers", "to_address"},
+	{"webhook_subscriptions", "organizer"},
+	{"relayed_checkins", "participant"},
+	{"ticket_pass_uses", "holder"},
+	{"checkin_devices", "address"},
+	{"offline_checkins", "participant"},
+	{"offline_checkins", "device"},
+}
+
+// NormalizeAddresses 将已有数据中非校验和格式的地址改写为校验和格式（校验和需要 keccak，无法用 SQL 完成），已规范化时不做修改
+func NormalizeAddresses(db *gorm.DB) error {
+	for _, c := range addressColumns {
+		var values []string
+		if err := db.Table(c.table).Distinct(c.column).Where(c.column+" <> ''").Pluck(c.column, &values).Error; err != nil {
+			return fmt.Errorf("failed to load %s.%s: %w", c.table, c.column, err)
+		}
+
+		var updated int64
+		for _, raw := range values {
+			canonical := Address(raw).Canonical()
+			if string(canonical) == raw {
+				continue
+			}
+			// 默认排序规则不区分大小写，使用 BINARY 只匹配原值
+			result := db.Table(c.table).Where("BINARY "+c.column+" = ?", raw).Update(c.column, string(canonical))
+			if result.Error != nil {
+				return fmt.Errorf("failed to normalize %s.%s: %w", c.table, c.column, result.Error)
+			}
+			updated += result.RowsAffected
+		}
+		if updated > 0 {
+			log.Printf("🔧 Normalized %d addresses in %s.%s", updated, c.table, c.column)
+		}
+	}
+	return nil
+}
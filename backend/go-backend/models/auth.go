@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// AuthNonce SIWE 登录使用的一次性 nonce
+type AuthNonce struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	Nonce     string     `gorm:"type:varchar(64);uniqueIndex" json:"nonce"`
+	ExpiresAt time.Time  `gorm:"index" json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at"` // 登录成功后标记，防止重放
+	CreatedAt time.Time  `json:"created_at"`
+}
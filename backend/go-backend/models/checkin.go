@@ -0,0 +1,65 @@
+package models
+
+import "time"
+
+// TicketPassUse 已核验的门票二维码，nonce 和 (链, Token ID) 唯一，用于防止重放和重复入场
+type TicketPassUse struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	Nonce      string    `gorm:"type:varchar(64);uniqueIndex" json:"nonce"`
+	ChainID    uint64    `gorm:"uniqueIndex:idx_ticket_pass_use_token,priority:1" json:"chain_id"`
+	TokenID    string    `gorm:"type:varchar(100);uniqueIndex:idx_ticket_pass_use_token,priority:2" json:"token_id"`
+	EventID    string    `gorm:"type:varchar(100);index" json:"event_id"`
+	Holder     Address   `gorm:"type:varchar(42)" json:"holder"`
+	SignerType string    `gorm:"type:varchar(20)" json:"signer_type"` // holder, server
+	VerifiedBy string    `gorm:"type:varchar(42)" json:"verified_by"` // 核验的组织者/管理员钱包
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// CheckInDevice 组织者授权的扫码设备密钥，设备离线时用该密钥签名签到记录
+type CheckInDevice struct {
+	ID           uint       `gorm:"primaryKey" json:"id"`
+	ChainID      uint64     `gorm:"uniqueIndex:idx_checkin_device,priority:1" json:"chain_id"`
+	EventID      string     `gorm:"type:varchar(100);uniqueIndex:idx_checkin_device,priority:2" json:"event_id"`
+	Address      Address    `gorm:"type:varchar(42);uniqueIndex:idx_checkin_device,priority:3" json:"address"` // 设备密钥地址
+	Label        string     `gorm:"type:varchar(100)" json:"label"`
+	AuthorizedBy string     `gorm:"type:varchar(42)" json:"authorized_by"` // 授权的组织者/管理员钱包
+	RevokedAt    *time.Time `json:"revoked_at,omitempty"`                  // 撤销前扫描的记录仍然有效
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+func (CheckInDevice) TableName() string {
+	return "checkin_devices"
+}
+
+// 离线签到状态
+const (
+	OfflineCheckInProvisional = "provisional" // 已记录，等待上链
+	OfflineCheckInSubmitted   = "submitted"   // 已交给中继器提交
+	OfflineCheckInSettled     = "settled"     // 已同步到链上 ParticipantCheckedIn
+	OfflineCheckInConflict    = "conflict"    // 无法上链或与链上记录冲突，临时签到已撤销
+)
+
+// OfflineCheckIn 扫码设备离线记录的签到，每个参与者在每个活动只保留第一条
+type OfflineCheckIn struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	ChainID     uint64     `gorm:"uniqueIndex:idx_offline_checkin_participant,priority:1;index:idx_offline_checkin_status,priority:1" json:"chain_id"`
+	EventID     string     `gorm:"type:varchar(100);uniqueIndex:idx_offline_checkin_participant,priority:2" json:"event_id"`
+	Participant Address    `gorm:"type:varchar(42);uniqueIndex:idx_offline_checkin_participant,priority:3" json:"participant"`
+	TokenID     string     `gorm:"type:varchar(100);index" json:"token_id,omitempty"`
+	Device      Address    `gorm:"type:varchar(42);index" json:"device"`
+	ScannedAt   int64      `json:"scanned_at"` // 设备记录的签到时间（Unix 秒）
+	Nonce       string     `gorm:"type:varchar(64);uniqueIndex" json:"nonce"`
+	Signature   string     `gorm:"type:varchar(132)" json:"signature"`
+	Status      string     `gorm:"type:varchar(20);index:idx_offline_checkin_status,priority:2" json:"status"` // provisional, submitted, settled, conflict
+	RelayJobID  *uint      `json:"relay_job_id,omitempty"`                                                     // 中继签到任务 ID
+	TxHash      string     `gorm:"type:varchar(66)" json:"tx_hash,omitempty"`                                  // 链上签到交易
+	Error       string     `gorm:"type:text" json:"error,omitempty"`
+	SettledAt   *time.Time `json:"settled_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+func (OfflineCheckIn) TableName() string {
+	return "offline_checkins"
+}
@@ -13,12 +13,12 @@ type Event struct {
 	Network          string    `gorm:"type:varchar(50);index:idx_chain_contract_event,priority:2" json:"network"`   // 网络名称（monad, mantle, somnia）
 	ContractAddress  string    `gorm:"index:idx_chain_contract_event,priority:3" json:"contract_address"`           // 合约地址
 	EventID          string    `gorm:"type:varchar(100);index:idx_chain_contract_event,priority:4" json:"event_id"` // 合约内的事件ID（存储为字符串）
-	Organizer        string    `gorm:"index" json:"organizer"`
-	Title            string    `json:"title"`
-	Description      string    `gorm:"type:text" json:"description"`
+	Organizer        Address   `gorm:"index" json:"organizer"`
+	Title            string    `gorm:"index:idx_event_fulltext,class:FULLTEXT,option:WITH PARSER ngram" json:"title"`
+	Description      string    `gorm:"type:text;index:idx_event_fulltext,class:FULLTEXT,option:WITH PARSER ngram" json:"description"`
 	StartTime        int64     `json:"start_time"`
 	EndTime          int64     `json:"end_time"`
-	Location         string    `json:"location"`
+	Location         string    `gorm:"index:idx_event_fulltext,class:FULLTEXT,option:WITH PARSER ngram" json:"location"`
 	MaxParticipants  uint64    `json:"max_participants"`
 	ParticipantCount uint64    `json:"participant_count"`
 	Active           bool      `json:"active"`
@@ -48,15 +48,22 @@ type Participant struct {
 	Network         string    `gorm:"type:varchar(50);index:idx_chain_contract_event_participant,priority:2" json:"network"` // 网络名称
 	ContractAddress string    `gorm:"index:idx_chain_contract_event_participant,priority:3" json:"contract_address"`
 	EventID         string    `gorm:"type:varchar(100);index:idx_chain_contract_event_participant,priority:4" json:"event_id"`
-	Wallet          string    `gorm:"index:idx_chain_contract_event_participant,priority:5" json:"wallet"`
+	Wallet          Address   `gorm:"index:idx_chain_contract_event_participant,priority:5" json:"wallet"`
 	Name            string    `json:"name"`
 	RegisteredAt    int64     `json:"registered_at"`
 	CheckedIn       bool      `json:"checked_in"`
 	CheckInTime     int64     `json:"check_in_time"`
+	CheckInStatus   string    `gorm:"type:varchar(20)" json:"check_in_status,omitempty"` // provisional, confirmed；未签到时为空
 	CreatedAt       time.Time `json:"created_at"`
 	UpdatedAt       time.Time `json:"updated_at"`
 }
 
+// 参与者签到状态
+const (
+	CheckInStatusProvisional = "provisional" // 扫码设备离线记录，尚未上链
+	CheckInStatusConfirmed   = "confirmed"   // 已从链上 ParticipantCheckedIn 同步
+)
+
 func (Participant) TableName() string {
 	return "participants"
 }
@@ -68,7 +75,7 @@ type Sponsor struct {
 	Network         string    `gorm:"type:varchar(50);index:idx_chain_sponsor,priority:2" json:"network"` // 网络名称
 	ContractAddress string    `gorm:"index:idx_chain_sponsor,priority:3" json:"contract_address"`
 	EventID         string    `gorm:"type:varchar(100);index" json:"event_id"`
-	Wallet          string    `gorm:"index" json:"wallet"`
+	Wallet          Address   `gorm:"index" json:"wallet"`
 	Name            string    `json:"name"`
 	Amount          string    `json:"amount"` // 使用 string 存储大数字
 	SponsoredAt     int64     `json:"sponsored_at"`
@@ -88,7 +95,7 @@ type NFTTicket struct {
 	ContractAddress string    `gorm:"index:idx_chain_contract_token,priority:3" json:"contract_address"`           // NFT合约地址
 	TokenID         string    `gorm:"type:varchar(100);index:idx_chain_contract_token,priority:4" json:"token_id"` // Token ID字符串
 	EventID         string    `gorm:"type:varchar(100);index" json:"event_id"`                                     // Event ID字符串
-	Holder          string    `gorm:"index" json:"holder"`
+	Holder          Address   `gorm:"index" json:"holder"`
 	EventTitle      string    `json:"event_title"`
 	Location        string    `json:"location"`
 	StartTime       int64     `json:"start_time"`
@@ -103,6 +110,27 @@ func (NFTTicket) TableName() string {
 	return "nft_tickets"
 }
 
+// TicketTransfer 门票转让记录（由 TicketTransferred 日志写入）
+type TicketTransfer struct {
+	ID              uint      `gorm:"primaryKey" json:"id"`
+	ChainID         uint64    `gorm:"uniqueIndex:idx_ticket_transfer_log,priority:1;index:idx_ticket_transfer_event,priority:1" json:"chain_id"`
+	Network         string    `gorm:"type:varchar(50)" json:"network"`
+	ContractAddress string    `gorm:"type:varchar(42)" json:"contract_address"`
+	EventID         string    `gorm:"type:varchar(100);index:idx_ticket_transfer_event,priority:2" json:"event_id"`
+	TokenID         string    `gorm:"type:varchar(100);index" json:"token_id"`
+	FromAddress     Address   `gorm:"type:varchar(42)" json:"from"`
+	ToAddress       Address   `gorm:"type:varchar(42)" json:"to"`
+	BlockNumber     uint64    `json:"block_number"`
+	TxHash          string    `gorm:"type:varchar(66);uniqueIndex:idx_ticket_transfer_log,priority:2" json:"tx_hash"`
+	LogIndex        uint      `gorm:"uniqueIndex:idx_ticket_transfer_log,priority:3" json:"log_index"`
+	TransferredAt   int64     `json:"transferred_at"` // 区块时间（Unix 秒）
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+func (TicketTransfer) TableName() string {
+	return "ticket_transfers"
+}
+
 // SyncLog 同步日志
 type SyncLog struct {
 	ID          uint      `gorm:"primaryKey" json:"id"`
@@ -111,15 +139,65 @@ type SyncLog struct {
 	EventType   string    `json:"event_type"`                            // "event", "participant", "sponsor", "ticket"
 	BlockNumber uint64    `json:"block_number"`
 	TxHash      string    `json:"tx_hash"`
-	Status      string    `json:"status"` // "success", "failed"
+	Status      string    `json:"status"` // "received", "success", "failed", "skipped"
 	Error       string    `json:"error,omitempty"`
-	CreatedAt   time.Time `json:"created_at"`
+	CreatedAt   time.Time `gorm:"index" json:"created_at"`
 }
 
 func (SyncLog) TableName() string {
 	return "sync_logs"
 }
 
+// SyncLogDailyStat 同步日志每日汇总（过期的 sync_logs 压缩后保留的统计）
+type SyncLogDailyStat struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	ChainID   uint64    `gorm:"uniqueIndex:idx_sync_log_daily,priority:1" json:"chain_id"`                    // 链ID
+	Network   string    `gorm:"type:varchar(50);uniqueIndex:idx_sync_log_daily,priority:2" json:"network"`    // 网络名称
+	Date      string    `gorm:"type:char(10);uniqueIndex:idx_sync_log_daily,priority:3" json:"date"`          // 日期 YYYY-MM-DD
+	EventType string    `gorm:"type:varchar(50);uniqueIndex:idx_sync_log_daily,priority:4" json:"event_type"` // 日志类型
+	Status    string    `gorm:"type:varchar(20);uniqueIndex:idx_sync_log_daily,priority:5" json:"status"`     // 状态
+	Count     int64     `json:"count"`                                                                        // 日志条数
+	MinBlock  uint64    `json:"min_block"`
+	MaxBlock  uint64    `json:"max_block"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (SyncLogDailyStat) TableName() string {
+	return "sync_log_daily_stats"
+}
+
+// SyncCheckpoint 同步检查点（每条链每个合约一行，记录已处理到的区块）
+type SyncCheckpoint struct {
+	ID              uint      `gorm:"primaryKey" json:"id"`
+	ChainID         uint64    `gorm:"uniqueIndex:idx_checkpoint_chain_contract,priority:1" json:"chain_id"`                          // 链ID
+	Network         string    `gorm:"type:varchar(50);index" json:"network"`                                                         // 网络名称
+	ContractAddress string    `gorm:"type:varchar(42);uniqueIndex:idx_checkpoint_chain_contract,priority:2" json:"contract_address"` // 合约地址
+	LastBlock       uint64    `json:"last_block"`                                                                                    // 最后处理的区块
+	BlockHash       string    `gorm:"type:varchar(66)" json:"block_hash"`                                                            // 最后处理区块的哈希
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+func (SyncCheckpoint) TableName() string {
+	return "sync_checkpoints"
+}
+
+// ProcessedLog 已处理的链上日志（与日志对应的数据写入在同一事务中记录，用于去重）
+type ProcessedLog struct {
+	ID              uint      `gorm:"primaryKey" json:"id"`
+	ChainID         uint64    `gorm:"uniqueIndex:idx_processed_log,priority:1" json:"chain_id"`                 // 链ID
+	Network         string    `gorm:"type:varchar(50)" json:"network"`                                          // 网络名称
+	ContractAddress string    `gorm:"type:varchar(42)" json:"contract_address"`                                 // 合约地址
+	TxHash          string    `gorm:"type:varchar(66);uniqueIndex:idx_processed_log,priority:2" json:"tx_hash"` // 交易哈希
+	LogIndex        uint      `gorm:"uniqueIndex:idx_processed_log,priority:3" json:"log_index"`                // 日志在区块中的索引
+	BlockNumber     uint64    `gorm:"index" json:"block_number"`
+	EventType       string    `gorm:"type:varchar(50)" json:"event_type"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+func (ProcessedLog) TableName() string {
+	return "processed_logs"
+}
+
 // AutoMigrate 自动迁移数据库
 func AutoMigrate(db *gorm.DB) error {
 	return db.AutoMigrate(
@@ -127,6 +205,20 @@ func AutoMigrate(db *gorm.DB) error {
 		&Participant{},
 		&Sponsor{},
 		&NFTTicket{},
+		&TicketTransfer{},
 		&SyncLog{},
+		&SyncCheckpoint{},
+		&ProcessedLog{},
+		&SyncLogDailyStat{},
+		&WebhookSubscription{},
+		&WebhookDelivery{},
+		&WebhookDeliveryAttempt{},
+		&AuthNonce{},
+		&TicketPassUse{},
+		&RelayedCheckIn{},
+		&ChainTransaction{},
+		&CheckInDevice{},
+		&OfflineCheckIn{},
+		&StatRollup{},
 	)
 }
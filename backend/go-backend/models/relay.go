@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// 中继签到状态
+const (
+	RelayStatusQueued    = "queued"    // 等待提交
+	RelayStatusSubmitted = "submitted" // 已广播，等待回执
+	RelayStatusConfirmed = "confirmed" // 已打包且执行成功
+	RelayStatusFailed    = "failed"    // 模拟失败、发送失败或执行回滚
+)
+
+// RelayedCheckIn 由中继器代组织者提交的签到交易
+type RelayedCheckIn struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	ChainID     uint64     `gorm:"index:idx_relay_checkin_participant,priority:1" json:"chain_id"`
+	EventID     string     `gorm:"type:varchar(100);index:idx_relay_checkin_participant,priority:2" json:"event_id"`
+	Participant Address    `gorm:"type:varchar(42);index:idx_relay_checkin_participant,priority:3" json:"participant"`
+	TokenID     string     `gorm:"type:varchar(100)" json:"token_id"`                // 为空或 0 时不标记门票
+	RequestedBy string     `gorm:"type:varchar(42)" json:"requested_by"`             // 发起请求的组织者/管理员钱包
+	BatchID     string     `gorm:"type:varchar(32);index" json:"batch_id,omitempty"` // 批量签到标识
+	Relayer     string     `gorm:"type:varchar(42)" json:"relayer"`                  // 签名交易的委托地址
+	Status      string     `gorm:"type:varchar(20);index" json:"status"`             // queued, submitted, confirmed, failed
+	TxID        *uint      `gorm:"index" json:"tx_id"`                               // chain_transactions 记录
+	TxHash      string     `gorm:"type:varchar(66);index" json:"tx_hash"`            // 加价重发后更新为最终打包的哈希
+	Nonce       *uint64    `json:"nonce"`
+	BlockNumber uint64     `json:"block_number"`
+	GasUsed     uint64     `json:"gas_used"`
+	Error       string     `gorm:"type:text" json:"error"`
+	SubmittedAt *time.Time `json:"submitted_at"`
+	ConfirmedAt *time.Time `json:"confirmed_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+func (RelayedCheckIn) TableName() string {
+	return "relayed_checkins"
+}
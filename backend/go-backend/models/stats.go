@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// StatRollup 平台统计汇总，每个周期、每条链一行，由汇总任务按周期重新计算
+type StatRollup struct {
+	ID                  uint      `gorm:"primaryKey" json:"-"`
+	Interval            string    `gorm:"type:varchar(10);uniqueIndex:idx_stat_rollup,priority:1" json:"interval"` // day, week, month
+	PeriodStart         int64     `gorm:"uniqueIndex:idx_stat_rollup,priority:2" json:"period_start"`              // 周期起始时间（UTC，Unix 秒）
+	ChainID             uint64    `gorm:"uniqueIndex:idx_stat_rollup,priority:3" json:"chain_id"`
+	Network             string    `gorm:"type:varchar(50)" json:"network"`
+	EventsStarted       int64     `json:"events_started"`       // 该周期开始的活动数
+	ActiveParticipants  int64     `json:"active_participants"`  // 该周期报名或签到的不同钱包数
+	Registrations       int64     `json:"registrations"`        // 该周期的报名数
+	CheckIns            int64     `json:"check_ins"`            // 该周期的签到数（含临时签到）
+	CohortRegistrations int64     `json:"cohort_registrations"` // 该周期开始的活动的报名人数
+	CohortCheckIns      int64     `json:"cohort_check_ins"`     // 该周期开始的活动的签到人数
+	SponsorCount        int64     `json:"sponsor_count"`
+	SponsorAmount       string    `gorm:"type:varchar(78)" json:"sponsor_amount"` // 赞助总额（原生代币最小单位）
+	UpdatedAt           time.Time `json:"updated_at"`
+}
+
+func (StatRollup) TableName() string {
+	return "stat_rollups"
+}
@@ -0,0 +1,69 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// 链上交易状态
+const (
+	TxStatusPending   = "pending"   // 已广播，等待打包（可能被加价重发）
+	TxStatusConfirmed = "confirmed" // 已打包且执行成功
+	TxStatusReverted  = "reverted"  // 已打包但执行回滚
+	TxStatusFailed    = "failed"    // 广播失败，nonce 已释放
+	TxStatusReplaced  = "replaced"  // nonce 已被其他交易占用
+)
+
+// ChainTransaction 后端发出的链上交易。加价重发时沿用同一 nonce，只更新当前哈希与手续费
+type ChainTransaction struct {
+	ID                uint       `gorm:"primaryKey" json:"id"`
+	ChainID           uint64     `gorm:"uniqueIndex:idx_chain_tx_nonce,priority:1;index:idx_chain_tx_status,priority:1" json:"chain_id"`
+	Network           string     `gorm:"type:varchar(50)" json:"network"`
+	FromAddress       string     `gorm:"type:varchar(42);uniqueIndex:idx_chain_tx_nonce,priority:2" json:"from"`
+	Nonce             *uint64    `gorm:"uniqueIndex:idx_chain_tx_nonce,priority:3" json:"nonce"` // 广播失败释放后为 NULL
+	ToAddress         string     `gorm:"type:varchar(42)" json:"to"`
+	Value             string     `gorm:"type:varchar(100)" json:"value"`
+	Data              string     `gorm:"type:mediumtext" json:"-"` // 十六进制调用数据
+	Gas               uint64     `json:"gas"`
+	TxType            uint8      `json:"tx_type"`                            // 0 legacy, 2 EIP-1559
+	GasPrice          string     `gorm:"type:varchar(100)" json:"gas_price"` // legacy 交易
+	GasTipCap         string     `gorm:"type:varchar(100)" json:"gas_tip_cap"`
+	GasFeeCap         string     `gorm:"type:varchar(100)" json:"gas_fee_cap"`
+	TxHash            string     `gorm:"type:varchar(66);index" json:"tx_hash"` // 当前（最近一次广播的）哈希
+	Hashes            string     `gorm:"type:text" json:"-"`                    // 所有广播过的哈希，逗号分隔
+	RawTx             string     `gorm:"type:mediumtext" json:"-"`              // 当前签名交易，重启后重新广播
+	Status            string     `gorm:"type:varchar(20);index:idx_chain_tx_status,priority:2" json:"status"`
+	Purpose           string     `gorm:"type:varchar(50)" json:"purpose"`          // 用途，如 relay_checkin
+	Reference         string     `gorm:"type:varchar(100);index" json:"reference"` // 业务记录标识，如 relayed_checkin:12
+	Attempts          int        `json:"attempts"`                                 // 广播次数（含加价重发）
+	LastSentAt        *time.Time `json:"last_sent_at"`
+	BlockNumber       uint64     `json:"block_number"`
+	GasUsed           uint64     `json:"gas_used"`
+	EffectiveGasPrice string     `gorm:"type:varchar(100)" json:"effective_gas_price"`
+	Error             string     `gorm:"type:text" json:"error"`
+	ConfirmedAt       *time.Time `json:"confirmed_at"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
+
+func (ChainTransaction) TableName() string {
+	return "chain_transactions"
+}
+
+// HashList 所有广播过的哈希
+func (t *ChainTransaction) HashList() []string {
+	if t.Hashes == "" {
+		return []string{}
+	}
+	return strings.Split(t.Hashes, ",")
+}
+
+// AddHash 记录新广播的哈希并设为当前哈希
+func (t *ChainTransaction) AddHash(hash string) {
+	t.TxHash = hash
+	if t.Hashes == "" {
+		t.Hashes = hash
+	} else {
+		t.Hashes += "," + hash
+	}
+}
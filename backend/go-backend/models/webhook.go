@@ -0,0 +1,89 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// 投递状态
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliverySucceeded = "succeeded"
+	WebhookDeliveryFailed    = "failed"
+)
+
+// WebhookSubscription 组织者的 Webhook 订阅
+type WebhookSubscription struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	Organizer  Address   `gorm:"type:varchar(42);index" json:"organizer"` // 组织者钱包地址
+	EventID    string    `gorm:"type:varchar(100);index" json:"event_id"` // 为空时订阅该组织者的所有活动
+	URL        string    `gorm:"type:varchar(1024)" json:"url"`           // 回调地址
+	Secret     string    `gorm:"type:varchar(128)" json:"-"`              // HMAC 签名密钥
+	EventTypes string    `gorm:"type:varchar(500)" json:"-"`              // 逗号分隔的事件类型，为空时接收所有类型
+	Active     bool      `gorm:"default:true" json:"active"`              // 是否启用
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+func (WebhookSubscription) TableName() string {
+	return "webhook_subscriptions"
+}
+
+// EventTypeList 订阅的事件类型列表
+func (s *WebhookSubscription) EventTypeList() []string {
+	if s.EventTypes == "" {
+		return []string{}
+	}
+	return strings.Split(s.EventTypes, ",")
+}
+
+// Accepts 是否接收指定类型的事件
+func (s *WebhookSubscription) Accepts(eventType string) bool {
+	if s.EventTypes == "" {
+		return true
+	}
+	for _, t := range s.EventTypeList() {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDelivery 一次 Webhook 投递（包含重试状态）
+type WebhookDelivery struct {
+	ID             uint       `gorm:"primaryKey" json:"id"`
+	SubscriptionID uint       `gorm:"index" json:"subscription_id"`
+	EventType      string     `gorm:"type:varchar(50)" json:"event_type"`
+	EventID        string     `gorm:"type:varchar(100)" json:"event_id"`
+	Payload        string     `gorm:"type:mediumtext" json:"payload"`                                           // 请求体 JSON
+	Status         string     `gorm:"type:varchar(20);index:idx_webhook_delivery_due,priority:1" json:"status"` // pending, succeeded, failed
+	Attempts       int        `json:"attempts"`                                                                 // 已尝试次数
+	NextAttemptAt  time.Time  `gorm:"index:idx_webhook_delivery_due,priority:2" json:"next_attempt_at"`         // 下次尝试时间
+	LastAttemptAt  *time.Time `json:"last_attempt_at"`
+	ResponseStatus int        `json:"response_status"`             // 最后一次响应状态码
+	LastError      string     `gorm:"type:text" json:"last_error"` // 最后一次错误
+	RedeliveryOf   *uint      `gorm:"index" json:"redelivery_of"`  // 手动重新投递时指向原投递
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+func (WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
+
+// WebhookDeliveryAttempt 每次 HTTP 请求的投递日志
+type WebhookDeliveryAttempt struct {
+	ID             uint      `gorm:"primaryKey" json:"id"`
+	DeliveryID     uint      `gorm:"index" json:"delivery_id"`
+	Attempt        int       `json:"attempt"`
+	ResponseStatus int       `json:"response_status"`
+	ResponseBody   string    `gorm:"type:text" json:"response_body"` // 截断后的响应内容
+	Error          string    `gorm:"type:text" json:"error"`
+	DurationMs     int64     `json:"duration_ms"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+func (WebhookDeliveryAttempt) TableName() string {
+	return "webhook_delivery_attempts"
+}
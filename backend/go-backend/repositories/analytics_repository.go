@@ -0,0 +1,106 @@
+package repositories
+
+import (
+	"fmt"
+
+	"hackathon-backend/models"
+
+	"gorm.io/gorm"
+)
+
+// BucketCount 按时间桶聚合的计数，Bucket 为桶起始时间（Unix 秒）
+type BucketCount struct {
+	Bucket int64 `json:"bucket"`
+	Count  int64 `json:"count"`
+}
+
+// ParticipantSummary 活动参与者汇总
+type ParticipantSummary struct {
+	Registered  int64
+	CheckedIn   int64
+	Provisional int64
+}
+
+// TicketSummary 活动门票汇总
+type TicketSummary struct {
+	Issued int64
+	Used   int64
+}
+
+// SponsorAmount 单条赞助金额（最小单位的十进制字符串）
+type SponsorAmount struct {
+	Network string
+	Amount  string
+}
+
+type AnalyticsRepository struct {
+	db *gorm.DB
+}
+
+func NewAnalyticsRepository(db *gorm.DB) *AnalyticsRepository {
+	return &AnalyticsRepository{db: db}
+}
+
+// countByBucket 按 column（Unix 秒）分桶计数，忽略为 0 的时间
+func countByBucket(query *gorm.DB, column string, bucketSeconds int64) ([]BucketCount, error) {
+	expr := fmt.Sprintf("FLOOR(%s / %d) * %d", column, bucketSeconds, bucketSeconds)
+	var rows []BucketCount
+	err := query.Select(expr + " AS bucket, COUNT(*) AS count").
+		Where(column + " > 0").
+		Group("bucket").
+		Order("bucket ASC").
+		Scan(&rows).Error
+	return rows, err
+}
+
+func (r *AnalyticsRepository) participants(event *models.Event) *gorm.DB {
+	return r.db.Model(&models.Participant{}).
+		Where("chain_id = ? AND contract_address = ? AND event_id = ?", event.ChainID, event.ContractAddress, event.EventID)
+}
+
+// GetParticipantSummary 统计活动的报名、签到和临时签到人数
+func (r *AnalyticsRepository) GetParticipantSummary(event *models.Event) (*ParticipantSummary, error) {
+	var summary ParticipantSummary
+	err := r.participants(event).
+		Select("COUNT(*) AS registered, "+
+			"COALESCE(SUM(CASE WHEN checked_in THEN 1 ELSE 0 END), 0) AS checked_in, "+
+			"COALESCE(SUM(CASE WHEN check_in_status = ? THEN 1 ELSE 0 END), 0) AS provisional", models.CheckInStatusProvisional).
+		Scan(&summary).Error
+	return &summary, err
+}
+
+// GetRegistrationBuckets 按报名时间分桶统计报名人数
+func (r *AnalyticsRepository) GetRegistrationBuckets(event *models.Event, bucketSeconds int64) ([]BucketCount, error) {
+	return countByBucket(r.participants(event), "registered_at", bucketSeconds)
+}
+
+// GetCheckInBuckets 按签到时间分桶统计签到人数（含临时签到）
+func (r *AnalyticsRepository) GetCheckInBuckets(event *models.Event, bucketSeconds int64) ([]BucketCount, error) {
+	return countByBucket(r.participants(event).Where("checked_in = ?", true), "check_in_time", bucketSeconds)
+}
+
+// GetSponsorAmounts 获取活动的所有赞助金额，由调用方按网络用大整数求和
+func (r *AnalyticsRepository) GetSponsorAmounts(event *models.Event) ([]SponsorAmount, error) {
+	var amounts []SponsorAmount
+	err := r.db.Model(&models.Sponsor{}).
+		Select("network, amount").
+		Where("chain_id = ? AND contract_address = ? AND event_id = ?", event.ChainID, event.ContractAddress, event.EventID).
+		Scan(&amounts).Error
+	return amounts, err
+}
+
+// GetTicketSummary 统计活动已发放和已使用的门票（门票不区分合约，见 FindTicketOnChain）
+func (r *AnalyticsRepository) GetTicketSummary(event *models.Event) (*TicketSummary, error) {
+	var summary TicketSummary
+	err := r.db.Model(&models.NFTTicket{}).
+		Select("COUNT(*) AS issued, COALESCE(SUM(CASE WHEN used THEN 1 ELSE 0 END), 0) AS used").
+		Where("chain_id = ? AND event_id = ?", event.ChainID, event.EventID).
+		Scan(&summary).Error
+	return &summary, err
+}
+
+// GetTransferBuckets 按区块时间分桶统计门票转让次数
+func (r *AnalyticsRepository) GetTransferBuckets(event *models.Event, bucketSeconds int64) ([]BucketCount, error) {
+	query := r.db.Model(&models.TicketTransfer{}).Where("chain_id = ? AND event_id = ?", event.ChainID, event.EventID)
+	return countByBucket(query, "transferred_at", bucketSeconds)
+}
@@ -0,0 +1,40 @@
+package repositories
+
+import (
+	"time"
+
+	"hackathon-backend/models"
+
+	"gorm.io/gorm"
+)
+
+type AuthRepository struct {
+	db *gorm.DB
+}
+
+func NewAuthRepository(db *gorm.DB) *AuthRepository {
+	return &AuthRepository{db: db}
+}
+
+// CreateNonce 保存新签发的 nonce
+func (r *AuthRepository) CreateNonce(nonce *models.AuthNonce) error {
+	return r.db.Create(nonce).Error
+}
+
+// ConsumeNonce 将未使用且未过期的 nonce 标记为已使用，返回是否成功；
+// 条件更新保证并发登录时同一 nonce 只能被使用一次
+func (r *AuthRepository) ConsumeNonce(nonce string, now time.Time) (bool, error) {
+	result := r.db.Model(&models.AuthNonce{}).
+		Where("nonce = ? AND used_at IS NULL AND expires_at > ?", nonce, now).
+		Update("used_at", now)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// DeleteExpiredNonces 删除已过期的 nonce
+func (r *AuthRepository) DeleteExpiredNonces(before time.Time) (int64, error) {
+	result := r.db.Where("expires_at <= ?", before).Delete(&models.AuthNonce{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,154 @@
+package repositories
+
+import (
+	"time"
+
+	"hackathon-backend/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CheckInRepository struct {
+	db *gorm.DB
+}
+
+func NewCheckInRepository(db *gorm.DB) *CheckInRepository {
+	return &CheckInRepository{db: db}
+}
+
+// FindPassUse 查找门票的核验记录，不存在时返回 nil
+func (r *CheckInRepository) FindPassUse(chainID uint64, tokenID string) (*models.TicketPassUse, error) {
+	var use models.TicketPassUse
+	err := r.db.Where("chain_id = ? AND token_id = ?", chainID, tokenID).First(&use).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &use, nil
+}
+
+// CreatePassUse 记录二维码核验，nonce 已使用或门票已核验过时返回 false
+func (r *CheckInRepository) CreatePassUse(use *models.TicketPassUse) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(use)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// SaveDevice 授权扫码设备，已存在时更新备注并取消撤销
+func (r *CheckInRepository) SaveDevice(device *models.CheckInDevice) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "chain_id"}, {Name: "event_id"}, {Name: "address"}},
+		DoUpdates: clause.AssignmentColumns([]string{"label", "authorized_by", "revoked_at", "updated_at"}),
+	}).Create(device).Error
+}
+
+// FindDevice 查找活动的扫码设备（含已撤销），不存在时返回 nil
+func (r *CheckInRepository) FindDevice(chainID uint64, eventID string, address models.Address) (*models.CheckInDevice, error) {
+	var device models.CheckInDevice
+	err := r.db.Where("chain_id = ? AND event_id = ? AND address = ?", chainID, eventID, address).First(&device).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &device, nil
+}
+
+// GetDevices 获取活动的所有扫码设备
+func (r *CheckInRepository) GetDevices(chainID uint64, eventID string) ([]models.CheckInDevice, error) {
+	var devices []models.CheckInDevice
+	err := r.db.Where("chain_id = ? AND event_id = ?", chainID, eventID).Order("id ASC").Find(&devices).Error
+	return devices, err
+}
+
+// RevokeDevice 撤销扫码设备，设备不存在或已撤销时返回 false
+func (r *CheckInRepository) RevokeDevice(chainID uint64, eventID string, address models.Address, revokedAt time.Time) (bool, error) {
+	result := r.db.Model(&models.CheckInDevice{}).
+		Where("chain_id = ? AND event_id = ? AND address = ? AND revoked_at IS NULL", chainID, eventID, address).
+		Update("revoked_at", revokedAt)
+	return result.RowsAffected > 0, result.Error
+}
+
+// CreateOfflineCheckIn 记录离线签到，参与者已有离线签到或 nonce 已使用时返回 false
+func (r *CheckInRepository) CreateOfflineCheckIn(checkIn *models.OfflineCheckIn) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(checkIn)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// FindOfflineCheckIn 查找参与者的离线签到，不存在时返回 nil
+func (r *CheckInRepository) FindOfflineCheckIn(chainID uint64, eventID string, participant models.Address) (*models.OfflineCheckIn, error) {
+	var checkIn models.OfflineCheckIn
+	err := r.db.Where("chain_id = ? AND event_id = ? AND participant = ?", chainID, eventID, participant).First(&checkIn).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &checkIn, nil
+}
+
+// UpdateOfflineCheckIn 保存离线签到状态
+func (r *CheckInRepository) UpdateOfflineCheckIn(checkIn *models.OfflineCheckIn) error {
+	return r.db.Save(checkIn).Error
+}
+
+// GetOfflineCheckIns 获取活动的离线签到，status 为空时返回全部
+func (r *CheckInRepository) GetOfflineCheckIns(chainID uint64, eventID string, status string) ([]models.OfflineCheckIn, error) {
+	query := r.db.Where("chain_id = ? AND event_id = ?", chainID, eventID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	var checkIns []models.OfflineCheckIn
+	err := query.Order("id ASC").Find(&checkIns).Error
+	return checkIns, err
+}
+
+// GetOfflineCheckInsByStatus 按记录顺序获取指定状态的离线签到，eventIDs 非空时只取这些活动
+func (r *CheckInRepository) GetOfflineCheckInsByStatus(chainID uint64, status string, eventIDs []string, limit int) ([]models.OfflineCheckIn, error) {
+	query := r.db.Where("chain_id = ? AND status = ?", chainID, status)
+	if len(eventIDs) > 0 {
+		query = query.Where("event_id IN ?", eventIDs)
+	}
+	var checkIns []models.OfflineCheckIn
+	err := query.Order("id ASC").Limit(limit).Find(&checkIns).Error
+	return checkIns, err
+}
+
+// SettleOfflineCheckIn 链上已签到时结算参与者的离线签到（含此前标记为冲突的），没有待结算记录时返回 nil
+func (r *CheckInRepository) SettleOfflineCheckIn(chainID uint64, eventID string, participant models.Address, txHash string) (*models.OfflineCheckIn, error) {
+	var checkIn models.OfflineCheckIn
+	err := r.db.Where("chain_id = ? AND event_id = ? AND participant = ? AND status <> ?", chainID, eventID, participant, models.OfflineCheckInSettled).
+		First(&checkIn).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	checkIn.Status = models.OfflineCheckInSettled
+	checkIn.TxHash = txHash
+	checkIn.Error = ""
+	checkIn.SettledAt = &now
+	return &checkIn, r.db.Save(&checkIn).Error
+}
+
+// GetUnsettledOfflineCheckInsByToken 获取引用该门票且尚未结算的离线签到
+func (r *CheckInRepository) GetUnsettledOfflineCheckInsByToken(chainID uint64, eventID string, tokenID string) ([]models.OfflineCheckIn, error) {
+	var checkIns []models.OfflineCheckIn
+	err := r.db.Where("chain_id = ? AND event_id = ? AND token_id = ?", chainID, eventID, tokenID).
+		Where("status IN ?", []string{models.OfflineCheckInProvisional, models.OfflineCheckInSubmitted}).
+		Find(&checkIns).Error
+	return checkIns, err
+}
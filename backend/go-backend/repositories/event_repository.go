@@ -1,9 +1,12 @@
 package repositories
 
 import (
+	"strings"
+
 	"hackathon-backend/models"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type EventRepository struct {
@@ -14,6 +17,11 @@ func NewEventRepository(db *gorm.DB) *EventRepository {
 	return &EventRepository{db: db}
 }
 
+// WithTx 返回使用指定事务的 repository
+func (r *EventRepository) WithTx(tx *gorm.DB) *EventRepository {
+	return &EventRepository{db: tx}
+}
+
 // GetDB 获取数据库实例
 func (r *EventRepository) GetDB() *gorm.DB {
 	return r.db
@@ -36,6 +44,51 @@ func (r *EventRepository) GetEventByID(eventID string) (*models.Event, error) {
 	return &event, err
 }
 
+// FindEvent 根据链、合约和链上 ID 查找活动，不存在时返回 nil
+func (r *EventRepository) FindEvent(chainID uint64, contractAddress string, eventID string) (*models.Event, error) {
+	var event models.Event
+	err := r.db.Where("chain_id = ? AND contract_address = ? AND event_id = ?", chainID, contractAddress, eventID).First(&event).Error
+
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &event, nil
+}
+
+// FindEventOnChain 根据链和链上 ID 查找活动（不区分合约，取最新同步的记录），不存在时返回 nil
+func (r *EventRepository) FindEventOnChain(chainID uint64, eventID string) (*models.Event, error) {
+	var event models.Event
+	err := r.db.Where("chain_id = ? AND event_id = ?", chainID, eventID).Order("id DESC").First(&event).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// SetEventActive 更新活动的开放状态，活动不存在时返回 nil
+func (r *EventRepository) SetEventActive(chainID uint64, contractAddress string, eventID string, active bool) (*models.Event, error) {
+	event, err := r.FindEvent(chainID, contractAddress, eventID)
+	if err != nil || event == nil {
+		return nil, err
+	}
+
+	return event, r.db.Model(event).Update("active", active).Error
+}
+
+// IncrementParticipantCount 原子增加活动的参与者计数
+func (r *EventRepository) IncrementParticipantCount(chainID uint64, contractAddress string, eventID string, delta int) error {
+	return r.db.Model(&models.Event{}).
+		Where("chain_id = ? AND contract_address = ? AND event_id = ?", chainID, contractAddress, eventID).
+		UpdateColumn("participant_count", gorm.Expr("participant_count + ?", delta)).Error
+}
+
 // GetEventByDBID 根据数据库 ID 获取活动
 func (r *EventRepository) GetEventByDBID(id uint64) (*models.Event, error) {
 	var event models.Event
@@ -51,7 +104,7 @@ func (r *EventRepository) GetAllEvents() ([]models.Event, error) {
 }
 
 // GetEventsByOrganizer 根据组织者获取活动（按创建时间倒序，最新的在前）
-func (r *EventRepository) GetEventsByOrganizer(organizer string) ([]models.Event, error) {
+func (r *EventRepository) GetEventsByOrganizer(organizer models.Address) ([]models.Event, error) {
 	var events []models.Event
 	err := r.db.Where("organizer = ?", organizer).Order("created_at DESC").Find(&events).Error
 	return events, err
@@ -79,6 +132,102 @@ func (r *EventRepository) GetParticipantsByEvent(eventID string) ([]models.Parti
 	return participants, err
 }
 
+// FindParticipant 根据链、活动和钱包查找参与者（不区分合约，取最新同步的记录），不存在时返回 nil
+func (r *EventRepository) FindParticipant(chainID uint64, eventID string, wallet models.Address) (*models.Participant, error) {
+	var participant models.Participant
+	err := r.db.Where("chain_id = ? AND event_id = ? AND wallet = ?", chainID, eventID, wallet).Order("id DESC").First(&participant).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &participant, nil
+}
+
+// SetParticipantCheckIn 更新参与者签到状态，参与者不存在时返回 false
+func (r *EventRepository) SetParticipantCheckIn(chainID uint64, contractAddress string, eventID string, wallet models.Address, checkedIn bool, checkInTime int64) (bool, error) {
+	var participant models.Participant
+	err := r.db.Where("chain_id = ? AND contract_address = ? AND event_id = ? AND wallet = ?", chainID, contractAddress, eventID, wallet).
+		First(&participant).Error
+	if err == gorm.ErrRecordNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	status := ""
+	if checkedIn {
+		status = models.CheckInStatusConfirmed
+	}
+	err = r.db.Model(&participant).Updates(map[string]interface{}{
+		"checked_in":      checkedIn,
+		"check_in_time":   checkInTime,
+		"check_in_status": status,
+	}).Error
+	return true, err
+}
+
+// SetProvisionalCheckIn 将尚未签到的参与者标记为临时签到（离线签到等待上链），已签到时返回 false
+func (r *EventRepository) SetProvisionalCheckIn(participantID uint, checkInTime int64) (bool, error) {
+	result := r.db.Model(&models.Participant{}).Where("id = ? AND checked_in = ?", participantID, false).Updates(map[string]interface{}{
+		"checked_in":      true,
+		"check_in_time":   checkInTime,
+		"check_in_status": models.CheckInStatusProvisional,
+	})
+	return result.RowsAffected > 0, result.Error
+}
+
+// ClearProvisionalCheckIn 撤销参与者的临时签到，链上已确认的签到不受影响
+func (r *EventRepository) ClearProvisionalCheckIn(chainID uint64, eventID string, wallet models.Address) error {
+	return r.db.Model(&models.Participant{}).
+		Where("chain_id = ? AND event_id = ? AND wallet = ? AND check_in_status = ?", chainID, eventID, wallet, models.CheckInStatusProvisional).
+		Updates(map[string]interface{}{
+			"checked_in":      false,
+			"check_in_time":   0,
+			"check_in_status": "",
+		}).Error
+}
+
+// UpsertParticipant 按 (链, 合约, 活动, 钱包) 更新参与者，不存在时创建
+func (r *EventRepository) UpsertParticipant(participant *models.Participant) error {
+	if participant.CheckedIn {
+		participant.CheckInStatus = models.CheckInStatusConfirmed
+	}
+
+	var existing models.Participant
+	err := r.db.Where("chain_id = ? AND contract_address = ? AND event_id = ? AND wallet = ?",
+		participant.ChainID, participant.ContractAddress, participant.EventID, participant.Wallet).
+		First(&existing).Error
+	if err == gorm.ErrRecordNotFound {
+		return r.db.Create(participant).Error
+	}
+	if err != nil {
+		return err
+	}
+
+	participant.ID = existing.ID
+	participant.CreatedAt = existing.CreatedAt
+	if !participant.CheckedIn && existing.CheckInStatus == models.CheckInStatusProvisional {
+		// 链上尚未签到，保留离线签到的临时状态
+		participant.CheckedIn = true
+		participant.CheckInTime = existing.CheckInTime
+		participant.CheckInStatus = existing.CheckInStatus
+	}
+	return r.db.Save(participant).Error
+}
+
+// ReplaceSponsors 用链上数据替换活动的全部赞助记录
+func (r *EventRepository) ReplaceSponsors(chainID uint64, contractAddress string, eventID string, sponsors []models.Sponsor) error {
+	err := r.db.Where("chain_id = ? AND contract_address = ? AND event_id = ?", chainID, contractAddress, eventID).
+		Delete(&models.Sponsor{}).Error
+	if err != nil || len(sponsors) == 0 {
+		return err
+	}
+	return r.db.Create(&sponsors).Error
+}
+
 // CreateSponsor 创建赞助商
 func (r *EventRepository) CreateSponsor(sponsor *models.Sponsor) error {
 	return r.db.Create(sponsor).Error
@@ -96,6 +245,19 @@ func (r *EventRepository) CreateNFTTicket(ticket *models.NFTTicket) error {
 	return r.db.Create(ticket).Error
 }
 
+// FindTicketOnChain 根据链和 Token ID 查找门票（不区分合约，取最新同步的记录），不存在时返回 nil
+func (r *EventRepository) FindTicketOnChain(chainID uint64, tokenID string) (*models.NFTTicket, error) {
+	var ticket models.NFTTicket
+	err := r.db.Where("chain_id = ? AND token_id = ?", chainID, tokenID).Order("id DESC").First(&ticket).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &ticket, nil
+}
+
 // GetNFTTicketByTokenID 根据 Token ID 获取 NFT 门票
 func (r *EventRepository) GetNFTTicketByTokenID(tokenID string) (*models.NFTTicket, error) {
 	var ticket models.NFTTicket
@@ -103,8 +265,36 @@ func (r *EventRepository) GetNFTTicketByTokenID(tokenID string) (*models.NFTTick
 	return &ticket, err
 }
 
+// MarkTicketUsed 将门票标记为已使用，门票不存在时返回 nil
+func (r *EventRepository) MarkTicketUsed(chainID uint64, tokenID string) (*models.NFTTicket, error) {
+	ticket, err := r.FindTicketOnChain(chainID, tokenID)
+	if err != nil || ticket == nil {
+		return nil, err
+	}
+
+	if ticket.Used {
+		return ticket, nil
+	}
+	return ticket, r.db.Model(ticket).Update("used", true).Error
+}
+
+// TransferTicket 更新门票持有者，门票不存在时返回 nil
+func (r *EventRepository) TransferTicket(chainID uint64, tokenID string, holder models.Address) (*models.NFTTicket, error) {
+	ticket, err := r.FindTicketOnChain(chainID, tokenID)
+	if err != nil || ticket == nil {
+		return nil, err
+	}
+
+	return ticket, r.db.Model(ticket).Update("holder", holder).Error
+}
+
+// CreateTicketTransfer 记录门票转让，同一日志重复写入时忽略
+func (r *EventRepository) CreateTicketTransfer(transfer *models.TicketTransfer) error {
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(transfer).Error
+}
+
 // GetNFTTicketsByHolder 获取持有者的所有 NFT 门票
-func (r *EventRepository) GetNFTTicketsByHolder(holder string) ([]models.NFTTicket, error) {
+func (r *EventRepository) GetNFTTicketsByHolder(holder models.Address) ([]models.NFTTicket, error) {
 	var tickets []models.NFTTicket
 	err := r.db.Where("holder = ?", holder).Find(&tickets).Error
 	return tickets, err
@@ -115,16 +305,298 @@ func (r *EventRepository) CreateSyncLog(log *models.SyncLog) error {
 	return r.db.Create(log).Error
 }
 
-// GetLastSyncBlock 获取最后同步的区块
-func (r *EventRepository) GetLastSyncBlock(eventType string) (uint64, error) {
-	var log models.SyncLog
-	err := r.db.Where("event_type = ? AND status = ?", eventType, "success").
-		Order("block_number DESC").
-		First(&log).Error
+// GetCheckpoint 获取指定链和合约的同步检查点，不存在时返回 nil
+func (r *EventRepository) GetCheckpoint(chainID uint64, contractAddress string) (*models.SyncCheckpoint, error) {
+	var checkpoint models.SyncCheckpoint
+	err := r.db.Where("chain_id = ? AND contract_address = ?", chainID, contractAddress).First(&checkpoint).Error
 
 	if err == gorm.ErrRecordNotFound {
-		return 0, nil
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &checkpoint, nil
+}
+
+// GetCheckpoints 获取所有同步检查点
+func (r *EventRepository) GetCheckpoints() ([]models.SyncCheckpoint, error) {
+	var checkpoints []models.SyncCheckpoint
+	err := r.db.Order("chain_id, contract_address").Find(&checkpoints).Error
+	return checkpoints, err
+}
+
+// AdvanceCheckpoint 推进同步检查点（只前进不后退）
+func (r *EventRepository) AdvanceCheckpoint(checkpoint *models.SyncCheckpoint) error {
+	// MySQL 按顺序执行赋值，block_hash 需在 last_block 之前更新
+	return r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "chain_id"}, {Name: "contract_address"}},
+		DoUpdates: clause.Set{
+			{Column: clause.Column{Name: "block_hash"}, Value: gorm.Expr("IF(VALUES(last_block) > last_block, VALUES(block_hash), block_hash)")},
+			{Column: clause.Column{Name: "network"}, Value: gorm.Expr("VALUES(network)")},
+			{Column: clause.Column{Name: "updated_at"}, Value: gorm.Expr("IF(VALUES(last_block) > last_block, VALUES(updated_at), updated_at)")},
+			{Column: clause.Column{Name: "last_block"}, Value: gorm.Expr("GREATEST(last_block, VALUES(last_block))")},
+		},
+	}).Create(checkpoint).Error
+}
+
+// IsLogProcessed 日志是否已处理
+func (r *EventRepository) IsLogProcessed(chainID uint64, txHash string, logIndex uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.ProcessedLog{}).
+		Where("chain_id = ? AND tx_hash = ? AND log_index = ?", chainID, txHash, logIndex).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// MarkLogProcessed 记录已处理的日志，日志已存在时返回 false
+func (r *EventRepository) MarkLogProcessed(processed *models.ProcessedLog) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(processed)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// EventFilter 活动列表过滤条件
+type EventFilter struct {
+	Network      string
+	Organizer    models.Address
+	Active       *bool
+	StartsAfter  *int64 // start_time >= StartsAfter
+	StartsBefore *int64 // start_time < StartsBefore
+}
+
+// ParticipantFilter 参与者列表过滤条件
+type ParticipantFilter struct {
+	EventID   string
+	Network   string
+	CheckedIn *bool
+}
+
+// SponsorFilter 赞助商列表过滤条件
+type SponsorFilter struct {
+	EventID string
+	Network string
+}
+
+// TicketFilter 门票列表过滤条件
+type TicketFilter struct {
+	EventID string
+	Holder  models.Address
+	Network string
+	Used    *bool
+}
+
+var eventListSpec = listSpec[models.Event]{
+	sorts: map[string]sortField[models.Event]{
+		"created_at":        {column: "created_at", isTime: true, value: func(e *models.Event) interface{} { return e.CreatedAt }},
+		"start_time":        {column: "start_time", value: func(e *models.Event) interface{} { return e.StartTime }},
+		"end_time":          {column: "end_time", value: func(e *models.Event) interface{} { return e.EndTime }},
+		"participant_count": {column: "participant_count", value: func(e *models.Event) interface{} { return e.ParticipantCount }},
+	},
+	defaultSort: "created_at",
+	id:          func(e *models.Event) uint { return e.ID },
+}
+
+var participantListSpec = listSpec[models.Participant]{
+	sorts: map[string]sortField[models.Participant]{
+		"registered_at": {column: "registered_at", value: func(p *models.Participant) interface{} { return p.RegisteredAt }},
+		"check_in_time": {column: "check_in_time", value: func(p *models.Participant) interface{} { return p.CheckInTime }},
+	},
+	defaultSort: "registered_at",
+	id:          func(p *models.Participant) uint { return p.ID },
+}
+
+var sponsorListSpec = listSpec[models.Sponsor]{
+	sorts: map[string]sortField[models.Sponsor]{
+		"sponsored_at": {column: "sponsored_at", value: func(s *models.Sponsor) interface{} { return s.SponsoredAt }},
+	},
+	defaultSort: "sponsored_at",
+	id:          func(s *models.Sponsor) uint { return s.ID },
+}
+
+var ticketListSpec = listSpec[models.NFTTicket]{
+	sorts: map[string]sortField[models.NFTTicket]{
+		"issued_at":  {column: "issued_at", value: func(t *models.NFTTicket) interface{} { return t.IssuedAt }},
+		"start_time": {column: "start_time", value: func(t *models.NFTTicket) interface{} { return t.StartTime }},
+	},
+	defaultSort: "issued_at",
+	id:          func(t *models.NFTTicket) uint { return t.ID },
+}
+
+// ListEvents 分页查询活动
+func (r *EventRepository) ListEvents(filter EventFilter, page PageQuery) (*Page[models.Event], error) {
+	query := applyEventFilter(r.db.Model(&models.Event{}), filter)
+	return paginate(query, eventListSpec, page)
+}
+
+// EventSearchHit 全文检索结果
+type EventSearchHit struct {
+	models.Event
+	Score float64 `gorm:"column:score" json:"score"`
+}
+
+// eventMatchExpr 与 idx_event_fulltext 索引列一致的全文匹配表达式
+const eventMatchExpr = "MATCH(title, description, location) AGAINST (? IN NATURAL LANGUAGE MODE)"
+
+// SearchEvents 全文检索活动（按相关度排序），返回当前页结果和总数
+func (r *EventRepository) SearchEvents(keyword string, location string, filter EventFilter, limit int, offset int) ([]EventSearchHit, int64, error) {
+	query := applyEventFilter(r.db.Model(&models.Event{}), filter)
+	if keyword != "" {
+		query = query.Where(eventMatchExpr, keyword)
+	}
+	if location != "" {
+		query = query.Where("location LIKE ?", "%"+escapeLike(location)+"%")
+	}
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	query = query.Session(&gorm.Session{})
+	if keyword != "" {
+		query = query.Select("events.*, "+eventMatchExpr+" AS score", keyword).Order("score DESC")
+	} else {
+		query = query.Select("events.*, 0 AS score")
 	}
 
-	return log.BlockNumber, err
+	var hits []EventSearchHit
+	err := query.Order("start_time DESC").Order("id DESC").
+		Limit(limit).Offset(offset).
+		Find(&hits).Error
+	return hits, total, err
+}
+
+// escapeLike 转义 LIKE 通配符
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// applyEventFilter 应用活动过滤条件
+func applyEventFilter(query *gorm.DB, filter EventFilter) *gorm.DB {
+	if filter.Network != "" {
+		query = query.Where("network = ?", filter.Network)
+	}
+	if filter.Organizer != "" {
+		query = query.Where("organizer = ?", filter.Organizer)
+	}
+	if filter.Active != nil {
+		query = query.Where("active = ?", *filter.Active)
+	}
+	if filter.StartsAfter != nil {
+		query = query.Where("start_time >= ?", *filter.StartsAfter)
+	}
+	if filter.StartsBefore != nil {
+		query = query.Where("start_time < ?", *filter.StartsBefore)
+	}
+	return query
+}
+
+// ListParticipants 分页查询参与者
+func (r *EventRepository) ListParticipants(filter ParticipantFilter, page PageQuery) (*Page[models.Participant], error) {
+	query := r.db.Model(&models.Participant{})
+	if filter.EventID != "" {
+		query = query.Where("event_id = ?", filter.EventID)
+	}
+	if filter.Network != "" {
+		query = query.Where("network = ?", filter.Network)
+	}
+	if filter.CheckedIn != nil {
+		query = query.Where("checked_in = ?", *filter.CheckedIn)
+	}
+	return paginate(query, participantListSpec, page)
+}
+
+// ListSponsors 分页查询赞助商
+func (r *EventRepository) ListSponsors(filter SponsorFilter, page PageQuery) (*Page[models.Sponsor], error) {
+	query := r.db.Model(&models.Sponsor{})
+	if filter.EventID != "" {
+		query = query.Where("event_id = ?", filter.EventID)
+	}
+	if filter.Network != "" {
+		query = query.Where("network = ?", filter.Network)
+	}
+	return paginate(query, sponsorListSpec, page)
+}
+
+// ListTickets 分页查询 NFT 门票
+func (r *EventRepository) ListTickets(filter TicketFilter, page PageQuery) (*Page[models.NFTTicket], error) {
+	query := r.db.Model(&models.NFTTicket{})
+	if filter.EventID != "" {
+		query = query.Where("event_id = ?", filter.EventID)
+	}
+	if filter.Holder != "" {
+		query = query.Where("holder = ?", filter.Holder)
+	}
+	if filter.Network != "" {
+		query = query.Where("network = ?", filter.Network)
+	}
+	if filter.Used != nil {
+		query = query.Where("used = ?", *filter.Used)
+	}
+	return paginate(query, ticketListSpec, page)
+}
+
+// EventKey 活动在链上的标识（链ID + 合约内活动ID）
+type EventKey struct {
+	ChainID uint64
+	EventID string
+}
+
+// eventKeyValues 将 EventKey 转换为 (chain_id, event_id) IN ? 查询参数
+func eventKeyValues(keys []EventKey) [][]interface{} {
+	values := make([][]interface{}, 0, len(keys))
+	for _, key := range keys {
+		values = append(values, []interface{}{key.ChainID, key.EventID})
+	}
+	return values
+}
+
+// GetEventsByKeys 批量获取活动
+func (r *EventRepository) GetEventsByKeys(keys []EventKey) ([]models.Event, error) {
+	var events []models.Event
+	if len(keys) == 0 {
+		return events, nil
+	}
+	err := r.db.Where("(chain_id, event_id) IN ?", eventKeyValues(keys)).Order("id ASC").Find(&events).Error
+	return events, err
+}
+
+// GetParticipantsByEventKeys 批量获取多个活动的参与者
+func (r *EventRepository) GetParticipantsByEventKeys(keys []EventKey) ([]models.Participant, error) {
+	var participants []models.Participant
+	if len(keys) == 0 {
+		return participants, nil
+	}
+	err := r.db.Where("(chain_id, event_id) IN ?", eventKeyValues(keys)).
+		Order("registered_at ASC, id ASC").
+		Find(&participants).Error
+	return participants, err
+}
+
+// GetSponsorsByEventKeys 批量获取多个活动的赞助商
+func (r *EventRepository) GetSponsorsByEventKeys(keys []EventKey) ([]models.Sponsor, error) {
+	var sponsors []models.Sponsor
+	if len(keys) == 0 {
+		return sponsors, nil
+	}
+	err := r.db.Where("(chain_id, event_id) IN ?", eventKeyValues(keys)).
+		Order("sponsored_at ASC, id ASC").
+		Find(&sponsors).Error
+	return sponsors, err
+}
+
+// GetTicketsByEventKeys 批量获取多个活动的门票
+func (r *EventRepository) GetTicketsByEventKeys(keys []EventKey) ([]models.NFTTicket, error) {
+	var tickets []models.NFTTicket
+	if len(keys) == 0 {
+		return tickets, nil
+	}
+	err := r.db.Where("(chain_id, event_id) IN ?", eventKeyValues(keys)).
+		Order("issued_at ASC, id ASC").
+		Find(&tickets).Error
+	return tickets, err
 }
@@ -0,0 +1,45 @@
+package repositories
+
+import (
+	"hackathon-backend/models"
+
+	"gorm.io/gorm"
+)
+
+// ExportRepository 按批读取活动数据用于导出
+type ExportRepository struct {
+	db *gorm.DB
+}
+
+func NewExportRepository(db *gorm.DB) *ExportRepository {
+	return &ExportRepository{db: db}
+}
+
+func (r *ExportRepository) eventScope(event *models.Event) *gorm.DB {
+	return r.db.Where("chain_id = ? AND contract_address = ? AND event_id = ?", event.ChainID, event.ContractAddress, event.EventID)
+}
+
+// EachParticipant 分批遍历活动的参与者（按 ID 升序），避免一次性加载到内存
+func (r *ExportRepository) EachParticipant(event *models.Event, batchSize int, fn func(participants []models.Participant) error) error {
+	var batch []models.Participant
+	return r.eventScope(event).FindInBatches(&batch, batchSize, func(tx *gorm.DB, _ int) error {
+		return fn(batch)
+	}).Error
+}
+
+// EachSponsor 分批遍历活动的赞助（按 ID 升序）
+func (r *ExportRepository) EachSponsor(event *models.Event, batchSize int, fn func(sponsors []models.Sponsor) error) error {
+	var batch []models.Sponsor
+	return r.eventScope(event).FindInBatches(&batch, batchSize, func(tx *gorm.DB, _ int) error {
+		return fn(batch)
+	}).Error
+}
+
+// EachTicket 分批遍历活动的门票（按 ID 升序）
+func (r *ExportRepository) EachTicket(event *models.Event, batchSize int, fn func(tickets []models.NFTTicket) error) error {
+	var batch []models.NFTTicket
+	return r.db.Where("chain_id = ? AND event_id = ?", event.ChainID, event.EventID).
+		FindInBatches(&batch, batchSize, func(tx *gorm.DB, _ int) error {
+			return fn(batch)
+		}).Error
+}
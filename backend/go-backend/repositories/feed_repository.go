@@ -0,0 +1,79 @@
+package repositories
+
+import (
+	"time"
+
+	"hackathon-backend/models"
+
+	"gorm.io/gorm"
+)
+
+// FeedFilter 日历和订阅源的过滤条件
+type FeedFilter struct {
+	ChainID   uint64 // 0 表示所有链
+	Organizer models.Address
+	Location  string // 地点包含该关键字
+}
+
+// FeedRepository 为日历和 RSS/Atom 订阅源读取活动
+type FeedRepository struct {
+	db *gorm.DB
+}
+
+func NewFeedRepository(db *gorm.DB) *FeedRepository {
+	return &FeedRepository{db: db}
+}
+
+// notEndedBefore 结束时间不早于 since 的活动（未设置结束时间时按开始时间）
+func notEndedBefore(db *gorm.DB, since int64) *gorm.DB {
+	return db.Where("(events.end_time >= ? OR (events.end_time = 0 AND events.start_time >= ?))", since, since)
+}
+
+func (r *FeedRepository) filtered(filter FeedFilter) *gorm.DB {
+	query := r.db.Model(&models.Event{})
+	if filter.ChainID != 0 {
+		query = query.Where("chain_id = ?", filter.ChainID)
+	}
+	if filter.Organizer != "" {
+		query = query.Where("organizer = ?", filter.Organizer)
+	}
+	if filter.Location != "" {
+		query = query.Where("location LIKE ?", "%"+escapeLike(filter.Location)+"%")
+	}
+	return query
+}
+
+// GetEventsEndingAfter 获取 since 之后结束的活动，按开始时间升序
+func (r *FeedRepository) GetEventsEndingAfter(filter FeedFilter, since int64, limit int) ([]models.Event, error) {
+	var events []models.Event
+	err := notEndedBefore(r.filtered(filter), since).Order("start_time ASC").Limit(limit).Find(&events).Error
+	return events, err
+}
+
+// GetEventsCreatedSince 获取 since 之后索引的活动，最新的在前
+func (r *FeedRepository) GetEventsCreatedSince(filter FeedFilter, since time.Time, limit int) ([]models.Event, error) {
+	var events []models.Event
+	err := r.filtered(filter).Where("created_at >= ?", since).Order("created_at DESC, id DESC").Limit(limit).Find(&events).Error
+	return events, err
+}
+
+// GetEventsStartingBetween 获取开始时间在 [from, to) 内的活动，按开始时间升序
+func (r *FeedRepository) GetEventsStartingBetween(filter FeedFilter, from int64, to int64, limit int) ([]models.Event, error) {
+	var events []models.Event
+	err := r.filtered(filter).Where("start_time >= ? AND start_time < ?", from, to).Order("start_time ASC, id ASC").Limit(limit).Find(&events).Error
+	return events, err
+}
+
+// GetRegisteredEvents 获取钱包报名的、since 之后结束的活动，按开始时间升序
+func (r *FeedRepository) GetRegisteredEvents(wallet models.Address, since int64, limit int) ([]models.Event, error) {
+	var events []models.Event
+	err := notEndedBefore(r.db.Model(&models.Event{}), since).
+		Joins("JOIN participants ON participants.chain_id = events.chain_id AND participants.contract_address = events.contract_address "+
+			"AND participants.event_id = events.event_id").
+		Where("participants.wallet = ?", wallet).
+		Distinct("events.*").
+		Order("events.start_time ASC").
+		Limit(limit).
+		Find(&events).Error
+	return events, err
+}
@@ -0,0 +1,180 @@
+package repositories
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	DefaultPageLimit = 50
+	MaxPageLimit     = 200
+)
+
+// ErrInvalidCursor 游标无法解析或与当前排序不匹配
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// ErrInvalidSort 不支持的排序字段
+var ErrInvalidSort = errors.New("invalid sort field")
+
+// PageQuery 分页和排序参数
+type PageQuery struct {
+	Limit  int
+	Cursor string
+	Sort   string // 排序字段，为空时使用默认字段
+	Desc   bool   // 是否降序
+}
+
+// Page 分页结果
+type Page[T any] struct {
+	Items      []T
+	Total      int64
+	Limit      int
+	NextCursor string
+}
+
+// sortField 可排序字段
+type sortField[T any] struct {
+	column string
+	isTime bool
+	value  func(item *T) interface{}
+}
+
+// listSpec 某个列表的可排序字段、默认排序和主键
+type listSpec[T any] struct {
+	sorts       map[string]sortField[T]
+	defaultSort string
+	id          func(item *T) uint
+}
+
+// pageCursor 游标内容：最后一条记录的排序值和主键（keyset 分页）
+type pageCursor struct {
+	Sort  string          `json:"s"`
+	Desc  bool            `json:"d"`
+	Value json.RawMessage `json:"v"`
+	ID    uint            `json:"id"`
+}
+
+// paginate 对查询执行计数、游标过滤、排序和分页
+func paginate[T any](query *gorm.DB, spec listSpec[T], page PageQuery) (*Page[T], error) {
+	sortName := page.Sort
+	if sortName == "" {
+		sortName = spec.defaultSort
+	}
+	field, ok := spec.sorts[sortName]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidSort, sortName)
+	}
+
+	limit := page.Limit
+	if limit <= 0 {
+		limit = DefaultPageLimit
+	}
+	if limit > MaxPageLimit {
+		limit = MaxPageLimit
+	}
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	direction, op := "ASC", ">"
+	if page.Desc {
+		direction, op = "DESC", "<"
+	}
+
+	query = query.Session(&gorm.Session{})
+	if page.Cursor != "" {
+		value, id, err := decodeCursor(page.Cursor, sortName, page.Desc, field.isTime)
+		if err != nil {
+			return nil, err
+		}
+		query = query.Where(
+			fmt.Sprintf("((%s %s ?) OR (%s = ? AND id %s ?))", field.column, op, field.column, op),
+			value, value, id,
+		)
+	}
+
+	var items []T
+	err := query.Order(fmt.Sprintf("%s %s, id %s", field.column, direction, direction)).
+		Limit(limit + 1).
+		Find(&items).Error
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Page[T]{Total: total, Limit: limit}
+	if len(items) > limit {
+		items = items[:limit]
+		last := &items[len(items)-1]
+		result.NextCursor, err = encodeCursor(sortName, page.Desc, field.value(last), spec.id(last))
+		if err != nil {
+			return nil, err
+		}
+	}
+	if items == nil {
+		items = []T{}
+	}
+	result.Items = items
+
+	return result, nil
+}
+
+func encodeCursor(sort string, desc bool, value interface{}, id uint) (string, error) {
+	if t, ok := value.(time.Time); ok {
+		value = t.Format(time.RFC3339Nano)
+	}
+
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := json.Marshal(pageCursor{Sort: sort, Desc: desc, Value: raw, ID: id})
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodeCursor(cursor string, sort string, desc bool, isTime bool) (interface{}, uint, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, 0, ErrInvalidCursor
+	}
+
+	var c pageCursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, 0, ErrInvalidCursor
+	}
+	if c.Sort != sort || c.Desc != desc {
+		return nil, 0, fmt.Errorf("%w: cursor was issued for a different sort order", ErrInvalidCursor)
+	}
+
+	if isTime {
+		var s string
+		if err := json.Unmarshal(c.Value, &s); err != nil {
+			return nil, 0, ErrInvalidCursor
+		}
+		t, err := time.Parse(time.RFC3339Nano, s)
+		if err != nil {
+			return nil, 0, ErrInvalidCursor
+		}
+		return t, c.ID, nil
+	}
+
+	var n json.Number
+	if err := json.Unmarshal(c.Value, &n); err != nil {
+		return nil, 0, ErrInvalidCursor
+	}
+	v, err := n.Int64()
+	if err != nil {
+		return nil, 0, ErrInvalidCursor
+	}
+	return v, c.ID, nil
+}
@@ -0,0 +1,78 @@
+package repositories
+
+import (
+	"hackathon-backend/models"
+
+	"gorm.io/gorm"
+)
+
+type RelayRepository struct {
+	db *gorm.DB
+}
+
+func NewRelayRepository(db *gorm.DB) *RelayRepository {
+	return &RelayRepository{db: db}
+}
+
+// CreateCheckIn 创建中继签到任务
+func (r *RelayRepository) CreateCheckIn(job *models.RelayedCheckIn) error {
+	return r.db.Create(job).Error
+}
+
+// CreateCheckIns 批量创建中继签到任务
+func (r *RelayRepository) CreateCheckIns(jobs []models.RelayedCheckIn) error {
+	if len(jobs) == 0 {
+		return nil
+	}
+	return r.db.Create(&jobs).Error
+}
+
+// GetCheckInsByBatch 获取批量签到中的任务
+func (r *RelayRepository) GetCheckInsByBatch(batchID string) ([]models.RelayedCheckIn, error) {
+	var jobs []models.RelayedCheckIn
+	err := r.db.Where("batch_id = ?", batchID).Order("id ASC").Find(&jobs).Error
+	return jobs, err
+}
+
+// UpdateCheckIn 保存中继签到任务状态
+func (r *RelayRepository) UpdateCheckIn(job *models.RelayedCheckIn) error {
+	return r.db.Save(job).Error
+}
+
+// GetCheckIn 获取中继签到任务，不存在时返回 nil
+func (r *RelayRepository) GetCheckIn(id uint) (*models.RelayedCheckIn, error) {
+	var job models.RelayedCheckIn
+	err := r.db.First(&job, id).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// FindPendingCheckIn 查找参与者尚未结束（排队中或等待回执）的签到任务，不存在时返回 nil
+func (r *RelayRepository) FindPendingCheckIn(chainID uint64, eventID string, participant models.Address) (*models.RelayedCheckIn, error) {
+	var job models.RelayedCheckIn
+	err := r.db.Where("chain_id = ? AND event_id = ? AND participant = ?", chainID, eventID, participant).
+		Where("status IN ?", []string{models.RelayStatusQueued, models.RelayStatusSubmitted}).
+		First(&job).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// GetCheckInsByStatus 按创建顺序获取指定状态的签到任务
+func (r *RelayRepository) GetCheckInsByStatus(chainID uint64, status string, limit int) ([]models.RelayedCheckIn, error) {
+	var jobs []models.RelayedCheckIn
+	err := r.db.Where("chain_id = ? AND status = ?", chainID, status).
+		Order("id ASC").
+		Limit(limit).
+		Find(&jobs).Error
+	return jobs, err
+}
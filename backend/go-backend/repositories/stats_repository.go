@@ -0,0 +1,137 @@
+package repositories
+
+import (
+	"hackathon-backend/models"
+
+	"gorm.io/gorm"
+)
+
+// RollupEvent 汇总用的活动字段
+type RollupEvent struct {
+	ChainID         uint64
+	Network         string
+	ContractAddress string
+	EventID         string
+	StartTime       int64
+}
+
+// RollupParticipant 汇总用的参与者字段
+type RollupParticipant struct {
+	ChainID      uint64
+	Network      string
+	Wallet       string
+	RegisteredAt int64
+	CheckedIn    bool
+	CheckInTime  int64
+}
+
+// RollupSponsor 汇总用的赞助字段
+type RollupSponsor struct {
+	ChainID     uint64
+	Network     string
+	Amount      string
+	SponsoredAt int64
+}
+
+// EventParticipantCount 单个活动的报名和签到人数
+type EventParticipantCount struct {
+	ChainID         uint64
+	ContractAddress string
+	EventID         string
+	Registered      int64
+	CheckedIn       int64
+}
+
+// StatsQuery 统计时间序列查询条件
+type StatsQuery struct {
+	Interval string
+	ChainID  uint64 // 0 表示所有链
+	From     int64  // 周期起始时间下限（含）
+	To       int64  // 周期起始时间上限（含）
+}
+
+type StatsRepository struct {
+	db *gorm.DB
+}
+
+func NewStatsRepository(db *gorm.DB) *StatsRepository {
+	return &StatsRepository{db: db}
+}
+
+// GetEventsStartedSince 获取开始时间不早于 since 的活动
+func (r *StatsRepository) GetEventsStartedSince(since int64) ([]RollupEvent, error) {
+	var events []RollupEvent
+	err := r.db.Model(&models.Event{}).
+		Select("chain_id, network, contract_address, event_id, start_time").
+		Where("start_time >= ?", since).
+		Scan(&events).Error
+	return events, err
+}
+
+// GetParticipantsActiveSince 获取 since 之后报名或签到的参与者
+func (r *StatsRepository) GetParticipantsActiveSince(since int64) ([]RollupParticipant, error) {
+	var participants []RollupParticipant
+	err := r.db.Model(&models.Participant{}).
+		Select("chain_id, network, wallet, registered_at, checked_in, check_in_time").
+		Where("registered_at >= ? OR (checked_in = ? AND check_in_time >= ?)", since, true, since).
+		Scan(&participants).Error
+	return participants, err
+}
+
+// GetSponsorsSince 获取 since 之后的赞助
+func (r *StatsRepository) GetSponsorsSince(since int64) ([]RollupSponsor, error) {
+	var sponsors []RollupSponsor
+	err := r.db.Model(&models.Sponsor{}).
+		Select("chain_id, network, amount, sponsored_at").
+		Where("sponsored_at >= ?", since).
+		Scan(&sponsors).Error
+	return sponsors, err
+}
+
+// GetParticipantCounts 按活动统计报名和签到人数，调用方再按 (链, 合约) 匹配
+func (r *StatsRepository) GetParticipantCounts(eventIDs []string) ([]EventParticipantCount, error) {
+	if len(eventIDs) == 0 {
+		return nil, nil
+	}
+	var counts []EventParticipantCount
+	err := r.db.Model(&models.Participant{}).
+		Select("chain_id, contract_address, event_id, COUNT(*) AS registered, "+
+			"COALESCE(SUM(CASE WHEN checked_in THEN 1 ELSE 0 END), 0) AS checked_in").
+		Where("event_id IN ?", eventIDs).
+		Group("chain_id, contract_address, event_id").
+		Scan(&counts).Error
+	return counts, err
+}
+
+// ReplaceRollups 在同一事务中删除各周期类型 periodStarts 之后的汇总并写入新的汇总
+func (r *StatsRepository) ReplaceRollups(periodStarts map[string]int64, rollups []models.StatRollup) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for interval, start := range periodStarts {
+			if err := tx.Where("`interval` = ? AND period_start >= ?", interval, start).Delete(&models.StatRollup{}).Error; err != nil {
+				return err
+			}
+		}
+		if len(rollups) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(rollups, 500).Error
+	})
+}
+
+// CountRollups 汇总表中的行数
+func (r *StatsRepository) CountRollups() (int64, error) {
+	var count int64
+	err := r.db.Model(&models.StatRollup{}).Count(&count).Error
+	return count, err
+}
+
+// GetRollups 按链和周期顺序获取汇总
+func (r *StatsRepository) GetRollups(query StatsQuery) ([]models.StatRollup, error) {
+	db := r.db.Where("`interval` = ? AND period_start >= ? AND period_start <= ?", query.Interval, query.From, query.To)
+	if query.ChainID != 0 {
+		db = db.Where("chain_id = ?", query.ChainID)
+	}
+	var rollups []models.StatRollup
+	err := db.Order("chain_id ASC, period_start ASC").Find(&rollups).Error
+	return rollups, err
+}
@@ -0,0 +1,94 @@
+package repositories
+
+import (
+	"time"
+
+	"hackathon-backend/models"
+
+	"gorm.io/gorm"
+)
+
+type SyncLogRepository struct {
+	db *gorm.DB
+}
+
+func NewSyncLogRepository(db *gorm.DB) *SyncLogRepository {
+	return &SyncLogRepository{db: db}
+}
+
+// SyncLogExpiry 同步日志过期条件：成功类日志与失败日志分别使用不同的截止时间
+type SyncLogExpiry struct {
+	SuccessBefore time.Time // status != failed 且早于该时间的日志过期
+	FailureBefore time.Time // status = failed 且早于该时间的日志过期
+	MaxID         uint      // 只处理 ID 不超过该值的日志，保证归档、汇总和删除作用于同一批数据
+}
+
+// expired 构造过期日志查询
+func (r *SyncLogRepository) expired(tx *gorm.DB, expiry SyncLogExpiry) *gorm.DB {
+	return tx.Model(&models.SyncLog{}).
+		Where("id <= ?", expiry.MaxID).
+		Where("(status <> ? AND created_at < ?) OR (status = ? AND created_at < ?)",
+			"failed", expiry.SuccessBefore, "failed", expiry.FailureBefore)
+}
+
+// GetMaxID 获取当前最大的同步日志 ID
+func (r *SyncLogRepository) GetMaxID() (uint, error) {
+	var maxID uint
+	err := r.db.Model(&models.SyncLog{}).Select("COALESCE(MAX(id), 0)").Scan(&maxID).Error
+	return maxID, err
+}
+
+// CountExpired 统计过期日志条数
+func (r *SyncLogRepository) CountExpired(expiry SyncLogExpiry) (int64, error) {
+	var count int64
+	err := r.expired(r.db, expiry).Count(&count).Error
+	return count, err
+}
+
+// EachExpired 分批遍历过期日志（按 ID 升序），避免一次性加载到内存
+func (r *SyncLogRepository) EachExpired(expiry SyncLogExpiry, batchSize int, fn func(logs []models.SyncLog) error) error {
+	var batch []models.SyncLog
+	return r.expired(r.db, expiry).FindInBatches(&batch, batchSize, func(tx *gorm.DB, _ int) error {
+		return fn(batch)
+	}).Error
+}
+
+// CompactExpired 在同一事务中将过期日志汇总到每日统计表并删除，返回删除条数
+func (r *SyncLogRepository) CompactExpired(expiry SyncLogExpiry) (int64, error) {
+	var deleted int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		aggregate := r.expired(tx, expiry).
+			Select("chain_id, network, DATE_FORMAT(created_at, '%Y-%m-%d') AS date, event_type, status, " +
+				"COUNT(*) AS count, MIN(block_number) AS min_block, MAX(block_number) AS max_block, NOW(3) AS updated_at").
+			Group("chain_id, network, DATE_FORMAT(created_at, '%Y-%m-%d'), event_type, status")
+
+		err := tx.Exec("INSERT INTO sync_log_daily_stats (chain_id, network, date, event_type, status, count, min_block, max_block, updated_at) "+
+			"SELECT * FROM (?) AS agg "+
+			"ON DUPLICATE KEY UPDATE count = sync_log_daily_stats.count + agg.count, "+
+			"min_block = LEAST(sync_log_daily_stats.min_block, agg.min_block), "+
+			"max_block = GREATEST(sync_log_daily_stats.max_block, agg.max_block), "+
+			"updated_at = agg.updated_at", aggregate).Error
+		if err != nil {
+			return err
+		}
+
+		result := r.expired(tx, expiry).Delete(&models.SyncLog{})
+		deleted = result.RowsAffected
+		return result.Error
+	})
+	return deleted, err
+}
+
+// GetDailyStats 获取每日汇总，network 为空时返回所有网络
+func (r *SyncLogRepository) GetDailyStats(network string, since string) ([]models.SyncLogDailyStat, error) {
+	var stats []models.SyncLogDailyStat
+	query := r.db.Model(&models.SyncLogDailyStat{})
+	if network != "" {
+		query = query.Where("network = ?", network)
+	}
+	if since != "" {
+		query = query.Where("date >= ?", since)
+	}
+	err := query.Order("date DESC, network, event_type, status").Find(&stats).Error
+	return stats, err
+}
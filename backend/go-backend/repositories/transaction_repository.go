@@ -0,0 +1,72 @@
+package repositories
+
+import (
+	"hackathon-backend/models"
+
+	"gorm.io/gorm"
+)
+
+// TransactionRepository 链上交易记录，实现 blockchain.TxStore
+type TransactionRepository struct {
+	db *gorm.DB
+}
+
+func NewTransactionRepository(db *gorm.DB) *TransactionRepository {
+	return &TransactionRepository{db: db}
+}
+
+// CreateTransaction 保存新交易
+func (r *TransactionRepository) CreateTransaction(tx *models.ChainTransaction) error {
+	return r.db.Create(tx).Error
+}
+
+// UpdateTransaction 保存交易状态
+func (r *TransactionRepository) UpdateTransaction(tx *models.ChainTransaction) error {
+	return r.db.Save(tx).Error
+}
+
+// GetTransaction 获取交易，不存在时返回 nil
+func (r *TransactionRepository) GetTransaction(id uint) (*models.ChainTransaction, error) {
+	var tx models.ChainTransaction
+	err := r.db.First(&tx, id).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &tx, nil
+}
+
+// FindTransactionByReference 查找业务记录最近一笔交易，不存在时返回 nil
+func (r *TransactionRepository) FindTransactionByReference(chainID uint64, reference string) (*models.ChainTransaction, error) {
+	var tx models.ChainTransaction
+	err := r.db.Where("chain_id = ? AND reference = ?", chainID, reference).Order("id DESC").First(&tx).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &tx, nil
+}
+
+// MaxNonce 签名地址已占用的最大 nonce，没有记录时返回 nil
+func (r *TransactionRepository) MaxNonce(chainID uint64, from string) (*uint64, error) {
+	var max *uint64
+	err := r.db.Model(&models.ChainTransaction{}).
+		Where("chain_id = ? AND from_address = ? AND nonce IS NOT NULL", chainID, from).
+		Select("MAX(nonce)").
+		Scan(&max).Error
+	return max, err
+}
+
+// GetPendingTransactions 按签名地址和 nonce 顺序获取待确认的交易
+func (r *TransactionRepository) GetPendingTransactions(chainID uint64, limit int) ([]models.ChainTransaction, error) {
+	var txs []models.ChainTransaction
+	err := r.db.Where("chain_id = ? AND status = ?", chainID, models.TxStatusPending).
+		Order("from_address ASC, nonce ASC").
+		Limit(limit).
+		Find(&txs).Error
+	return txs, err
+}
@@ -0,0 +1,95 @@
+package repositories
+
+import (
+	"hackathon-backend/models"
+
+	"gorm.io/gorm"
+)
+
+// WalletParticipation 钱包的报名记录及所属活动
+type WalletParticipation struct {
+	ChainID         uint64 `json:"chain_id"`
+	Network         string `json:"network"`
+	ContractAddress string `json:"contract_address"`
+	EventID         string `json:"event_id"`
+	Title           string `json:"title"`
+	Location        string `json:"location"`
+	StartTime       int64  `json:"start_time"`
+	EndTime         int64  `json:"end_time"`
+	Name            string `json:"name"`
+	RegisteredAt    int64  `json:"registered_at"`
+	CheckedIn       bool   `json:"checked_in"`
+	CheckInTime     int64  `json:"check_in_time"`
+	CheckInStatus   string `json:"check_in_status,omitempty"`
+}
+
+// WalletSponsorship 钱包的赞助记录及所属活动
+type WalletSponsorship struct {
+	ChainID         uint64 `json:"chain_id"`
+	Network         string `json:"network"`
+	ContractAddress string `json:"contract_address"`
+	EventID         string `json:"event_id"`
+	Title           string `json:"title"`
+	Name            string `json:"name"`
+	Amount          string `json:"amount"` // 最小单位
+	SponsoredAt     int64  `json:"sponsored_at"`
+}
+
+// WalletRepository 按钱包地址跨链查询
+type WalletRepository struct {
+	db *gorm.DB
+}
+
+func NewWalletRepository(db *gorm.DB) *WalletRepository {
+	return &WalletRepository{db: db}
+}
+
+// scope 按地址列过滤，chainID 为 0 时不限制链
+func (r *WalletRepository) scope(table string, column string, address models.Address, chainID uint64) *gorm.DB {
+	db := r.db.Table(table).Where(table+"."+column+" = ?", address)
+	if chainID != 0 {
+		db = db.Where(table+".chain_id = ?", chainID)
+	}
+	return db
+}
+
+// GetParticipations 获取钱包的报名记录（按报名时间倒序）
+func (r *WalletRepository) GetParticipations(address models.Address, chainID uint64) ([]WalletParticipation, error) {
+	var rows []WalletParticipation
+	err := r.scope("participants", "wallet", address, chainID).
+		Select("participants.chain_id, participants.network, participants.contract_address, participants.event_id, " +
+			"events.title, events.location, events.start_time, events.end_time, participants.name, participants.registered_at, " +
+			"participants.checked_in, participants.check_in_time, participants.check_in_status").
+		Joins("LEFT JOIN events ON events.chain_id = participants.chain_id AND events.contract_address = participants.contract_address " +
+			"AND events.event_id = participants.event_id").
+		Order("participants.registered_at DESC").
+		Scan(&rows).Error
+	return rows, err
+}
+
+// GetSponsorships 获取钱包的赞助记录（按赞助时间倒序）
+func (r *WalletRepository) GetSponsorships(address models.Address, chainID uint64) ([]WalletSponsorship, error) {
+	var rows []WalletSponsorship
+	err := r.scope("sponsors", "wallet", address, chainID).
+		Select("sponsors.chain_id, sponsors.network, sponsors.contract_address, sponsors.event_id, events.title, " +
+			"sponsors.name, sponsors.amount, sponsors.sponsored_at").
+		Joins("LEFT JOIN events ON events.chain_id = sponsors.chain_id AND events.contract_address = sponsors.contract_address " +
+			"AND events.event_id = sponsors.event_id").
+		Order("sponsors.sponsored_at DESC").
+		Scan(&rows).Error
+	return rows, err
+}
+
+// GetOrganizedEvents 获取钱包组织的活动（按开始时间倒序）
+func (r *WalletRepository) GetOrganizedEvents(address models.Address, chainID uint64) ([]models.Event, error) {
+	var events []models.Event
+	err := r.scope("events", "organizer", address, chainID).Order("start_time DESC").Find(&events).Error
+	return events, err
+}
+
+// GetHeldTickets 获取钱包当前持有的门票（按发放时间倒序）
+func (r *WalletRepository) GetHeldTickets(address models.Address, chainID uint64) ([]models.NFTTicket, error) {
+	var tickets []models.NFTTicket
+	err := r.scope("nft_tickets", "holder", address, chainID).Order("issued_at DESC").Find(&tickets).Error
+	return tickets, err
+}
@@ -0,0 +1,142 @@
+package repositories
+
+import (
+	"time"
+
+	"hackathon-backend/models"
+
+	"gorm.io/gorm"
+)
+
+type WebhookRepository struct {
+	db *gorm.DB
+}
+
+func NewWebhookRepository(db *gorm.DB) *WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+// WithTx 返回使用指定事务的 repository
+func (r *WebhookRepository) WithTx(tx *gorm.DB) *WebhookRepository {
+	return &WebhookRepository{db: tx}
+}
+
+// CreateSubscription 创建 Webhook 订阅
+func (r *WebhookRepository) CreateSubscription(sub *models.WebhookSubscription) error {
+	return r.db.Create(sub).Error
+}
+
+// UpdateSubscription 更新 Webhook 订阅
+func (r *WebhookRepository) UpdateSubscription(sub *models.WebhookSubscription) error {
+	return r.db.Save(sub).Error
+}
+
+// DeleteSubscription 删除 Webhook 订阅
+func (r *WebhookRepository) DeleteSubscription(id uint) error {
+	return r.db.Delete(&models.WebhookSubscription{}, id).Error
+}
+
+// GetSubscription 获取 Webhook 订阅，不存在时返回 nil
+func (r *WebhookRepository) GetSubscription(id uint) (*models.WebhookSubscription, error) {
+	var sub models.WebhookSubscription
+	err := r.db.First(&sub, id).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &sub, nil
+}
+
+// GetSubscriptionsByOrganizer 获取组织者的所有 Webhook 订阅
+func (r *WebhookRepository) GetSubscriptionsByOrganizer(organizer models.Address) ([]models.WebhookSubscription, error) {
+	var subs []models.WebhookSubscription
+	err := r.db.Where("organizer = ?", organizer).Order("id DESC").Find(&subs).Error
+	return subs, err
+}
+
+// FindMatchingSubscriptions 查找与活动匹配的启用订阅：指定了该活动，或未指定活动且属于活动组织者
+func (r *WebhookRepository) FindMatchingSubscriptions(eventID string, organizer models.Address) ([]models.WebhookSubscription, error) {
+	var subs []models.WebhookSubscription
+	err := r.db.Where("active = ?", true).
+		Where("(event_id = ? OR (event_id = '' AND organizer = ?))", eventID, organizer).
+		Find(&subs).Error
+	return subs, err
+}
+
+// CreateDeliveries 批量创建投递记录
+func (r *WebhookRepository) CreateDeliveries(deliveries []models.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	return r.db.Create(&deliveries).Error
+}
+
+// CreateDelivery 创建投递记录
+func (r *WebhookRepository) CreateDelivery(delivery *models.WebhookDelivery) error {
+	return r.db.Create(delivery).Error
+}
+
+// GetDelivery 获取订阅下的投递记录，不存在时返回 nil
+func (r *WebhookRepository) GetDelivery(subscriptionID uint, id uint) (*models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+	err := r.db.Where("subscription_id = ?", subscriptionID).First(&delivery, id).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+// GetDueDeliveries 获取到期待投递的记录
+func (r *WebhookRepository) GetDueDeliveries(now time.Time, limit int) ([]models.WebhookDelivery, error) {
+	var deliveries []models.WebhookDelivery
+	err := r.db.Where("status = ? AND next_attempt_at <= ?", models.WebhookDeliveryPending, now).
+		Order("next_attempt_at ASC, id ASC").
+		Limit(limit).
+		Find(&deliveries).Error
+	return deliveries, err
+}
+
+// RecordAttempt 在同一事务中保存投递状态和本次请求日志
+func (r *WebhookRepository) RecordAttempt(delivery *models.WebhookDelivery, attempt *models.WebhookDeliveryAttempt) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(delivery).Error; err != nil {
+			return err
+		}
+		return tx.Create(attempt).Error
+	})
+}
+
+// GetAttempts 获取投递的请求日志
+func (r *WebhookRepository) GetAttempts(deliveryID uint) ([]models.WebhookDeliveryAttempt, error) {
+	var attempts []models.WebhookDeliveryAttempt
+	err := r.db.Where("delivery_id = ?", deliveryID).Order("attempt ASC").Find(&attempts).Error
+	return attempts, err
+}
+
+// DeliveryFilter 投递列表过滤条件
+type DeliveryFilter struct {
+	SubscriptionID uint
+	Status         string
+}
+
+var deliveryListSpec = listSpec[models.WebhookDelivery]{
+	sorts: map[string]sortField[models.WebhookDelivery]{
+		"created_at": {column: "created_at", isTime: true, value: func(d *models.WebhookDelivery) interface{} { return d.CreatedAt }},
+	},
+	defaultSort: "created_at",
+	id:          func(d *models.WebhookDelivery) uint { return d.ID },
+}
+
+// ListDeliveries 分页获取投递记录
+func (r *WebhookRepository) ListDeliveries(filter DeliveryFilter, page PageQuery) (*Page[models.WebhookDelivery], error) {
+	query := r.db.Model(&models.WebhookDelivery{}).Where("subscription_id = ?", filter.SubscriptionID)
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	return paginate(query, deliveryListSpec, page)
+}
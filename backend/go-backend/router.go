@@ -0,0 +1,205 @@
+package main
+
+import (
+	"context"
+
+	"hackathon-backend/blockchain"
+	"hackathon-backend/config"
+	"hackathon-backend/controllers"
+	"hackathon-backend/middleware"
+	"hackathon-backend/repositories"
+	"hackathon-backend/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// newRouter 初始化 MVC 层并注册路由，返回路由和需要在后台运行的任务
+func newRouter(db *gorm.DB, txManager *blockchain.TxManager, relayer *blockchain.Relayer) (*gin.Engine, []func(ctx context.Context)) {
+	eventRepo := repositories.NewEventRepository(db)
+	eventBus := services.NewEventBus()
+	webhookRepo := repositories.NewWebhookRepository(db)
+	webhookService := services.NewWebhookService(webhookRepo, eventRepo)
+	eventService := services.NewEventService(eventRepo, eventBus, webhookService)
+	authRepo := repositories.NewAuthRepository(db)
+	authService := services.NewAuthService(authRepo)
+	authPolicy := services.NewAuthPolicy()
+	authController := controllers.NewAuthController(authService)
+	eventController := controllers.NewEventController(eventService, authPolicy)
+	streamController := controllers.NewStreamController(eventBus)
+	graphqlController := controllers.NewGraphQLController(eventService, authPolicy)
+	webhookController := controllers.NewWebhookController(webhookService, authPolicy)
+	syncLogRepo := repositories.NewSyncLogRepository(db)
+	syncLogService := services.NewSyncLogService(syncLogRepo)
+	syncLogController := controllers.NewSyncLogController(syncLogService)
+	checkInRepo := repositories.NewCheckInRepository(db)
+	checkInService := services.NewCheckInService(checkInRepo, eventRepo)
+	checkInController := controllers.NewCheckInController(checkInService, authPolicy)
+	relayRepo := repositories.NewRelayRepository(db)
+	relayerService := services.NewRelayerService(relayRepo, eventRepo, relayer)
+	relayerController := controllers.NewRelayerController(relayerService, eventService, authPolicy)
+	offlineCheckInService := services.NewOfflineCheckInService(checkInRepo, eventRepo, relayerService)
+	offlineCheckInController := controllers.NewOfflineCheckInController(offlineCheckInService, eventService, authPolicy)
+	analyticsRepo := repositories.NewAnalyticsRepository(db)
+	analyticsService := services.NewAnalyticsService(analyticsRepo)
+	analyticsController := controllers.NewAnalyticsController(analyticsService, eventService)
+
+	exportRepo := repositories.NewExportRepository(db)
+	exportService := services.NewExportService(exportRepo)
+	exportController := controllers.NewExportController(exportService, eventService, authPolicy)
+
+	statsRepo := repositories.NewStatsRepository(db)
+	statsRollupService := services.NewStatsRollupService(statsRepo)
+	statsController := controllers.NewStatsController(statsRollupService)
+
+	walletRepo := repositories.NewWalletRepository(db)
+	walletService := services.NewWalletService(walletRepo)
+	walletController := controllers.NewWalletController(walletService, authPolicy)
+
+	feedRepo := repositories.NewFeedRepository(db)
+	calendarService := services.NewCalendarService(feedRepo)
+	calendarController := controllers.NewCalendarController(calendarService, eventService)
+	feedService := services.NewFeedService(feedRepo)
+	feedController := controllers.NewFeedController(feedService)
+	docsController := controllers.NewDocsController()
+
+	// 后台任务由 main 启动
+	tasks := []func(ctx context.Context){
+		// 启动事件摄取 (WebSocket 订阅或 eth_getLogs 轮询)
+		eventService.RunSync,
+		// 启动 Webhook 投递
+		webhookService.Run,
+		// 启动交易管理器（查询回执、重发卡住的交易）
+		txManager.Run,
+		// 启动签到中继（未启用时直接返回）
+		relayerService.Run,
+		// 启动离线签到结算（中继器未启用时只依赖链上日志结算）
+		offlineCheckInService.Run,
+		// 启动同步日志保留与压缩任务
+		syncLogService.Run,
+		// 启动平台统计汇总任务
+		statsRollupService.Run,
+	}
+
+	// 设置 Gin 路由
+	router := gin.New()
+	router.Use(gin.Logger(), middleware.Recovery())
+
+	// 启用 CORS，只对 CORS_ALLOWED_ORIGINS 中的前端来源放行
+	router.Use(func(c *gin.Context) {
+		c.Writer.Header().Add("Vary", "Origin")
+		if origin := c.GetHeader("Origin"); origin != "" && config.AppConfig.IsAllowedOrigin(origin) {
+			c.Writer.Header().Set("Access-Control-Allow-Origin", origin)
+			c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
+		}
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
+		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE")
+
+		if c.Request.Method == "OPTIONS" {
+			c.AbortWithStatus(204)
+			return
+		}
+
+		c.Next()
+	})
+
+	// 解析登录令牌，需要登录/管理员权限的路由再单独挂载 requireAuth/requireAdmin
+	router.Use(middleware.OptionalAuth(authService))
+	requireAuth := middleware.RequireAuth(authService)
+	requireAdmin := middleware.RequireAdmin(authPolicy)
+
+	// 健康检查
+	router.GET("/health", eventController.Health)
+	router.GET("/health/live", eventController.Health)
+	router.GET("/health/ready", eventController.Ready)
+
+	// 登录 API (Sign-In with Ethereum)
+	router.GET("/api/auth/nonce", authController.GetNonce)
+	router.POST("/api/auth/login", authController.Login)
+	router.POST("/api/auth/logout", authController.Logout)
+	router.GET("/api/auth/me", requireAuth, authController.Me)
+
+	// 活动相关 API
+	router.GET("/api/events", eventController.GetAllEvents)
+	router.GET("/api/events/search", eventController.SearchEvents)
+	router.GET("/api/events/:id", eventController.GetEventByID)
+	router.GET("/api/events/organizer", eventController.GetEventsByOrganizer)
+	router.GET("/api/events.ics", calendarController.GetEventsCalendar)
+	router.GET("/api/events/:id/calendar.ics", calendarController.GetEventCalendar)
+	router.GET("/api/events/:id/participants", eventController.GetEventParticipants)
+	router.GET("/api/events/:id/sponsors", eventController.GetEventSponsors)
+	router.GET("/api/events/:id/tickets", eventController.GetEventTickets)
+	router.GET("/api/events/:id/analytics", analyticsController.GetEventAnalytics)
+	router.GET("/api/events/:id/export/:dataset", requireAuth, exportController.Export)
+	router.POST("/api/events/:id/reindex", requireAuth, eventController.ReindexEvent)
+	router.GET("/api/events/:id/devices", requireAuth, offlineCheckInController.GetDevices)
+	router.POST("/api/events/:id/devices", requireAuth, offlineCheckInController.AuthorizeDevice)
+	router.DELETE("/api/events/:id/devices/:address", requireAuth, offlineCheckInController.RevokeDevice)
+	router.GET("/api/events/:id/offline-checkins", requireAuth, offlineCheckInController.GetOfflineCheckIns)
+
+	// 门票相关 API
+	router.GET("/api/tickets", eventController.GetTicketsByHolder)
+	router.POST("/api/tickets/:tokenId/pass", requireAuth, checkInController.IssuePass)
+
+	// 钱包 API
+	router.GET("/api/wallets/:address", walletController.GetProfile)
+	router.GET("/api/wallets/:address/calendar.ics", calendarController.GetWalletCalendar)
+
+	// 订阅源 (Atom / RSS)
+	router.GET("/api/feeds/new.atom", feedController.Feed(services.FeedNew, services.FeedFormatAtom))
+	router.GET("/api/feeds/new.rss", feedController.Feed(services.FeedNew, services.FeedFormatRSS))
+	router.GET("/api/feeds/upcoming.atom", feedController.Feed(services.FeedUpcoming, services.FeedFormatAtom))
+	router.GET("/api/feeds/upcoming.rss", feedController.Feed(services.FeedUpcoming, services.FeedFormatRSS))
+
+	// 签到核验
+	router.POST("/api/checkin/verify", requireAuth, checkInController.VerifyPass)
+	router.POST("/api/checkin/offline", offlineCheckInController.Upload)
+
+	// 签到中继
+	router.GET("/api/relayer", relayerController.GetStatus)
+	router.POST("/api/relayer/checkins", requireAuth, relayerController.CreateCheckIn)
+	router.GET("/api/relayer/checkins/:id", requireAuth, relayerController.GetCheckIn)
+	router.POST("/api/relayer/batches", requireAuth, relayerController.CreateBatch)
+	router.GET("/api/relayer/batches/:batchId", requireAuth, relayerController.GetBatch)
+
+	// GraphQL API
+	router.POST("/api/graphql", graphqlController.Query)
+	router.GET("/api/graphql", graphqlController.Query)
+
+	// 实时推送 API
+	router.GET("/api/stream", streamController.StreamSSE)
+	router.GET("/api/ws", streamController.StreamWebSocket)
+
+	// Webhook API
+	router.POST("/api/webhooks", requireAuth, webhookController.CreateWebhook)
+	router.GET("/api/webhooks", requireAuth, webhookController.GetWebhooks)
+	router.GET("/api/webhooks/:id", requireAuth, webhookController.GetWebhook)
+	router.PUT("/api/webhooks/:id", requireAuth, webhookController.UpdateWebhook)
+	router.DELETE("/api/webhooks/:id", requireAuth, webhookController.DeleteWebhook)
+	router.GET("/api/webhooks/:id/deliveries", requireAuth, webhookController.GetDeliveries)
+	router.GET("/api/webhooks/:id/deliveries/:deliveryId/attempts", requireAuth, webhookController.GetDeliveryAttempts)
+	router.POST("/api/webhooks/:id/deliveries/:deliveryId/redeliver", requireAuth, webhookController.Redeliver)
+
+	// 统计 API
+	router.GET("/api/stats", eventController.GetSyncStats)
+	router.GET("/api/stats/timeseries", statsController.GetTimeseries)
+	router.POST("/api/stats/rollup", requireAuth, requireAdmin, statsController.Rollup)
+
+	// 同步模式 API
+	router.GET("/api/sync/mode", eventController.GetSyncMode)
+	router.PUT("/api/sync/mode", requireAuth, requireAdmin, eventController.SetSyncMode)
+	router.GET("/api/sync/checkpoints", eventController.GetSyncCheckpoints)
+
+	// 同步日志 API
+	router.GET("/api/sync/logs/daily", syncLogController.GetDailyStats)
+	router.POST("/api/sync/logs/compact", requireAuth, requireAdmin, syncLogController.Compact)
+
+	// 测试 API
+	router.POST("/api/test/event", requireAuth, requireAdmin, eventController.CreateTestEvent)
+
+	// API 文档
+	router.GET("/api/openapi.json", docsController.OpenAPISpec)
+	router.GET("/api/docs", docsController.SwaggerUI)
+
+	return router, tasks
+}
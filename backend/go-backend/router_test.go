@@ -0,0 +1,309 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"hackathon-backend/apidocs"
+	"hackathon-backend/config"
+	"hackathon-backend/middleware"
+	"hackathon-backend/models"
+	"hackathon-backend/testdb"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+func newTestRouter(t *testing.T) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	cfg := config.LoadConfig()
+
+	db := testdb.Open(t)
+	if err := models.AutoMigrate(db); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	organizer, _ := models.ParseAddress("0xad6F55f669eaf666b7628d7Bd482Eb000e24D687")
+	event := models.Event{
+		ChainID:         cfg.GetActiveChainID(),
+		Network:         cfg.GetActiveNetworkName(),
+		ContractAddress: "0x0000000000000000000000000000000000000001",
+		EventID:         "1",
+		Organizer:       organizer,
+		Title:           "Demo Day",
+		StartTime:       time.Now().Add(24 * time.Hour).Unix(),
+		EndTime:         time.Now().Add(48 * time.Hour).Unix(),
+		MaxParticipants: 100,
+		Active:          true,
+		SyncedAt:        time.Now(),
+	}
+	if err := db.Create(&event).Error; err != nil {
+		t.Fatalf("seed event: %v", err)
+	}
+
+	router, _ := newRouter(db, nil, nil)
+	return router
+}
+
+func TestRoutesMatchOpenAPISpec(t *testing.T) {
+	router := newTestRouter(t)
+
+	undocumented, missing, err := apidocs.CheckRoutes(router.Routes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, route := range undocumented {
+		t.Errorf("route not documented in openapi.json: %s", route)
+	}
+	for _, route := range missing {
+		t.Errorf("openapi.json documents unregistered route: %s", route)
+	}
+}
+
+func TestResponsesMatchOpenAPISpec(t *testing.T) {
+	router := newTestRouter(t)
+
+	var spec map[string]interface{}
+	if err := json.Unmarshal(apidocs.Spec, &spec); err != nil {
+		t.Fatalf("invalid openapi.json: %v", err)
+	}
+
+	tests := []struct {
+		target string // 实际请求
+		path   string // openapi.json 中的路径
+		status int
+	}{
+		{"/api/events", "/api/events", http.StatusOK},
+		{"/api/events?limit=0", "/api/events", http.StatusBadRequest},
+		{"/api/events/1", "/api/events/{id}", http.StatusOK},
+		{"/api/events/999", "/api/events/{id}", http.StatusNotFound},
+		{"/api/auth/nonce", "/api/auth/nonce", http.StatusOK},
+		{"/api/auth/me", "/api/auth/me", http.StatusUnauthorized},
+		{"/api/sync/mode", "/api/sync/mode", http.StatusOK},
+		{"/api/relayer", "/api/relayer", http.StatusOK},
+		{"/api/stats/timeseries?interval=day", "/api/stats/timeseries", http.StatusOK},
+		{"/api/stats/timeseries?interval=hour", "/api/stats/timeseries", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.target, nil))
+			if w.Code != tt.status {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.status, w.Body.String())
+			}
+
+			schema, err := responseSchema(spec, tt.path, tt.status)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var body interface{}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("invalid JSON body: %v", err)
+			}
+			if err := validate(spec, schema, body, "$"); err != nil {
+				t.Errorf("%v\nbody: %s", err, w.Body.String())
+			}
+		})
+	}
+}
+
+// responseSchema 取出 GET path 在 status 下的 application/json schema
+func TestRecoveryAbortsStartedResponse(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.Recovery())
+	router.GET("/export", func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+		ctx.Writer.WriteString("id,wallet\n")
+		ctx.Writer.Flush()
+		panic(http.ErrAbortHandler)
+	})
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/export")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if _, err := io.ReadAll(resp.Body); err == nil {
+		t.Error("aborted download read to completion")
+	}
+}
+
+func TestCrossOriginRequests(t *testing.T) {
+	router := newTestRouter(t)
+	config.AppConfig.AllowedOrigins = "https://app.example, https://admin.example/"
+	server := httptest.NewServer(router)
+	defer server.Close()
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http") + "/api/ws"
+
+	tests := []struct {
+		name      string
+		origin    string
+		wantCORS  string
+		wantWSErr bool
+	}{
+		{name: "allowed origin", origin: "https://app.example", wantCORS: "https://app.example"},
+		{name: "allowed origin with trailing slash in config", origin: "https://admin.example", wantCORS: "https://admin.example"},
+		{name: "other origin", origin: "https://evil.example", wantWSErr: true},
+		{name: "no origin"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/health/live", nil)
+			header := http.Header{}
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+				header.Set("Origin", tt.origin)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			if got := w.Header().Get("Access-Control-Allow-Origin"); got != tt.wantCORS {
+				t.Errorf("Access-Control-Allow-Origin = %q, want %q", got, tt.wantCORS)
+			}
+
+			conn, resp, err := websocket.DefaultDialer.Dial(wsURL, header)
+			if conn != nil {
+				conn.Close()
+			}
+			if (err != nil) != tt.wantWSErr {
+				t.Fatalf("websocket dial error = %v, want error %v", err, tt.wantWSErr)
+			}
+			if tt.wantWSErr && resp.StatusCode != http.StatusForbidden {
+				t.Errorf("websocket handshake status = %d, want 403", resp.StatusCode)
+			}
+		})
+	}
+}
+
+func responseSchema(spec map[string]interface{}, path string, status int) (map[string]interface{}, error) {
+	operation, ok := lookup(spec, "paths", path, "get")
+	if !ok {
+		return nil, fmt.Errorf("GET %s not documented", path)
+	}
+	response, ok := lookup(operation, "responses", strconv.Itoa(status))
+	if !ok {
+		return nil, fmt.Errorf("GET %s has no %d response", path, status)
+	}
+	response = resolve(spec, response)
+	schema, ok := lookup(response, "content", "application/json", "schema")
+	if !ok {
+		return nil, fmt.Errorf("GET %s %d has no JSON schema", path, status)
+	}
+	return resolve(spec, schema), nil
+}
+
+func lookup(node map[string]interface{}, keys ...string) (map[string]interface{}, bool) {
+	for _, key := range keys {
+		next, ok := node[key].(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		node = next
+	}
+	return node, true
+}
+
+// resolve 展开 "#/components/..." 形式的 $ref
+func resolve(spec, node map[string]interface{}) map[string]interface{} {
+	for {
+		ref, ok := node["$ref"].(string)
+		if !ok {
+			return node
+		}
+		target, ok := lookup(spec, strings.Split(strings.TrimPrefix(ref, "#/"), "/")...)
+		if !ok {
+			return map[string]interface{}{"not": ref}
+		}
+		node = target
+	}
+}
+
+// validate 只校验 type / required / properties / items / enum，足以发现响应包络和字段类型不一致
+func validate(spec, schema map[string]interface{}, value interface{}, at string) error {
+	schema = resolve(spec, schema)
+	if ref, ok := schema["not"].(string); ok {
+		return fmt.Errorf("%s: unresolved $ref %s", at, ref)
+	}
+	if value == nil {
+		if nullable, _ := schema["nullable"].(bool); nullable || schema["type"] == nil {
+			return nil
+		}
+		return fmt.Errorf("%s: null is not nullable", at)
+	}
+
+	switch schema["type"] {
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s: want object, got %T", at, value)
+		}
+		required, _ := schema["required"].([]interface{})
+		for _, name := range required {
+			if _, ok := object[name.(string)]; !ok {
+				return fmt.Errorf("%s: missing required field %q", at, name)
+			}
+		}
+		properties, _ := schema["properties"].(map[string]interface{})
+		for name, property := range properties {
+			field, ok := object[name]
+			if !ok {
+				continue
+			}
+			if err := validate(spec, property.(map[string]interface{}), field, at+"."+name); err != nil {
+				return err
+			}
+		}
+	case "array":
+		array, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("%s: want array, got %T", at, value)
+		}
+		items, _ := schema["items"].(map[string]interface{})
+		for i, item := range array {
+			if items == nil {
+				break
+			}
+			if err := validate(spec, items, item, fmt.Sprintf("%s[%d]", at, i)); err != nil {
+				return err
+			}
+		}
+	case "string":
+		if _, ok := value.(string); !ok {
+			return fmt.Errorf("%s: want string, got %T", at, value)
+		}
+	case "integer":
+		n, ok := value.(float64)
+		if !ok || n != float64(int64(n)) {
+			return fmt.Errorf("%s: want integer, got %v", at, value)
+		}
+	case "number":
+		if _, ok := value.(float64); !ok {
+			return fmt.Errorf("%s: want number, got %T", at, value)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("%s: want boolean, got %T", at, value)
+		}
+	}
+
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		for _, allowed := range enum {
+			if allowed == value {
+				return nil
+			}
+		}
+		return fmt.Errorf("%s: %v not in enum %v", at, value, enum)
+	}
+	return nil
+}
@@ -0,0 +1,288 @@
+package services
+
+import (
+	"errors"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+
+	"hackathon-backend/config"
+	"hackathon-backend/models"
+	"hackathon-backend/repositories"
+)
+
+// 分析时间桶
+const (
+	IntervalHour = "hour"
+	IntervalDay  = "day"
+)
+
+// analyticsMaxFilledBuckets 补零后的最大桶数，超过时只返回有数据的桶
+const analyticsMaxFilledBuckets = 2000
+
+// nativeTokenDecimals 各网络原生代币精度
+const nativeTokenDecimals = 18
+
+var ErrInvalidInterval = errors.New("interval must be hour or day")
+
+// SeriesPoint 时间序列中的一个桶
+type SeriesPoint struct {
+	Time       int64 `json:"t"` // 桶起始时间（Unix 秒）
+	Count      int64 `json:"count"`
+	Cumulative int64 `json:"cumulative"`
+}
+
+// RegistrationAnalytics 报名统计
+type RegistrationAnalytics struct {
+	Total         int64         `json:"total"`
+	Capacity      uint64        `json:"capacity"`       // 合约中的 MaxParticipants
+	CapacityRatio *float64      `json:"capacity_ratio"` // 报名人数 / 容量，容量为 0 时为 null
+	Series        []SeriesPoint `json:"series"`
+}
+
+// CheckInAnalytics 签到统计，临时签到（离线签到尚未上链）计入签到人数
+type CheckInAnalytics struct {
+	CheckedIn   int64         `json:"checked_in"`
+	Provisional int64         `json:"provisional"`
+	Rate        float64       `json:"rate"` // 签到人数 / 报名人数
+	NoShows     int64         `json:"no_shows"`
+	NoShowRate  float64       `json:"no_show_rate"`
+	Final       bool          `json:"final"` // 活动已结束，未到场人数不再变化
+	Series      []SeriesPoint `json:"series"`
+}
+
+// SponsorTotal 按代币单位汇总的赞助金额
+type SponsorTotal struct {
+	Network   string `json:"network"`
+	Unit      string `json:"unit"`
+	Decimals  int    `json:"decimals"`
+	Count     int64  `json:"count"`
+	AmountWei string `json:"amount_wei"` // 最小单位
+	Amount    string `json:"amount"`     // 按精度换算后的十进制字符串
+}
+
+// SponsorAnalytics 赞助统计
+type SponsorAnalytics struct {
+	Count  int64          `json:"count"`
+	Totals []SponsorTotal `json:"totals"`
+}
+
+// TicketAnalytics 门票统计
+type TicketAnalytics struct {
+	Issued         int64         `json:"issued"`
+	Used           int64         `json:"used"`
+	Transfers      int64         `json:"transfers"`
+	TransferSeries []SeriesPoint `json:"transfer_series"`
+}
+
+// EventAnalytics 单个活动的分析数据
+type EventAnalytics struct {
+	ChainID       uint64                `json:"chain_id"`
+	Network       string                `json:"network"`
+	EventID       string                `json:"event_id"`
+	StartTime     int64                 `json:"start_time"`
+	EndTime       int64                 `json:"end_time"`
+	Interval      string                `json:"interval"`
+	Registrations RegistrationAnalytics `json:"registrations"`
+	CheckIns      CheckInAnalytics      `json:"check_ins"`
+	Sponsors      SponsorAnalytics      `json:"sponsors"`
+	Tickets       TicketAnalytics       `json:"tickets"`
+	GeneratedAt   time.Time             `json:"generated_at"`
+}
+
+// AnalyticsService 基于已索引数据计算统计
+type AnalyticsService struct {
+	repo *repositories.AnalyticsRepository
+}
+
+func NewAnalyticsService(repo *repositories.AnalyticsRepository) *AnalyticsService {
+	return &AnalyticsService{repo: repo}
+}
+
+// GetEventAnalytics 计算活动的报名、签到、赞助和门票统计；interval 为空时活动跨度不超过 3 天按小时，否则按天
+func (s *AnalyticsService) GetEventAnalytics(event *models.Event, interval string) (*EventAnalytics, error) {
+	if interval == "" {
+		interval = IntervalHour
+		if event.EndTime-event.StartTime > 3*24*3600 {
+			interval = IntervalDay
+		}
+	}
+	var bucket int64
+	switch interval {
+	case IntervalHour:
+		bucket = 3600
+	case IntervalDay:
+		bucket = 24 * 3600
+	default:
+		return nil, ErrInvalidInterval
+	}
+
+	now := time.Now()
+	result := &EventAnalytics{
+		ChainID:     event.ChainID,
+		Network:     event.Network,
+		EventID:     event.EventID,
+		StartTime:   event.StartTime,
+		EndTime:     event.EndTime,
+		Interval:    interval,
+		GeneratedAt: now,
+	}
+
+	summary, err := s.repo.GetParticipantSummary(event)
+	if err != nil {
+		return nil, err
+	}
+	registrations, err := s.repo.GetRegistrationBuckets(event, bucket)
+	if err != nil {
+		return nil, err
+	}
+	result.Registrations = RegistrationAnalytics{
+		Total:    summary.Registered,
+		Capacity: event.MaxParticipants,
+		Series:   fillSeries(registrations, bucket, 0, 0),
+	}
+	if event.MaxParticipants > 0 {
+		ratio := float64(summary.Registered) / float64(event.MaxParticipants)
+		result.Registrations.CapacityRatio = &ratio
+	}
+
+	checkIns, err := s.repo.GetCheckInBuckets(event, bucket)
+	if err != nil {
+		return nil, err
+	}
+	// 签到曲线覆盖活动进行期间，没有签到的桶补零
+	windowEnd := event.EndTime
+	if windowEnd > now.Unix() {
+		windowEnd = now.Unix()
+	}
+	result.CheckIns = CheckInAnalytics{
+		CheckedIn:   summary.CheckedIn,
+		Provisional: summary.Provisional,
+		NoShows:     summary.Registered - summary.CheckedIn,
+		Final:       event.EndTime > 0 && now.Unix() >= event.EndTime,
+		Series:      fillSeries(checkIns, bucket, event.StartTime, windowEnd),
+	}
+	if summary.Registered > 0 {
+		result.CheckIns.Rate = float64(summary.CheckedIn) / float64(summary.Registered)
+		result.CheckIns.NoShowRate = float64(result.CheckIns.NoShows) / float64(summary.Registered)
+	}
+
+	amounts, err := s.repo.GetSponsorAmounts(event)
+	if err != nil {
+		return nil, err
+	}
+	result.Sponsors = SponsorAnalytics{Count: int64(len(amounts)), Totals: sumSponsorAmounts(amounts)}
+
+	tickets, err := s.repo.GetTicketSummary(event)
+	if err != nil {
+		return nil, err
+	}
+	transfers, err := s.repo.GetTransferBuckets(event, bucket)
+	if err != nil {
+		return nil, err
+	}
+	result.Tickets = TicketAnalytics{
+		Issued:         tickets.Issued,
+		Used:           tickets.Used,
+		TransferSeries: fillSeries(transfers, bucket, 0, 0),
+	}
+	for _, b := range transfers {
+		result.Tickets.Transfers += b.Count
+	}
+
+	return result, nil
+}
+
+// fillSeries 将分桶计数转换为带累计值的序列，并在 [from, to] 与数据范围内补零；桶数过多时不补零
+func fillSeries(buckets []repositories.BucketCount, bucket int64, from int64, to int64) []SeriesPoint {
+	counts := make(map[int64]int64, len(buckets))
+	var first, last int64
+	for i, b := range buckets {
+		counts[b.Bucket] = b.Count
+		if i == 0 || b.Bucket < first {
+			first = b.Bucket
+		}
+		if b.Bucket > last {
+			last = b.Bucket
+		}
+	}
+	if from > 0 && to >= from {
+		from, to = from/bucket*bucket, to/bucket*bucket
+		if len(buckets) == 0 || from < first {
+			first = from
+		}
+		if len(buckets) == 0 || to > last {
+			last = to
+		}
+	} else if len(buckets) == 0 {
+		return []SeriesPoint{}
+	}
+
+	series := make([]SeriesPoint, 0, len(buckets))
+	var cumulative int64
+	if (last-first)/bucket+1 > analyticsMaxFilledBuckets {
+		for _, b := range buckets {
+			cumulative += b.Count
+			series = append(series, SeriesPoint{Time: b.Bucket, Count: b.Count, Cumulative: cumulative})
+		}
+		return series
+	}
+	for t := first; t <= last; t += bucket {
+		cumulative += counts[t]
+		series = append(series, SeriesPoint{Time: t, Count: counts[t], Cumulative: cumulative})
+	}
+	return series
+}
+
+// sumSponsorAmounts 按网络（原生代币）汇总赞助金额，金额以最小单位存储
+func sumSponsorAmounts(amounts []repositories.SponsorAmount) []SponsorTotal {
+	type total struct {
+		sum   *big.Int
+		count int64
+	}
+	totals := make(map[string]*total)
+	for _, a := range amounts {
+		t, ok := totals[a.Network]
+		if !ok {
+			t = &total{sum: new(big.Int)}
+			totals[a.Network] = t
+		}
+		t.count++
+		if v, ok := new(big.Int).SetString(a.Amount, 10); ok {
+			t.sum.Add(t.sum, v)
+		}
+	}
+
+	result := make([]SponsorTotal, 0, len(totals))
+	for network, t := range totals {
+		result = append(result, SponsorTotal{
+			Network:   network,
+			Unit:      config.NativeTokenSymbol(network),
+			Decimals:  nativeTokenDecimals,
+			Count:     t.count,
+			AmountWei: t.sum.String(),
+			Amount:    formatUnits(t.sum, nativeTokenDecimals),
+		})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Network < result[j].Network })
+	return result
+}
+
+// formatUnits 将最小单位换算为十进制字符串，例如 1500000000000000000 -> "1.5"
+func formatUnits(value *big.Int, decimals int) string {
+	base := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+	whole, frac := new(big.Int).QuoRem(value, base, new(big.Int))
+	if frac.Sign() == 0 {
+		return whole.String()
+	}
+	fracStr := strings.TrimRight(leftPad(frac.String(), decimals), "0")
+	return whole.String() + "." + fracStr
+}
+
+func leftPad(s string, width int) string {
+	if len(s) >= width {
+		return s
+	}
+	return strings.Repeat("0", width-len(s)) + s
+}
@@ -0,0 +1,115 @@
+package services
+
+import (
+	"errors"
+	"log"
+	"strings"
+
+	"hackathon-backend/config"
+	"hackathon-backend/models"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	ErrUnauthenticated = errors.New("authentication required")
+	ErrForbidden       = errors.New("permission denied")
+)
+
+// AuthPolicy 基于索引数据的授权规则：
+// 活动组织者（Event.Organizer，且登录链与活动所在链一致）可以管理自己的活动，
+// 管理员（AUTH_ADMIN_ADDRESSES）可以执行所有操作
+type AuthPolicy struct {
+	admins map[common.Address]bool
+}
+
+func NewAuthPolicy() *AuthPolicy {
+	admins := map[common.Address]bool{}
+	for _, addr := range strings.Split(config.AppConfig.AuthAdminAddresses, ",") {
+		addr = strings.TrimSpace(addr)
+		if addr == "" {
+			continue
+		}
+		if !common.IsHexAddress(addr) {
+			log.Printf("⚠️ Ignoring invalid admin address: %s", addr)
+			continue
+		}
+		admins[common.HexToAddress(addr)] = true
+	}
+	return &AuthPolicy{admins: admins}
+}
+
+// IsAdmin 是否为管理员
+func (p *AuthPolicy) IsAdmin(session *SessionClaims) bool {
+	return session != nil && common.IsHexAddress(session.Address) && p.admins[common.HexToAddress(session.Address)]
+}
+
+// IsOrganizer 是否为活动组织者，登录令牌绑定的链需与活动所在链一致
+func (p *AuthPolicy) IsOrganizer(session *SessionClaims, event *models.Event) bool {
+	return session != nil && event != nil &&
+		session.ChainID == event.ChainID && session.Wallet().Equal(event.Organizer)
+}
+
+// CanManageEvent 是否可以管理活动（查看完整参与者信息、导出名单、重新索引等）
+func (p *AuthPolicy) CanManageEvent(session *SessionClaims, event *models.Event) bool {
+	return p.IsAdmin(session) || p.IsOrganizer(session, event)
+}
+
+// CanViewWallet 是否可以查看钱包的非公开信息（如报名时填写的姓名）：钱包本人或管理员
+func (p *AuthPolicy) CanViewWallet(session *SessionClaims, wallet models.Address) bool {
+	return p.IsAdmin(session) || (session != nil && session.Wallet().Equal(wallet))
+}
+
+// AuthorizeEventManagement 校验活动管理权限，event 为 nil 时只有管理员可以操作
+func (p *AuthPolicy) AuthorizeEventManagement(session *SessionClaims, event *models.Event) error {
+	if session == nil {
+		return ErrUnauthenticated
+	}
+	if !p.CanManageEvent(session, event) {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// AuthorizeOrganizer 校验当前钱包是否为该组织者（用于 Webhook 等按组织者归属的资源）
+func (p *AuthPolicy) AuthorizeOrganizer(session *SessionClaims, organizer models.Address) error {
+	if session == nil {
+		return ErrUnauthenticated
+	}
+	if !p.IsAdmin(session) && !session.Wallet().Equal(organizer) {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// AuthorizeAdmin 校验管理员权限
+func (p *AuthPolicy) AuthorizeAdmin(session *SessionClaims) error {
+	if session == nil {
+		return ErrUnauthenticated
+	}
+	if !p.IsAdmin(session) {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// RedactParticipant 隐藏参与者的姓名；参与者本人可以看到自己的完整信息
+func (p *AuthPolicy) RedactParticipant(session *SessionClaims, participant *models.Participant) {
+	if session != nil && session.Wallet().Equal(participant.Wallet) {
+		return
+	}
+	redactParticipant(participant)
+}
+
+// redactParticipant 按匿名访问者隐藏参与者的姓名；钱包地址本身已公开在链上，保持不变
+func redactParticipant(participant *models.Participant) {
+	participant.Name = ""
+}
+
+// MaskAddress 截断地址为 0x1234…abcd 形式
+func MaskAddress(addr string) string {
+	if len(addr) <= 10 {
+		return addr
+	}
+	return addr[:6] + "…" + addr[len(addr)-4:]
+}
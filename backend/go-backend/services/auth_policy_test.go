@@ -0,0 +1,165 @@
+package services_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"hackathon-backend/config"
+	"hackathon-backend/middleware"
+	"hackathon-backend/models"
+	"hackathon-backend/services"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	adminWallet     = common.HexToAddress("0x1111111111111111111111111111111111111111")
+	organizerWallet = common.HexToAddress("0xad6F55f669eaf666b7628d7Bd482Eb000e24D687")
+	strangerWallet  = common.HexToAddress("0x2222222222222222222222222222222222222222")
+)
+
+func newTestPolicy() *services.AuthPolicy {
+	config.AppConfig = &config.Config{AuthAdminAddresses: " " + adminWallet.Hex() + ",not-an-address"}
+	return services.NewAuthPolicy()
+}
+
+func session(wallet common.Address, chainID uint64) *services.SessionClaims {
+	return &services.SessionClaims{Address: wallet.Hex(), ChainID: chainID}
+}
+
+func testEvent() *models.Event {
+	return &models.Event{ChainID: 50312, EventID: "1", Organizer: models.AddressFrom(organizerWallet)}
+}
+
+func TestAuthPolicyRoles(t *testing.T) {
+	policy := newTestPolicy()
+	event := testEvent()
+
+	tests := []struct {
+		name        string
+		session     *services.SessionClaims
+		event       *models.Event
+		wantAdmin   bool
+		wantOrg     bool
+		wantManage  bool
+		wantAuthErr error
+	}{
+		{name: "anonymous", event: event, wantAuthErr: services.ErrUnauthenticated},
+		{name: "admin", session: session(adminWallet, 10143), event: event, wantAdmin: true, wantManage: true},
+		{name: "admin without event", session: session(adminWallet, 50312), wantAdmin: true, wantManage: true},
+		{name: "organizer", session: session(organizerWallet, 50312), event: event, wantOrg: true, wantManage: true},
+		{
+			name:    "organizer lowercase address",
+			session: &services.SessionClaims{Address: strings.ToLower(organizerWallet.Hex()), ChainID: 50312},
+			event:   event, wantOrg: true, wantManage: true,
+		},
+		{name: "organizer on wrong chain", session: session(organizerWallet, 5003), event: event, wantAuthErr: services.ErrForbidden},
+		{name: "organizer without event", session: session(organizerWallet, 50312), wantAuthErr: services.ErrForbidden},
+		{name: "stranger", session: session(strangerWallet, 50312), event: event, wantAuthErr: services.ErrForbidden},
+		{name: "malformed session address", session: &services.SessionClaims{Address: "0xnope", ChainID: 50312}, event: event, wantAuthErr: services.ErrForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := policy.IsAdmin(tt.session); got != tt.wantAdmin {
+				t.Errorf("IsAdmin = %v, want %v", got, tt.wantAdmin)
+			}
+			if got := policy.IsOrganizer(tt.session, tt.event); got != tt.wantOrg {
+				t.Errorf("IsOrganizer = %v, want %v", got, tt.wantOrg)
+			}
+			if got := policy.CanManageEvent(tt.session, tt.event); got != tt.wantManage {
+				t.Errorf("CanManageEvent = %v, want %v", got, tt.wantManage)
+			}
+			if err := policy.AuthorizeEventManagement(tt.session, tt.event); err != tt.wantAuthErr {
+				t.Errorf("AuthorizeEventManagement = %v, want %v", err, tt.wantAuthErr)
+			}
+		})
+	}
+}
+
+func TestAuthPolicyRedactParticipant(t *testing.T) {
+	policy := newTestPolicy()
+	wallet := models.AddressFrom(strangerWallet)
+
+	tests := []struct {
+		name       string
+		session    *services.SessionClaims
+		wantName   string
+		wantWallet models.Address
+	}{
+		{name: "anonymous", wantWallet: wallet},
+		{name: "other wallet", session: session(organizerWallet, 50312), wantWallet: wallet},
+		{name: "participant themselves", session: session(strangerWallet, 5003), wantName: "Alice", wantWallet: wallet},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			participant := &models.Participant{Wallet: wallet, Name: "Alice"}
+			policy.RedactParticipant(tt.session, participant)
+			if participant.Name != tt.wantName || participant.Wallet != tt.wantWallet {
+				t.Errorf("got name %q wallet %q, want %q %q", participant.Name, participant.Wallet, tt.wantName, tt.wantWallet)
+			}
+		})
+	}
+}
+
+func TestAuthPolicyCanViewWallet(t *testing.T) {
+	policy := newTestPolicy()
+
+	tests := []struct {
+		name    string
+		session *services.SessionClaims
+		want    bool
+	}{
+		{name: "anonymous"},
+		{name: "other wallet", session: session(organizerWallet, 50312)},
+		{name: "wallet itself", session: session(strangerWallet, 5003), want: true},
+		{name: "admin", session: session(adminWallet, 50312), want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := policy.CanViewWallet(tt.session, models.Address(strings.ToLower(strangerWallet.Hex()))); got != tt.want {
+				t.Errorf("CanViewWallet = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAbortWithAuthError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name          string
+		err           error
+		wantStatus    int
+		wantChallenge bool
+	}{
+		{name: "unauthenticated", err: services.ErrUnauthenticated, wantStatus: http.StatusUnauthorized, wantChallenge: true},
+		{name: "forbidden", err: services.ErrForbidden, wantStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+			middleware.AbortWithAuthError(ctx, tt.err)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if got := w.Header().Get("WWW-Authenticate") != ""; got != tt.wantChallenge {
+				t.Errorf("WWW-Authenticate present = %v, want %v", got, tt.wantChallenge)
+			}
+			if want := `{"error":"` + tt.err.Error() + `"}`; w.Body.String() != want {
+				t.Errorf("body = %s, want %s", w.Body.String(), want)
+			}
+			if !ctx.IsAborted() {
+				t.Error("request not aborted")
+			}
+		})
+	}
+}
@@ -0,0 +1,349 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"hackathon-backend/config"
+	"hackathon-backend/models"
+	"hackathon-backend/repositories"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	ErrInvalidSiweMessage = errors.New("invalid SIWE message")
+	ErrInvalidSignature   = errors.New("invalid signature")
+	ErrInvalidNonce       = errors.New("nonce is invalid, expired or already used")
+	ErrInvalidToken       = errors.New("invalid or expired token")
+)
+
+const (
+	siweHeaderSuffix = " wants you to sign in with your Ethereum account:"
+	authTokenIssuer  = "hackathon-backend"
+	// siweClockSkew 允许的客户端时钟偏差
+	siweClockSkew = time.Minute
+)
+
+// SiweMessage EIP-4361 登录消息
+type SiweMessage struct {
+	Scheme         string
+	Domain         string
+	Address        common.Address
+	Statement      string
+	URI            string
+	Version        string
+	ChainID        uint64
+	Nonce          string
+	IssuedAt       time.Time
+	ExpirationTime *time.Time
+	NotBefore      *time.Time
+	RequestID      string
+	Resources      []string
+}
+
+// ParseSiweMessage 按 EIP-4361 格式解析登录消息
+func ParseSiweMessage(raw string) (*SiweMessage, error) {
+	lines := strings.Split(strings.ReplaceAll(raw, "\r\n", "\n"), "\n")
+	if len(lines) < 4 {
+		return nil, fmt.Errorf("%w: message too short", ErrInvalidSiweMessage)
+	}
+
+	msg := &SiweMessage{}
+
+	// 第一行：[scheme://]domain wants you to sign in with your Ethereum account:
+	header, ok := strings.CutSuffix(lines[0], siweHeaderSuffix)
+	if !ok || header == "" {
+		return nil, fmt.Errorf("%w: malformed header", ErrInvalidSiweMessage)
+	}
+	if scheme, domain, found := strings.Cut(header, "://"); found {
+		msg.Scheme, msg.Domain = scheme, domain
+	} else {
+		msg.Domain = header
+	}
+
+	if !common.IsHexAddress(lines[1]) {
+		return nil, fmt.Errorf("%w: malformed address", ErrInvalidSiweMessage)
+	}
+	msg.Address = common.HexToAddress(lines[1])
+
+	// 地址后是空行，可选的 statement 后再跟一个空行
+	if lines[2] != "" {
+		return nil, fmt.Errorf("%w: expected empty line after address", ErrInvalidSiweMessage)
+	}
+	i := 3
+	if lines[i] != "" {
+		msg.Statement = lines[i]
+		i++
+	}
+	if i >= len(lines) || lines[i] != "" {
+		return nil, fmt.Errorf("%w: expected empty line before fields", ErrInvalidSiweMessage)
+	}
+	i++
+
+	var err error
+	for ; i < len(lines); i++ {
+		line := lines[i]
+		if line == "" && i == len(lines)-1 {
+			break
+		}
+		if line == "Resources:" {
+			for i++; i < len(lines) && strings.HasPrefix(lines[i], "- "); i++ {
+				msg.Resources = append(msg.Resources, strings.TrimPrefix(lines[i], "- "))
+			}
+			i--
+			continue
+		}
+
+		key, value, found := strings.Cut(line, ": ")
+		if !found {
+			return nil, fmt.Errorf("%w: malformed line %q", ErrInvalidSiweMessage, line)
+		}
+		switch key {
+		case "URI":
+			msg.URI = value
+		case "Version":
+			msg.Version = value
+		case "Chain ID":
+			if msg.ChainID, err = strconv.ParseUint(value, 10, 64); err != nil {
+				return nil, fmt.Errorf("%w: malformed chain id", ErrInvalidSiweMessage)
+			}
+		case "Nonce":
+			msg.Nonce = value
+		case "Issued At":
+			if msg.IssuedAt, err = time.Parse(time.RFC3339, value); err != nil {
+				return nil, fmt.Errorf("%w: malformed issued at", ErrInvalidSiweMessage)
+			}
+		case "Expiration Time":
+			t, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return nil, fmt.Errorf("%w: malformed expiration time", ErrInvalidSiweMessage)
+			}
+			msg.ExpirationTime = &t
+		case "Not Before":
+			t, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return nil, fmt.Errorf("%w: malformed not before", ErrInvalidSiweMessage)
+			}
+			msg.NotBefore = &t
+		case "Request ID":
+			msg.RequestID = value
+		default:
+			return nil, fmt.Errorf("%w: unknown field %q", ErrInvalidSiweMessage, key)
+		}
+	}
+
+	switch {
+	case msg.URI == "":
+		return nil, fmt.Errorf("%w: missing URI", ErrInvalidSiweMessage)
+	case msg.Version != "1":
+		return nil, fmt.Errorf("%w: unsupported version %q", ErrInvalidSiweMessage, msg.Version)
+	case msg.ChainID == 0:
+		return nil, fmt.Errorf("%w: missing chain id", ErrInvalidSiweMessage)
+	case len(msg.Nonce) < 8:
+		return nil, fmt.Errorf("%w: missing or short nonce", ErrInvalidSiweMessage)
+	case msg.IssuedAt.IsZero():
+		return nil, fmt.Errorf("%w: missing issued at", ErrInvalidSiweMessage)
+	}
+	return msg, nil
+}
+
+// RecoverPersonalSigner 从 personal_sign (EIP-191) 签名中恢复签名地址
+func RecoverPersonalSigner(message string, signature string) (common.Address, error) {
+	sig, err := hexutil.Decode(signature)
+	if err != nil || len(sig) != crypto.SignatureLength {
+		return common.Address{}, ErrInvalidSignature
+	}
+	// 钱包返回的 v 为 27/28，crypto 需要 0/1
+	if sig[crypto.RecoveryIDOffset] >= 27 {
+		sig[crypto.RecoveryIDOffset] -= 27
+	}
+
+	pub, err := crypto.SigToPub(accounts.TextHash([]byte(message)), sig)
+	if err != nil {
+		return common.Address{}, ErrInvalidSignature
+	}
+	return crypto.PubkeyToAddress(*pub), nil
+}
+
+// SessionClaims 登录令牌内容，绑定钱包地址和链
+type SessionClaims struct {
+	Address string `json:"address"`
+	ChainID uint64 `json:"chain_id"`
+	jwt.RegisteredClaims
+}
+
+// Wallet 登录钱包地址
+func (c *SessionClaims) Wallet() models.Address {
+	return models.Address(c.Address)
+}
+
+// AuthSession 登录结果
+type AuthSession struct {
+	Token     string    `json:"token"`
+	Address   string    `json:"address"`
+	ChainID   uint64    `json:"chain_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type AuthService struct {
+	repo     *repositories.AuthRepository
+	secret   []byte
+	tokenTTL time.Duration
+	nonceTTL time.Duration
+	domains  map[string]bool
+}
+
+func NewAuthService(repo *repositories.AuthRepository) *AuthService {
+	cfg := config.AppConfig
+
+	secret := []byte(cfg.AuthJWTSecret)
+	if len(secret) == 0 {
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			log.Fatalf("❌ Failed to generate auth secret: %v", err)
+		}
+		log.Println("⚠️ AUTH_JWT_SECRET not set, using a random secret (tokens are invalidated on restart)")
+	}
+
+	domains := map[string]bool{}
+	for _, domain := range strings.Split(cfg.AuthAllowedDomains, ",") {
+		if domain = strings.TrimSpace(domain); domain != "" {
+			domains[domain] = true
+		}
+	}
+
+	return &AuthService{
+		repo:     repo,
+		secret:   secret,
+		tokenTTL: time.Duration(cfg.AuthTokenTTL) * time.Second,
+		nonceTTL: time.Duration(cfg.AuthNonceTTL) * time.Second,
+		domains:  domains,
+	}
+}
+
+// IssueNonce 签发一次性 nonce，并清理过期的 nonce
+func (s *AuthService) IssueNonce() (*models.AuthNonce, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	nonce := &models.AuthNonce{
+		Nonce:     hex.EncodeToString(buf),
+		ExpiresAt: now.Add(s.nonceTTL),
+	}
+	if err := s.repo.CreateNonce(nonce); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.repo.DeleteExpiredNonces(now); err != nil {
+		log.Printf("⚠️ Failed to delete expired auth nonces: %v", err)
+	}
+	return nonce, nil
+}
+
+// Login 校验 SIWE 消息与签名，消费 nonce 后签发登录令牌
+func (s *AuthService) Login(message string, signature string) (*AuthSession, error) {
+	msg, err := ParseSiweMessage(message)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.domains[msg.Domain] {
+		return nil, fmt.Errorf("%w: domain %q is not allowed", ErrInvalidSiweMessage, msg.Domain)
+	}
+	if !config.AppConfig.IsSupportedChainID(msg.ChainID) {
+		return nil, fmt.Errorf("%w: unsupported chain id %d", ErrInvalidSiweMessage, msg.ChainID)
+	}
+
+	now := time.Now()
+	if msg.IssuedAt.After(now.Add(siweClockSkew)) {
+		return nil, fmt.Errorf("%w: issued in the future", ErrInvalidSiweMessage)
+	}
+	if msg.ExpirationTime != nil && !now.Before(*msg.ExpirationTime) {
+		return nil, fmt.Errorf("%w: message expired", ErrInvalidSiweMessage)
+	}
+	if msg.NotBefore != nil && now.Add(siweClockSkew).Before(*msg.NotBefore) {
+		return nil, fmt.Errorf("%w: message not yet valid", ErrInvalidSiweMessage)
+	}
+
+	signer, err := RecoverPersonalSigner(message, signature)
+	if err != nil {
+		return nil, err
+	}
+	if signer != msg.Address {
+		return nil, ErrInvalidSignature
+	}
+
+	// 签名校验通过后再消费 nonce，避免伪造请求耗尽合法用户的 nonce
+	ok, err := s.repo.ConsumeNonce(msg.Nonce, now)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrInvalidNonce
+	}
+
+	session, err := s.IssueToken(msg.Address, msg.ChainID)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("🔐 Wallet %s signed in on chain %d", session.Address, session.ChainID)
+	return session, nil
+}
+
+// IssueToken 为钱包签发登录令牌
+func (s *AuthService) IssueToken(address common.Address, chainID uint64) (*AuthSession, error) {
+	jti := make([]byte, 16)
+	if _, err := rand.Read(jti); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(s.tokenTTL)
+	claims := SessionClaims{
+		Address: address.Hex(),
+		ChainID: chainID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    authTokenIssuer,
+			Subject:   address.Hex(),
+			ID:        hex.EncodeToString(jti),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.secret)
+	if err != nil {
+		return nil, err
+	}
+	return &AuthSession{Token: token, Address: claims.Address, ChainID: chainID, ExpiresAt: expiresAt}, nil
+}
+
+// ParseToken 校验登录令牌并返回其内容
+func (s *AuthService) ParseToken(token string) (*SessionClaims, error) {
+	claims := &SessionClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(*jwt.Token) (interface{}, error) {
+		return s.secret, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(authTokenIssuer),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil || !common.IsHexAddress(claims.Address) {
+		return nil, ErrInvalidToken
+	}
+	return claims, nil
+}
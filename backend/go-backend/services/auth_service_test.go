@@ -0,0 +1,172 @@
+package services
+
+import (
+	"crypto/ecdsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"hackathon-backend/config"
+	"hackathon-backend/models"
+	"hackathon-backend/repositories"
+	"hackathon-backend/testdb"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const testSiweDomain = "app.example"
+
+func newTestAuthService(t *testing.T) *AuthService {
+	t.Helper()
+	config.AppConfig = &config.Config{
+		AuthJWTSecret:      "test-secret",
+		AuthTokenTTL:       3600,
+		AuthNonceTTL:       300,
+		AuthAllowedDomains: testSiweDomain,
+	}
+	db := testdb.Open(t, &models.AuthNonce{})
+	return NewAuthService(repositories.NewAuthRepository(db))
+}
+
+func newTestKey(t *testing.T) (*ecdsa.PrivateKey, common.Address) {
+	t.Helper()
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key, crypto.PubkeyToAddress(key.PublicKey)
+}
+
+// siweMessage 按 EIP-4361 格式拼出登录消息
+func siweMessage(domain string, address common.Address, nonce string, issuedAt, expiresAt time.Time) string {
+	return fmt.Sprintf("%s wants you to sign in with your Ethereum account:\n%s\n\nSign in to Hackathon\n\n"+
+		"URI: https://%s\nVersion: 1\nChain ID: 50312\nNonce: %s\nIssued At: %s\nExpiration Time: %s",
+		domain, address.Hex(), domain, nonce, issuedAt.Format(time.RFC3339), expiresAt.Format(time.RFC3339))
+}
+
+// personalSign 模拟钱包的 personal_sign，v 为 27/28
+func personalSign(t *testing.T, key *ecdsa.PrivateKey, message string) string {
+	t.Helper()
+	sig, err := crypto.Sign(accounts.TextHash([]byte(message)), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig[crypto.RecoveryIDOffset] += 27
+	return hexutil.Encode(sig)
+}
+
+func TestAuthServiceLogin(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name        string
+		domain      string
+		expires     time.Time
+		otherSigner bool
+		wantErr     error
+	}{
+		{name: "valid", domain: testSiweDomain, expires: now.Add(time.Hour)},
+		{name: "disallowed domain", domain: "evil.example", expires: now.Add(time.Hour), wantErr: ErrInvalidSiweMessage},
+		{name: "expired", domain: testSiweDomain, expires: now.Add(-time.Minute), wantErr: ErrInvalidSiweMessage},
+		{name: "signed by another wallet", domain: testSiweDomain, expires: now.Add(time.Hour), otherSigner: true, wantErr: ErrInvalidSignature},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := newTestAuthService(t)
+			key, address := newTestKey(t)
+			nonce, err := service.IssueNonce()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			message := siweMessage(tt.domain, address, nonce.Nonce, now.Add(-time.Minute), tt.expires)
+			signingKey := key
+			if tt.otherSigner {
+				signingKey, _ = newTestKey(t)
+			}
+
+			session, err := service.Login(message, personalSign(t, signingKey, message))
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Login error = %v, want %v", err, tt.wantErr)
+				}
+				// 校验失败不消费 nonce，合法用户仍可使用
+				valid := siweMessage(testSiweDomain, address, nonce.Nonce, now.Add(-time.Minute), now.Add(time.Hour))
+				if _, err := service.Login(valid, personalSign(t, key, valid)); err != nil {
+					t.Fatalf("nonce consumed by rejected login: %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Login: %v", err)
+			}
+
+			claims, err := service.ParseToken(session.Token)
+			if err != nil {
+				t.Fatalf("ParseToken: %v", err)
+			}
+			if claims.Address != address.Hex() || claims.ChainID != 50312 {
+				t.Errorf("claims = %s on %d, want %s on 50312", claims.Address, claims.ChainID, address.Hex())
+			}
+		})
+	}
+}
+
+func TestAuthServiceLoginRejectsReusedNonce(t *testing.T) {
+	service := newTestAuthService(t)
+	key, address := newTestKey(t)
+	nonce, err := service.IssueNonce()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	message := siweMessage(testSiweDomain, address, nonce.Nonce, time.Now().Add(-time.Minute), time.Now().Add(time.Hour))
+	signature := personalSign(t, key, message)
+	if _, err := service.Login(message, signature); err != nil {
+		t.Fatalf("first Login: %v", err)
+	}
+	if _, err := service.Login(message, signature); !errors.Is(err, ErrInvalidNonce) {
+		t.Fatalf("replayed Login error = %v, want %v", err, ErrInvalidNonce)
+	}
+}
+
+func TestAuthServiceParseTokenRejectsTampering(t *testing.T) {
+	service := newTestAuthService(t)
+	_, address := newTestKey(t)
+	_, other := newTestKey(t)
+
+	session, err := service.IssueToken(address, 50312)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 替换载荷中的地址，签名保持不变
+	parts := strings.Split(session.Token, ".")
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload = []byte(strings.ReplaceAll(string(payload), address.Hex(), other.Hex()))
+	parts[1] = base64.RawURLEncoding.EncodeToString(payload)
+	tampered := strings.Join(parts, ".")
+
+	// 其他密钥签发的令牌
+	foreign := &AuthService{secret: []byte("other-secret"), tokenTTL: time.Hour}
+	forged, err := foreign.IssueToken(address, 50312)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, token := range map[string]string{"tampered payload": tampered, "foreign secret": forged.Token} {
+		if _, err := service.ParseToken(token); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("%s: ParseToken error = %v, want %v", name, err, ErrInvalidToken)
+		}
+	}
+}
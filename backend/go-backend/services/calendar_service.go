@@ -0,0 +1,64 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"hackathon-backend/config"
+	"hackathon-backend/models"
+	"hackathon-backend/repositories"
+)
+
+const (
+	// calendarLookback 日历包含最近结束的活动，订阅者仍能看到刚结束或被关闭的活动
+	calendarLookback = 30 * 24 * time.Hour
+	// calendarMaxEvents 单个日历最多包含的活动数
+	calendarMaxEvents = 500
+)
+
+// CalendarService 生成活动的 iCalendar 订阅
+type CalendarService struct {
+	repo *repositories.FeedRepository
+}
+
+func NewCalendarService(repo *repositories.FeedRepository) *CalendarService {
+	return &CalendarService{repo: repo}
+}
+
+// EventsCalendar 即将开始和最近结束的活动，network、organizer 为空时不过滤
+func (s *CalendarService) EventsCalendar(network string, organizer models.Address) (*Calendar, error) {
+	filter := repositories.FeedFilter{Organizer: organizer}
+	name := "HackChain Hackathons"
+	if network != "" {
+		chainID, ok := config.ChainIDForNetwork(network)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownNetwork, network)
+		}
+		filter.ChainID = chainID
+		name += " (" + network + ")"
+	}
+
+	events, err := s.repo.GetEventsEndingAfter(filter, time.Now().Add(-calendarLookback).Unix(), calendarMaxEvents)
+	if err != nil {
+		return nil, err
+	}
+	return &Calendar{Name: name, Events: events}, nil
+}
+
+// EventCalendar 单个活动的日历
+func (s *CalendarService) EventCalendar(event *models.Event) *Calendar {
+	return &Calendar{Name: event.Title, Events: []models.Event{*event}}
+}
+
+// WalletCalendar 钱包在各链上报名的活动
+func (s *CalendarService) WalletCalendar(address string) (*Calendar, error) {
+	wallet, err := models.ParseAddress(address)
+	if err != nil {
+		return nil, err
+	}
+	events, err := s.repo.GetRegisteredEvents(wallet, time.Now().Add(-calendarLookback).Unix(), calendarMaxEvents)
+	if err != nil {
+		return nil, err
+	}
+	return &Calendar{Name: "HackChain " + MaskAddress(wallet.String()), Events: events}, nil
+}
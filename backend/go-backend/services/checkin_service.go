@@ -0,0 +1,289 @@
+package services
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"strings"
+	"time"
+
+	"hackathon-backend/blockchain"
+	"hackathon-backend/config"
+	"hackathon-backend/models"
+	"hackathon-backend/repositories"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// 门票二维码签名方
+const (
+	PassSignerHolder = "holder" // 持有者钱包 personal_sign
+	PassSignerServer = "server" // 服务端密钥签发
+)
+
+const ticketPassVersion = 1
+
+var (
+	ErrInvalidPass    = errors.New("invalid ticket pass")
+	ErrPassExpired    = errors.New("ticket pass expired")
+	ErrPassReplayed   = errors.New("ticket pass already used")
+	ErrTicketNotFound = errors.New("ticket not found")
+	ErrTicketUnusable = errors.New("ticket is not valid for check-in")
+)
+
+// TicketPass 门票二维码内容
+type TicketPass struct {
+	Version   int    `json:"v"`
+	ChainID   uint64 `json:"chain_id"`
+	EventID   string `json:"event_id"`
+	TokenID   string `json:"token_id"`
+	Holder    string `json:"holder"`
+	ExpiresAt int64  `json:"expires_at"`
+	Nonce     string `json:"nonce"`
+	Signer    string `json:"signer"` // holder, server
+	Signature string `json:"signature,omitempty"`
+}
+
+// Message 待签名内容，持有者签名时由钱包对该文本执行 personal_sign
+func (p *TicketPass) Message() string {
+	holder := models.Address(p.Holder).Canonical()
+	return fmt.Sprintf("HackChain Ticket Pass\nChain ID: %d\nEvent ID: %s\nToken ID: %s\nHolder: %s\nNonce: %s\nExpires At: %d",
+		p.ChainID, p.EventID, p.TokenID, holder, p.Nonce, p.ExpiresAt)
+}
+
+// PassCheck 通过签名与门票状态校验的二维码
+type PassCheck struct {
+	Pass        *TicketPass
+	Ticket      *models.NFTTicket
+	Event       *models.Event       // 活动尚未索引时为 nil
+	Participant *models.Participant // 持有者未登记为参与者时为 nil
+}
+
+// PassVerification 核验结果
+type PassVerification struct {
+	Ticket      *models.NFTTicket   `json:"ticket"`
+	Participant *models.Participant `json:"participant"`
+	Signer      string              `json:"signer"`
+	VerifiedAt  time.Time           `json:"verified_at"`
+}
+
+type CheckInService struct {
+	repo          *repositories.CheckInRepository
+	eventRepo     *repositories.EventRepository
+	signerKey     *ecdsa.PrivateKey
+	passTTL       time.Duration
+	verifyOnChain bool
+}
+
+func NewCheckInService(repo *repositories.CheckInRepository, eventRepo *repositories.EventRepository) *CheckInService {
+	cfg := config.AppConfig
+
+	var key *ecdsa.PrivateKey
+	var err error
+	if cfg.CheckInSignerKey != "" {
+		key, err = crypto.HexToECDSA(strings.TrimPrefix(cfg.CheckInSignerKey, "0x"))
+		if err != nil {
+			log.Fatalf("❌ Invalid CHECKIN_SIGNER_KEY: %v", err)
+		}
+	} else {
+		key, err = crypto.GenerateKey()
+		if err != nil {
+			log.Fatalf("❌ Failed to generate check-in signer key: %v", err)
+		}
+		log.Println("⚠️ CHECKIN_SIGNER_KEY not set, using a random key (server-signed passes are invalidated on restart)")
+	}
+	log.Printf("🎟️ Check-in pass signer: %s", crypto.PubkeyToAddress(key.PublicKey).Hex())
+
+	return &CheckInService{
+		repo:          repo,
+		eventRepo:     eventRepo,
+		signerKey:     key,
+		passTTL:       time.Duration(cfg.CheckInPassTTL) * time.Second,
+		verifyOnChain: cfg.CheckInVerifyOnChain,
+	}
+}
+
+// SignerAddress 服务端签名地址
+func (s *CheckInService) SignerAddress() common.Address {
+	return crypto.PubkeyToAddress(s.signerKey.PublicKey)
+}
+
+// IssuePass 为当前持有者签发门票二维码；signer 为 server 时由服务端签名，为 holder 时返回待持有者签名的内容
+func (s *CheckInService) IssuePass(session *SessionClaims, tokenID string, signer string) (*TicketPass, error) {
+	if signer != PassSignerServer && signer != PassSignerHolder {
+		return nil, fmt.Errorf("%w: signer must be %q or %q", ErrInvalidPass, PassSignerServer, PassSignerHolder)
+	}
+
+	ticket, err := s.eventRepo.FindTicketOnChain(config.AppConfig.GetActiveChainID(), tokenID)
+	if err != nil {
+		return nil, err
+	}
+	if ticket == nil {
+		return nil, ErrTicketNotFound
+	}
+	if !ticket.Holder.Equal(session.Wallet()) {
+		return nil, ErrForbidden
+	}
+	if ticket.Used {
+		return nil, ErrTicketUnusable
+	}
+
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	pass := &TicketPass{
+		Version:   ticketPassVersion,
+		ChainID:   ticket.ChainID,
+		EventID:   ticket.EventID,
+		TokenID:   ticket.TokenID,
+		Holder:    ticket.Holder.Canonical().String(),
+		ExpiresAt: time.Now().Add(s.passTTL).Unix(),
+		Nonce:     hex.EncodeToString(nonce),
+		Signer:    signer,
+	}
+	if signer == PassSignerServer {
+		sig, err := crypto.Sign(accounts.TextHash([]byte(pass.Message())), s.signerKey)
+		if err != nil {
+			return nil, err
+		}
+		sig[crypto.RecoveryIDOffset] += 27
+		pass.Signature = hexutil.Encode(sig)
+	}
+	return pass, nil
+}
+
+// CheckPass 校验二维码签名、有效期，以及数据库中门票的持有者和使用状态；
+// onChain 为 true（或配置 CHECKIN_VERIFY_ONCHAIN）时再调用合约 isTicketValid 复核
+func (s *CheckInService) CheckPass(ctx context.Context, pass *TicketPass, onChain bool) (*PassCheck, error) {
+	if pass.Version != ticketPassVersion || pass.ChainID == 0 || pass.EventID == "" || pass.TokenID == "" ||
+		!common.IsHexAddress(pass.Holder) || len(pass.Nonce) < 16 || pass.Signature == "" {
+		return nil, fmt.Errorf("%w: missing or malformed fields", ErrInvalidPass)
+	}
+	if time.Now().Unix() > pass.ExpiresAt {
+		return nil, ErrPassExpired
+	}
+
+	var expected common.Address
+	switch pass.Signer {
+	case PassSignerHolder:
+		expected = common.HexToAddress(pass.Holder)
+	case PassSignerServer:
+		expected = s.SignerAddress()
+	default:
+		return nil, fmt.Errorf("%w: unknown signer %q", ErrInvalidPass, pass.Signer)
+	}
+	recovered, err := RecoverPersonalSigner(pass.Message(), pass.Signature)
+	if err != nil || recovered != expected {
+		return nil, fmt.Errorf("%w: signature mismatch", ErrInvalidPass)
+	}
+
+	ticket, err := s.eventRepo.FindTicketOnChain(pass.ChainID, pass.TokenID)
+	if err != nil {
+		return nil, err
+	}
+	if ticket == nil {
+		return nil, ErrTicketNotFound
+	}
+	if ticket.EventID != pass.EventID {
+		return nil, fmt.Errorf("%w: ticket belongs to event %s", ErrInvalidPass, ticket.EventID)
+	}
+	// 二维码签发后门票被转让则失效
+	if !ticket.Holder.Equal(models.Address(pass.Holder)) {
+		return nil, fmt.Errorf("%w: ticket is no longer held by %s", ErrInvalidPass, pass.Holder)
+	}
+	if ticket.Used {
+		return nil, fmt.Errorf("%w: ticket already used", ErrTicketUnusable)
+	}
+
+	used, err := s.repo.FindPassUse(pass.ChainID, pass.TokenID)
+	if err != nil {
+		return nil, err
+	}
+	if used != nil {
+		return nil, fmt.Errorf("%w: ticket already verified at %s", ErrPassReplayed, used.CreatedAt.Format(time.RFC3339))
+	}
+
+	if (onChain || s.verifyOnChain) && pass.ChainID == config.AppConfig.GetActiveChainID() {
+		if err := s.checkOnChain(ctx, pass.TokenID); err != nil {
+			return nil, err
+		}
+	}
+
+	event, err := s.eventRepo.FindEventOnChain(pass.ChainID, pass.EventID)
+	if err != nil {
+		return nil, err
+	}
+	participant, err := s.eventRepo.FindParticipant(pass.ChainID, pass.EventID, ticket.Holder)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PassCheck{Pass: pass, Ticket: ticket, Event: event, Participant: participant}, nil
+}
+
+// checkOnChain 调用合约 isTicketValid
+func (s *CheckInService) checkOnChain(ctx context.Context, tokenID string) error {
+	id, ok := new(big.Int).SetString(tokenID, 10)
+	if !ok {
+		return fmt.Errorf("%w: malformed token id", ErrInvalidPass)
+	}
+
+	bc := s.getBlockchainClient()
+	if bc == nil {
+		return fmt.Errorf("blockchain client not initialized")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	valid, err := bc.IsTicketValid(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to check ticket on chain: %w", err)
+	}
+	if !valid {
+		return fmt.Errorf("%w: isTicketValid returned false", ErrTicketUnusable)
+	}
+	return nil
+}
+
+// RedeemPass 记录核验结果，同一 nonce 或同一门票只能核验一次
+func (s *CheckInService) RedeemPass(check *PassCheck, verifiedBy string) (*PassVerification, error) {
+	use := &models.TicketPassUse{
+		Nonce:      check.Pass.Nonce,
+		ChainID:    check.Pass.ChainID,
+		TokenID:    check.Pass.TokenID,
+		EventID:    check.Pass.EventID,
+		Holder:     check.Ticket.Holder,
+		SignerType: check.Pass.Signer,
+		VerifiedBy: verifiedBy,
+	}
+	inserted, err := s.repo.CreatePassUse(use)
+	if err != nil {
+		return nil, err
+	}
+	if !inserted {
+		return nil, ErrPassReplayed
+	}
+
+	log.Printf("🎟️ Verified ticket %s for event %s (holder %s, by %s)", use.TokenID, use.EventID, use.Holder, verifiedBy)
+	return &PassVerification{
+		Ticket:      check.Ticket,
+		Participant: check.Participant,
+		Signer:      check.Pass.Signer,
+		VerifiedAt:  use.CreatedAt,
+	}, nil
+}
+
+// getBlockchainClient 获取区块链客户端
+func (s *CheckInService) getBlockchainClient() *blockchain.BlockchainClient {
+	return blockchain.GetInstance()
+}
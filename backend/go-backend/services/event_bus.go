@@ -0,0 +1,244 @@
+package services
+
+import (
+	"log"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// 领域事件类型
+const (
+	DomainEventCreated          = "event.created"
+	DomainEventClosed           = "event.closed"
+	DomainParticipantRegistered = "participant.registered"
+	DomainParticipantCheckedIn  = "participant.checked_in"
+	DomainSponsorAdded          = "sponsor.added"
+	DomainTicketIssued          = "ticket.issued"
+	DomainTicketUsed            = "ticket.used"
+	DomainTicketTransferred     = "ticket.transferred"
+)
+
+const (
+	eventBusHistorySize     = 256 // 保留的最近事件数
+	eventBusSubscriberQueue = 64  // 每个订阅者的缓冲队列长度
+)
+
+// TopicAll 订阅所有领域事件
+const TopicAll = "all"
+
+// EventTopic 某个活动的主题
+func EventTopic(eventID string) string {
+	return "event:" + eventID
+}
+
+// WalletTopic 某个钱包的主题，地址不区分大小写
+func WalletTopic(wallet string) string {
+	return "wallet:" + strings.ToLower(wallet)
+}
+
+// DomainEvent 链上日志写入数据库后发布的领域事件
+type DomainEvent struct {
+	ID          uint64      `json:"id,omitempty"` // 进程内递增序号，用于 SSE Last-Event-ID 续传（发布到总线时分配）
+	Type        string      `json:"type"`
+	ChainID     uint64      `json:"chain_id"`
+	Network     string      `json:"network"`
+	EventID     string      `json:"event_id,omitempty"`
+	Wallets     []string    `json:"wallets,omitempty"` // 相关钱包（参与者、赞助商、门票持有者等）
+	TokenID     string      `json:"token_id,omitempty"`
+	BlockNumber uint64      `json:"block_number"`
+	TxHash      string      `json:"tx_hash"`
+	LogIndex    uint        `json:"log_index"`
+	Data        interface{} `json:"data,omitempty"`
+	OccurredAt  time.Time   `json:"occurred_at"`
+}
+
+// Topics 事件所属的主题
+func (e *DomainEvent) Topics() []string {
+	topics := []string{TopicAll}
+	if e.EventID != "" {
+		topics = append(topics, EventTopic(e.EventID))
+	}
+	for _, wallet := range e.Wallets {
+		topics = append(topics, WalletTopic(wallet))
+	}
+	return topics
+}
+
+// Subscription 事件总线上的一个订阅者
+type Subscription struct {
+	bus     *EventBus
+	ch      chan *DomainEvent
+	mu      sync.RWMutex
+	topics  map[string]bool
+	dropped atomic.Uint64
+	once    sync.Once
+}
+
+// Events 接收事件的通道，取消订阅后关闭
+func (sub *Subscription) Events() <-chan *DomainEvent {
+	return sub.ch
+}
+
+// Dropped 因消费过慢而丢弃的事件数
+func (sub *Subscription) Dropped() uint64 {
+	return sub.dropped.Load()
+}
+
+// SetTopics 替换订阅的主题，为空时不再接收任何事件
+func (sub *Subscription) SetTopics(topics []string) {
+	set := make(map[string]bool, len(topics))
+	for _, topic := range topics {
+		set[topic] = true
+	}
+
+	sub.mu.Lock()
+	sub.topics = set
+	sub.mu.Unlock()
+}
+
+// Topics 当前订阅的主题
+func (sub *Subscription) Topics() []string {
+	sub.mu.RLock()
+	defer sub.mu.RUnlock()
+
+	topics := make([]string, 0, len(sub.topics))
+	for topic := range sub.topics {
+		topics = append(topics, topic)
+	}
+	return topics
+}
+
+// matches 事件是否属于订阅的任一主题
+func (sub *Subscription) matches(evt *DomainEvent) bool {
+	sub.mu.RLock()
+	defer sub.mu.RUnlock()
+
+	for _, topic := range evt.Topics() {
+		if sub.topics[topic] {
+			return true
+		}
+	}
+	return false
+}
+
+// Close 取消订阅
+func (sub *Subscription) Close() {
+	sub.once.Do(func() {
+		sub.bus.unsubscribe(sub)
+		close(sub.ch)
+	})
+}
+
+// EventBus 进程内领域事件总线，按主题分发给 SSE / WebSocket 等订阅者
+type EventBus struct {
+	mu          sync.RWMutex
+	nextID      uint64
+	subscribers map[*Subscription]struct{}
+	history     []*DomainEvent // 最近的事件，用于断线重连后补发
+	historySize int
+	queueSize   int
+}
+
+// NewEventBus 创建事件总线
+func NewEventBus() *EventBus {
+	return &EventBus{
+		subscribers: map[*Subscription]struct{}{},
+		historySize: eventBusHistorySize,
+		queueSize:   eventBusSubscriberQueue,
+	}
+}
+
+// Subscribe 订阅指定主题，topics 为空时订阅所有事件
+func (b *EventBus) Subscribe(topics []string) *Subscription {
+	if len(topics) == 0 {
+		topics = []string{TopicAll}
+	}
+
+	sub := &Subscription{
+		bus: b,
+		ch:  make(chan *DomainEvent, b.queueSize),
+	}
+	sub.SetTopics(topics)
+
+	b.mu.Lock()
+	b.subscribers[sub] = struct{}{}
+	b.mu.Unlock()
+
+	return sub
+}
+
+// SubscribeSince 订阅并补发序号大于 lastID 的历史事件（仅限仍在历史缓冲区中的事件）
+func (b *EventBus) SubscribeSince(topics []string, lastID uint64) (*Subscription, []*DomainEvent) {
+	sub := b.Subscribe(topics)
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	var missed []*DomainEvent
+	for _, evt := range b.history {
+		if evt.ID > lastID && sub.matches(evt) {
+			missed = append(missed, evt)
+		}
+	}
+	return sub, missed
+}
+
+func (b *EventBus) unsubscribe(sub *Subscription) {
+	b.mu.Lock()
+	delete(b.subscribers, sub)
+	b.mu.Unlock()
+}
+
+// Publish 发布事件，订阅者队列已满时丢弃该订阅者的这条事件而不阻塞同步流程
+func (b *EventBus) Publish(evt *DomainEvent) {
+	if evt.OccurredAt.IsZero() {
+		evt.OccurredAt = time.Now()
+	}
+
+	b.mu.Lock()
+	b.nextID++
+	evt.ID = b.nextID
+	b.history = append(b.history, evt)
+	if len(b.history) > b.historySize {
+		b.history = b.history[len(b.history)-b.historySize:]
+	}
+	subscribers := make([]*Subscription, 0, len(b.subscribers))
+	for sub := range b.subscribers {
+		subscribers = append(subscribers, sub)
+	}
+	b.mu.Unlock()
+
+	for _, sub := range subscribers {
+		if !sub.matches(evt) {
+			continue
+		}
+		b.deliver(sub, evt)
+	}
+}
+
+// deliver 非阻塞投递，持有读锁以免与 Close 关闭通道并发
+func (b *EventBus) deliver(sub *Subscription, evt *DomainEvent) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if _, ok := b.subscribers[sub]; !ok {
+		return
+	}
+
+	select {
+	case sub.ch <- evt:
+	default:
+		if sub.dropped.Add(1) == 1 {
+			log.Printf("⚠️ Event bus subscriber is too slow, dropping events (first dropped: #%d %s)", evt.ID, evt.Type)
+		}
+	}
+}
+
+// SubscriberCount 当前订阅者数量
+func (b *EventBus) SubscriberCount() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.subscribers)
+}
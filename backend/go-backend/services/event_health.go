@@ -0,0 +1,184 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"hackathon-backend/config"
+)
+
+// HealthCheck 单项健康检查结果
+type HealthCheck struct {
+	OK     bool        `json:"ok"`
+	Detail string      `json:"detail,omitempty"`
+	Data   interface{} `json:"data,omitempty"`
+}
+
+// ReadinessReport readiness 检查报告
+type ReadinessReport struct {
+	Ready  bool                   `json:"ready"`
+	Checks map[string]HealthCheck `json:"checks"`
+}
+
+// SubscriptionHealth 事件摄取状态
+type SubscriptionHealth struct {
+	Mode       string     `json:"mode"`
+	ActiveMode string     `json:"active_mode"`
+	Subscribed bool       `json:"subscribed"`
+	LastLogAt  *time.Time `json:"last_log_at"`
+	LastPollAt *time.Time `json:"last_poll_at"`
+	LastError  string     `json:"last_error,omitempty"`
+}
+
+// NetworkLag 某个网络某个合约的索引延迟
+type NetworkLag struct {
+	Network         string `json:"network"`
+	ChainID         uint64 `json:"chain_id"`
+	ContractAddress string `json:"contract_address"`
+	HeadBlock       uint64 `json:"head_block"`
+	LastBlock       uint64 `json:"last_block"`
+	Lag             uint64 `json:"lag"`
+}
+
+// CheckReadiness 检查数据库、RPC、订阅状态和索引延迟
+func (s *EventService) CheckReadiness(ctx context.Context) *ReadinessReport {
+	cfg := config.AppConfig
+	timeout := time.Duration(cfg.HealthCheckTimeout) * time.Second
+	if timeout <= 0 {
+		timeout = 3 * time.Second
+	}
+
+	report := &ReadinessReport{Ready: true, Checks: map[string]HealthCheck{}}
+	add := func(name string, check HealthCheck) {
+		report.Checks[name] = check
+		if !check.OK {
+			report.Ready = false
+		}
+	}
+
+	add("database", s.checkDatabase(ctx, timeout))
+	add("rpc", s.checkRPC(ctx, timeout))
+	add("subscription", s.checkSubscription())
+	add("indexing_lag", s.checkIndexingLag(ctx, timeout, uint64(cfg.HealthMaxLagBlocks)))
+
+	return report
+}
+
+// checkDatabase 探测数据库连接
+func (s *EventService) checkDatabase(ctx context.Context, timeout time.Duration) HealthCheck {
+	sqlDB, err := s.repo.GetDB().DB()
+	if err != nil {
+		return HealthCheck{OK: false, Detail: err.Error()}
+	}
+
+	pingCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if err := sqlDB.PingContext(pingCtx); err != nil {
+		return HealthCheck{OK: false, Detail: err.Error()}
+	}
+	return HealthCheck{OK: true}
+}
+
+// checkRPC 探测 HTTP RPC 是否可达
+func (s *EventService) checkRPC(ctx context.Context, timeout time.Duration) HealthCheck {
+	bc := s.getBlockchainClient()
+	if bc == nil {
+		return HealthCheck{OK: false, Detail: "blockchain client not initialized"}
+	}
+
+	rpcCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	head, err := bc.GetLatestBlockNumber(rpcCtx)
+	if err != nil {
+		return HealthCheck{OK: false, Detail: err.Error()}
+	}
+	return HealthCheck{OK: true, Data: map[string]interface{}{"head_block": head}}
+}
+
+// checkSubscription 检查事件摄取是否在运行，以及是否长时间未收到日志
+func (s *EventService) checkSubscription() HealthCheck {
+	s.mu.Lock()
+	state := SubscriptionHealth{
+		Mode:       s.syncMode,
+		ActiveMode: s.activeMode,
+		Subscribed: s.subscribed,
+		LastError:  s.lastSyncErr,
+	}
+	if !s.lastLogAt.IsZero() {
+		lastLogAt := s.lastLogAt
+		state.LastLogAt = &lastLogAt
+	}
+	if !s.lastPollAt.IsZero() {
+		lastPollAt := s.lastPollAt
+		state.LastPollAt = &lastPollAt
+	}
+	pollInterval := s.pollInterval
+	s.mu.Unlock()
+
+	check := HealthCheck{OK: true, Data: state}
+
+	switch state.ActiveMode {
+	case SyncModeWebSocket:
+		if !state.Subscribed {
+			check.OK = false
+			check.Detail = "websocket subscription is not established"
+		}
+	case SyncModePolling:
+		// 连续多个轮询周期没有成功视为停滞
+		if state.LastPollAt == nil || time.Since(*state.LastPollAt) > 3*pollInterval+syncRetryDelay {
+			check.OK = false
+			check.Detail = "polling has not succeeded recently"
+		}
+	default:
+		check.OK = false
+		check.Detail = "event ingestion has not started"
+	}
+
+	if maxSilence := config.AppConfig.HealthMaxLogSilence; check.OK && maxSilence > 0 {
+		if state.LastLogAt == nil || time.Since(*state.LastLogAt) > time.Duration(maxSilence)*time.Second {
+			check.OK = false
+			check.Detail = fmt.Sprintf("no log received in the last %d seconds", maxSilence)
+		}
+	}
+
+	return check
+}
+
+// checkIndexingLag 检查当前网络各合约检查点与链上最新区块的差值
+func (s *EventService) checkIndexingLag(ctx context.Context, timeout time.Duration, maxLag uint64) HealthCheck {
+	lagCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	checkpoints, err := s.GetIndexingLag(lagCtx)
+	if err != nil {
+		return HealthCheck{OK: false, Detail: err.Error()}
+	}
+
+	check := HealthCheck{OK: true}
+	lags := make([]NetworkLag, 0, len(checkpoints))
+	for _, cp := range checkpoints {
+		// 只有当前活动网络能获取最新区块，其他网络不参与判断
+		if cp.Lag == nil {
+			continue
+		}
+
+		lags = append(lags, NetworkLag{
+			Network:         cp.Network,
+			ChainID:         cp.ChainID,
+			ContractAddress: cp.ContractAddress,
+			HeadBlock:       *cp.HeadBlock,
+			LastBlock:       cp.LastBlock,
+			Lag:             *cp.Lag,
+		})
+		if *cp.Lag > maxLag {
+			check.OK = false
+			check.Detail = fmt.Sprintf("%s %s lags %d blocks behind head (max %d)", cp.Network, cp.ContractAddress, *cp.Lag, maxLag)
+		}
+	}
+	check.Data = lags
+
+	return check
+}
@@ -0,0 +1,146 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"time"
+
+	"hackathon-backend/config"
+	"hackathon-backend/models"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidEventID  = errors.New("invalid event ID")
+	ErrEventNotOnChain = errors.New("event not found on chain")
+)
+
+// ReindexResult 重新索引结果
+type ReindexResult struct {
+	Event        *models.Event `json:"event"`
+	Participants int           `json:"participants"`
+	Sponsors     int           `json:"sponsors"`
+}
+
+// ReindexEvent 从当前活动网络的合约重新读取活动、参与者和赞助商，并覆盖数据库中的记录
+func (s *EventService) ReindexEvent(ctx context.Context, eventID string) (*ReindexResult, error) {
+	id, ok := new(big.Int).SetString(eventID, 10)
+	if !ok || id.Sign() <= 0 {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidEventID, eventID)
+	}
+
+	bc := s.getBlockchainClient()
+	if bc == nil {
+		return nil, fmt.Errorf("blockchain client not initialized")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
+	defer cancel()
+
+	details, err := bc.GetEventDetails(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get event details: %w", err)
+	}
+	if details.Id == nil || details.Id.Sign() == 0 {
+		return nil, ErrEventNotOnChain
+	}
+	contractParticipants, err := bc.GetEventParticipants(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get participants: %w", err)
+	}
+	contractSponsors, err := bc.GetEventSponsors(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sponsors: %w", err)
+	}
+
+	chainID := config.AppConfig.GetActiveChainID()
+	network := config.AppConfig.GetActiveNetworkName()
+	contractAddress := bc.GetHackathonAddress().Hex()
+
+	event := &models.Event{
+		ChainID:          chainID,
+		Network:          network,
+		ContractAddress:  contractAddress,
+		EventID:          details.Id.String(),
+		Organizer:        models.AddressFrom(details.Organizer),
+		Title:            details.Title,
+		Description:      details.Description,
+		StartTime:        details.StartTime.Int64(),
+		EndTime:          details.EndTime.Int64(),
+		Location:         details.Location,
+		MaxParticipants:  details.MaxParticipants.Uint64(),
+		ParticipantCount: details.ParticipantCount.Uint64(),
+		Active:           details.Active,
+		CreatedAt:        time.Unix(details.CreatedAt.Int64(), 0),
+		SyncedAt:         time.Now(),
+	}
+
+	sponsors := make([]models.Sponsor, 0, len(contractSponsors))
+	for _, sp := range contractSponsors {
+		sponsors = append(sponsors, models.Sponsor{
+			ChainID:         chainID,
+			Network:         network,
+			ContractAddress: contractAddress,
+			EventID:         event.EventID,
+			Wallet:          models.AddressFrom(sp.Wallet),
+			Name:            sp.Name,
+			Amount:          sp.Amount.String(),
+			SponsoredAt:     sp.SponsoredAt.Int64(),
+		})
+	}
+
+	err = s.repo.GetDB().Transaction(func(tx *gorm.DB) error {
+		repo := s.repo.WithTx(tx)
+
+		existing, err := repo.FindEvent(chainID, contractAddress, event.EventID)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			event.ID = existing.ID
+			if err := repo.UpdateEvent(event); err != nil {
+				return err
+			}
+		} else if err := repo.CreateEvent(event); err != nil {
+			return err
+		}
+
+		for _, p := range contractParticipants {
+			if err := repo.UpsertParticipant(&models.Participant{
+				ChainID:         chainID,
+				Network:         network,
+				ContractAddress: contractAddress,
+				EventID:         event.EventID,
+				Wallet:          models.AddressFrom(p.Wallet),
+				Name:            p.Name,
+				RegisteredAt:    p.RegisteredAt.Int64(),
+				CheckedIn:       p.CheckedIn,
+				CheckInTime:     p.CheckInTime.Int64(),
+			}); err != nil {
+				return err
+			}
+		}
+
+		return repo.ReplaceSponsors(chainID, contractAddress, event.EventID, sponsors)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save reindexed event: %w", err)
+	}
+
+	log.Printf("🔁 Reindexed event %s: %d participants, %d sponsors", event.EventID, len(contractParticipants), len(sponsors))
+	return &ReindexResult{Event: event, Participants: len(contractParticipants), Sponsors: len(sponsors)}, nil
+}
+
+// FindEventOnActiveChain 在当前活动网络中查找活动，不存在时返回 nil
+func (s *EventService) FindEventOnActiveChain(eventID string) (*models.Event, error) {
+	return s.FindEventOnChain(config.AppConfig.GetActiveChainID(), eventID)
+}
+
+// FindEventOnChain 在指定链上查找活动，不存在时返回 nil
+func (s *EventService) FindEventOnChain(chainID uint64, eventID string) (*models.Event, error) {
+	return s.repo.FindEventOnChain(chainID, eventID)
+}
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"hackathon-backend/repositories"
+)
+
+// snippetRadius 描述摘要在命中位置前后保留的字符数
+const snippetRadius = 80
+
+// EventSearchQuery 活动检索参数
+type EventSearchQuery struct {
+	Keyword  string // 全文检索关键词（标题、描述、地点）
+	Location string // 城市/地点模糊匹配
+	Filter   repositories.EventFilter
+	Limit    int
+	Offset   int
+}
+
+// EventSearchResult 单条检索结果，Highlights 为 HTML 转义后用 <mark> 标记命中词的片段
+type EventSearchResult struct {
+	repositories.EventSearchHit
+	Highlights map[string]string `json:"highlights,omitempty"`
+}
+
+// SearchEvents 全文检索活动，按相关度排序并生成高亮片段
+func (s *EventService) SearchEvents(query EventSearchQuery) ([]EventSearchResult, int64, error) {
+	hits, total, err := s.repo.SearchEvents(query.Keyword, query.Location, query.Filter, query.Limit, query.Offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	matcher := highlightMatcher(query.Keyword, query.Location)
+	results := make([]EventSearchResult, 0, len(hits))
+	for _, hit := range hits {
+		result := EventSearchResult{EventSearchHit: hit}
+		if matcher != nil {
+			result.Highlights = map[string]string{}
+			if v, ok := highlight(matcher, hit.Title, 0); ok {
+				result.Highlights["title"] = v
+			}
+			if v, ok := highlight(matcher, hit.Description, snippetRadius); ok {
+				result.Highlights["description"] = v
+			}
+			if v, ok := highlight(matcher, hit.Location, 0); ok {
+				result.Highlights["location"] = v
+			}
+		}
+		results = append(results, result)
+	}
+
+	return results, total, nil
+}
+
+// highlightMatcher 根据关键词构造不区分大小写的匹配正则，没有关键词时返回 nil
+func highlightMatcher(keywords ...string) *regexp.Regexp {
+	var terms []string
+	for _, keyword := range keywords {
+		for _, term := range strings.Fields(keyword) {
+			terms = append(terms, regexp.QuoteMeta(term))
+		}
+	}
+	if len(terms) == 0 {
+		return nil
+	}
+	return regexp.MustCompile("(?i)" + strings.Join(terms, "|"))
+}
+
+// highlight 返回用 <mark> 包裹命中词的 HTML 片段；radius > 0 时只截取首个命中位置附近的内容
+func highlight(matcher *regexp.Regexp, text string, radius int) (string, bool) {
+	loc := matcher.FindStringIndex(text)
+	if loc == nil {
+		return "", false
+	}
+
+	prefix, suffix := "", ""
+	if radius > 0 {
+		start, end := loc[0], loc[1]
+		for i := 0; i < radius && start > 0; i++ {
+			_, size := utf8.DecodeLastRuneInString(text[:start])
+			start -= size
+		}
+		for i := 0; i < radius && end < len(text); i++ {
+			_, size := utf8.DecodeRuneInString(text[end:])
+			end += size
+		}
+		if start > 0 {
+			prefix = "…"
+		}
+		if end < len(text) {
+			suffix = "…"
+		}
+		text = text[start:end]
+	}
+
+	var b strings.Builder
+	b.WriteString(prefix)
+	last := 0
+	for _, m := range matcher.FindAllStringIndex(text, -1) {
+		b.WriteString(html.EscapeString(text[last:m[0]]))
+		b.WriteString("<mark>")
+		b.WriteString(html.EscapeString(text[m[0]:m[1]]))
+		b.WriteString("</mark>")
+		last = m[1]
+	}
+	b.WriteString(html.EscapeString(text[last:]))
+	b.WriteString(suffix)
+
+	return b.String(), true
+}
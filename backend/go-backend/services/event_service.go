@@ -2,9 +2,11 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"math/big"
+	"sync"
 	"time"
 
 	"hackathon-backend/blockchain"
@@ -15,14 +17,55 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
+	"gorm.io/gorm"
 )
 
+// LogOutbox 发件箱：在日志写入的同一事务中为领域事件追加需要可靠投递的记录（如 Webhook），提交后再唤醒投递
+type LogOutbox interface {
+	EnqueueTx(tx *gorm.DB, evt *DomainEvent) error
+	Notify()
+}
+
 type EventService struct {
-	repo *repositories.EventRepository
+	repo   *repositories.EventRepository
+	bus    *EventBus
+	outbox LogOutbox
+
+	// 同步状态
+	mu                       sync.Mutex
+	syncMode                 string
+	activeMode               string
+	pollInterval             time.Duration
+	lastBlock                uint64
+	subscriptionsUnsupported bool
+	cancelRun                context.CancelFunc
+
+	// 健康状态
+	subscribed  bool      // WebSocket 订阅是否已建立
+	lastLogAt   time.Time // 最后一次收到日志的时间
+	lastPollAt  time.Time // 最后一次成功轮询的时间
+	lastSyncErr string    // 最近一次同步错误
 }
 
-func NewEventService(repo *repositories.EventRepository) *EventService {
-	return &EventService{repo: repo}
+func NewEventService(repo *repositories.EventRepository, bus *EventBus, outbox LogOutbox) *EventService {
+	syncMode := config.AppConfig.SyncMode
+	if !IsValidSyncMode(syncMode) {
+		log.Printf("⚠️ Unknown sync mode %q, using %s", syncMode, SyncModeAuto)
+		syncMode = SyncModeAuto
+	}
+
+	pollInterval := config.AppConfig.PollInterval
+	if pollInterval <= 0 {
+		pollInterval = 5
+	}
+
+	return &EventService{
+		repo:         repo,
+		bus:          bus,
+		outbox:       outbox,
+		syncMode:     syncMode,
+		pollInterval: time.Duration(pollInterval) * time.Second,
+	}
 }
 
 // GetRepository 获取 repository 实例
@@ -56,9 +99,29 @@ func (s *EventService) SubscribeEvents(ctx context.Context) error {
 
 	logs, sub, err := bc.SubscribeToLogs(ctx, addresses)
 	if err != nil {
-		log.Printf("⚠️ WebSocket subscription not supported: %v", err)
-		log.Println("🔄 Falling back to polling mode...")
-		return fmt.Errorf("websocket not supported, use polling instead")
+		return err
+	}
+	defer sub.Unsubscribe()
+
+	s.setSubscribed(true)
+	defer s.setSubscribed(false)
+
+	// 补齐检查点到订阅建立之间遗漏的日志（与订阅重复的日志由 processed_logs 去重），首次启动时从最新区块开始；
+	// 补齐失败时不能继续推进检查点，交由 RunSync 重试
+	lastBlock, err := s.loadCheckpointBlock()
+	if err != nil {
+		return fmt.Errorf("failed to load sync checkpoint: %w", err)
+	}
+	nextBlock := lastBlock
+	if lastBlock == 0 {
+		latest, err := bc.GetLatestBlockNumber(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get latest block: %w", err)
+		}
+		nextBlock = latest + 1
+		s.advanceContractCheckpoints(ctx, bc, latest)
+	} else if nextBlock, err = s.pollOnce(ctx, bc, lastBlock); err != nil {
+		return fmt.Errorf("failed to backfill logs since block %d: %w", lastBlock, err)
 	}
 
 	log.Println("✅ Listening for events...")
@@ -67,6 +130,11 @@ func (s *EventService) SubscribeEvents(ctx context.Context) error {
 	heartbeat := time.NewTicker(30 * time.Second)
 	defer heartbeat.Stop()
 
+	// 订阅送达的日志可能滞后或仍在缓冲中，不能据此推进检查点：定期用 eth_getLogs 核对到最新区块，
+	// 核对过的区块范围才推进检查点（与订阅重复的日志由 processed_logs 去重）
+	checkpointTicker := time.NewTicker(s.getPollInterval())
+	defer checkpointTicker.Stop()
+
 	for {
 		select {
 		case err := <-sub.Err():
@@ -74,7 +142,16 @@ func (s *EventService) SubscribeEvents(ctx context.Context) error {
 			return err
 		case vLog := <-logs:
 			log.Printf("📥 Received log from address: %s", vLog.Address.Hex())
-			s.processLog(vLog)
+			// 订阅无法重新获取失败的日志：停止推进检查点并重启，重启后从检查点补齐
+			if err := s.processLog(vLog); err != nil {
+				return err
+			}
+		case <-checkpointTicker.C:
+			next, err := s.pollOnce(ctx, bc, nextBlock)
+			if err != nil {
+				return fmt.Errorf("failed to reconcile logs since block %d: %w", nextBlock, err)
+			}
+			nextBlock = next
 		case <-heartbeat.C:
 			log.Println("💓 Event listener heartbeat - still listening...")
 		case <-ctx.Done():
@@ -83,9 +160,33 @@ func (s *EventService) SubscribeEvents(ctx context.Context) error {
 	}
 }
 
-// processLog 处理接收到的日志
-func (s *EventService) processLog(vLog types.Log) {
+// errLogAlreadyProcessed 日志已经处理过（processed_logs 中已有记录）
+var errLogAlreadyProcessed = errors.New("log already processed")
+
+// errIndexedRecordMissing 日志要更新的记录不在数据库中（如数据库从较新的区块开始同步，没有索引到对应的活动、
+// 参与者或门票），重试也不会成功：该日志记为跳过并推进检查点，不阻塞后续同步
+var errIndexedRecordMissing = errors.New("indexed record not found")
+
+// logApply 在数据库事务中写入日志对应的数据，返回成功描述和提交后要发布的领域事件
+type logApply func(repo *repositories.EventRepository) (string, *DomainEvent, error)
+
+// logHandler 解析日志并从链上读取详细数据，返回需要在事务中执行的写入
+type logHandler func(vLog types.Log) (logApply, error)
+
+// processLog 处理接收到的日志，读取链上数据或写入失败时返回错误，调用方不得将检查点推进到该日志之后
+func (s *EventService) processLog(vLog types.Log) error {
 	log.Printf("📥 Received log: Block: %d, Tx: %s", vLog.BlockNumber, vLog.TxHash.Hex())
+	s.markLogReceived()
+
+	if len(vLog.Topics) == 0 {
+		log.Printf("⚠️ Ignoring anonymous log in tx %s", vLog.TxHash.Hex())
+		return nil
+	}
+
+	if vLog.Removed {
+		log.Printf("⚠️ Ignoring removed log in tx %s (chain reorg)", vLog.TxHash.Hex())
+		return nil
+	}
 
 	// 记录同步日志
 	s.CreateSyncLog("event_subscription", vLog.BlockNumber, vLog.TxHash.Hex(), "received", "")
@@ -97,67 +198,222 @@ func (s *EventService) processLog(vLog types.Log) {
 	sponsorAddedSig := crypto.Keccak256Hash([]byte("SponsorAdded(uint256,address,uint256)"))
 	ticketIssuedSig := crypto.Keccak256Hash([]byte("TicketIssued(uint256,address,uint256)"))
 	ticketUsedSig := crypto.Keccak256Hash([]byte("TicketUsed(uint256)"))
+	eventClosedSig := crypto.Keccak256Hash([]byte("EventClosed(uint256)"))
+	ticketTransferredSig := crypto.Keccak256Hash([]byte("TicketTransferred(uint256,address,address)"))
 
 	// 根据事件类型处理
+	var eventType string
+	var handle logHandler
 	switch vLog.Topics[0] {
 	case eventCreatedSig:
-		s.handleEventCreated(vLog)
+		eventType, handle = "event_created", s.handleEventCreated
 	case participantRegisteredSig:
-		s.handleParticipantRegistered(vLog)
+		eventType, handle = "participant_registered", s.handleParticipantRegistered
 	case participantCheckedInSig:
-		s.handleParticipantCheckedIn(vLog)
+		eventType, handle = "participant_checked_in", s.handleParticipantCheckedIn
 	case sponsorAddedSig:
-		s.handleSponsorAdded(vLog)
+		eventType, handle = "sponsor_added", s.handleSponsorAdded
 	case ticketIssuedSig:
-		s.handleTicketIssued(vLog)
+		eventType, handle = "ticket_issued", s.handleTicketIssued
 	case ticketUsedSig:
-		s.handleTicketUsed(vLog)
+		eventType, handle = "ticket_used", s.handleTicketUsed
+	case eventClosedSig:
+		eventType, handle = "event_closed", s.handleEventClosed
+	case ticketTransferredSig:
+		eventType, handle = "ticket_transferred", s.handleTicketTransferred
 	default:
 		log.Printf("⚠️ Unknown event: %s", vLog.Topics[0].Hex())
+		return nil
 	}
+
+	// 已处理过的日志直接跳过，避免重复读取链上数据
+	processed, err := s.repo.IsLogProcessed(config.AppConfig.GetActiveChainID(), vLog.TxHash.Hex(), vLog.Index)
+	if err != nil {
+		log.Printf("⚠️ Failed to check processed log: %v", err)
+	} else if processed {
+		log.Printf("⏭️  Event already processed: %s #%d", vLog.TxHash.Hex(), vLog.Index)
+		return nil
+	}
+
+	apply, err := handle(vLog)
+	if err != nil {
+		log.Printf("❌ Failed to handle %s log: %v", eventType, err)
+		s.CreateSyncLog(eventType, vLog.BlockNumber, vLog.TxHash.Hex(), "failed", err.Error())
+		return fmt.Errorf("failed to handle %s log %s #%d: %w", eventType, vLog.TxHash.Hex(), vLog.Index, err)
+	}
+
+	message, domainEvent, err := s.applyLog(vLog, eventType, apply)
+	if errors.Is(err, errIndexedRecordMissing) {
+		log.Printf("⚠️ Skipping %s log %s #%d: %v", eventType, vLog.TxHash.Hex(), vLog.Index, err)
+		reason := err.Error()
+		if _, _, err = s.applyLog(vLog, eventType, skipLog); err == nil {
+			s.setLastBlock(vLog.BlockNumber)
+			s.CreateSyncLog(eventType, vLog.BlockNumber, vLog.TxHash.Hex(), "skipped", reason)
+			return nil
+		}
+	}
+	if errors.Is(err, errLogAlreadyProcessed) {
+		log.Printf("⏭️  Event already processed: %s #%d", vLog.TxHash.Hex(), vLog.Index)
+		return nil
+	}
+	if err != nil {
+		log.Printf("❌ Failed to save %s log: %v", eventType, err)
+		s.CreateSyncLog(eventType, vLog.BlockNumber, vLog.TxHash.Hex(), "failed", err.Error())
+		return fmt.Errorf("failed to save %s log %s #%d: %w", eventType, vLog.TxHash.Hex(), vLog.Index, err)
+	}
+
+	s.setLastBlock(vLog.BlockNumber)
+	log.Printf("✅ %s", message)
+	s.CreateSyncLog(eventType, vLog.BlockNumber, vLog.TxHash.Hex(), "success", message)
+
+	// 事务提交后再发布和唤醒投递，订阅者看到事件时数据库已可查询到对应数据
+	if s.outbox != nil && domainEvent != nil {
+		s.outbox.Notify()
+	}
+	s.publish(domainEvent)
+	return nil
 }
 
-// handleParticipantRegistered 处理 ParticipantRegistered 事件
-func (s *EventService) handleParticipantRegistered(vLog types.Log) {
-	log.Println("👤 Detected ParticipantRegistered event")
+// skipLog 不写入任何数据，只记录已处理日志并推进检查点
+func skipLog(*repositories.EventRepository) (string, *DomainEvent, error) {
+	return "", nil, nil
+}
 
-	if len(vLog.Topics) < 3 {
-		log.Println("❌ Invalid ParticipantRegistered log: missing topics")
+// annotate 补全领域事件的链上位置信息
+func (s *EventService) annotate(vLog types.Log, evt *DomainEvent) {
+	evt.ChainID = config.AppConfig.GetActiveChainID()
+	evt.Network = config.AppConfig.GetActiveNetworkName()
+	evt.BlockNumber = vLog.BlockNumber
+	evt.TxHash = vLog.TxHash.Hex()
+	evt.LogIndex = vLog.Index
+	evt.OccurredAt = time.Now()
+}
+
+// publish 将领域事件发布到事件总线（尽力而为，消费过慢的订阅者会丢弃事件）；
+// 总线通过公开的 /api/stream 和 /api/ws 推送，参与者信息按匿名访问者脱敏
+func (s *EventService) publish(evt *DomainEvent) {
+	if s.bus == nil || evt == nil {
 		return
 	}
+	s.bus.Publish(redactDomainEvent(evt))
+}
 
-	// Topic[1] is eventId (uint256)
-	eventID := new(big.Int).SetBytes(vLog.Topics[1].Bytes())
-	// Topic[2] is participant address
-	participantAddr := common.BytesToAddress(vLog.Topics[2].Bytes())
+// redactDomainEvent 返回参与者信息脱敏后的副本，不修改已写入发件箱的原事件
+func redactDomainEvent(evt *DomainEvent) *DomainEvent {
+	participant, ok := evt.Data.(*models.Participant)
+	if !ok {
+		return evt
+	}
+	redacted := *participant
+	redactParticipant(&redacted)
 
-	log.Printf("🆔 Event ID: %s, Participant: %s", eventID.String(), participantAddr.Hex())
+	public := *evt
+	public.Data = &redacted
+	return &public
+}
 
-	// 从合约获取参与者详细信息
+// applyLog 在同一事务中写入日志数据、记录已处理日志、写入发件箱并推进同步检查点
+func (s *EventService) applyLog(vLog types.Log, eventType string, apply logApply) (string, *DomainEvent, error) {
+	chainID := config.AppConfig.GetActiveChainID()
+	network := config.AppConfig.GetActiveNetworkName()
+
+	var message string
+	var domainEvent *DomainEvent
+	err := s.repo.GetDB().Transaction(func(tx *gorm.DB) error {
+		repo := s.repo.WithTx(tx)
+
+		inserted, err := repo.MarkLogProcessed(&models.ProcessedLog{
+			ChainID:         chainID,
+			Network:         network,
+			ContractAddress: s.getContractAddress(vLog),
+			TxHash:          vLog.TxHash.Hex(),
+			LogIndex:        vLog.Index,
+			BlockNumber:     vLog.BlockNumber,
+			EventType:       eventType,
+		})
+		if err != nil {
+			return err
+		}
+		if !inserted {
+			return errLogAlreadyProcessed
+		}
+
+		if message, domainEvent, err = apply(repo); err != nil {
+			return err
+		}
+		if domainEvent != nil {
+			s.annotate(vLog, domainEvent)
+			if s.outbox != nil {
+				if err := s.outbox.EnqueueTx(tx, domainEvent); err != nil {
+					return fmt.Errorf("failed to enqueue outbox records: %w", err)
+				}
+			}
+		}
+
+		return repo.AdvanceCheckpoint(&models.SyncCheckpoint{
+			ChainID:         chainID,
+			Network:         network,
+			ContractAddress: s.getContractAddress(vLog),
+			LastBlock:       vLog.BlockNumber,
+			BlockHash:       vLog.BlockHash.Hex(),
+		})
+	})
+
+	return message, domainEvent, err
+}
+
+// findContractParticipant 从合约读取活动参与者并找到指定钱包
+func (s *EventService) findContractParticipant(eventID *big.Int, wallet common.Address) (*blockchain.ContractParticipant, error) {
 	bc := s.getBlockchainClient()
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
 	participants, err := bc.GetEventParticipants(ctx, eventID)
 	if err != nil {
-		log.Printf("❌ Failed to get participant details: %v", err)
-		s.CreateSyncLog("participant_registered", vLog.BlockNumber, vLog.TxHash.Hex(), "failed", err.Error())
-		return
+		return nil, fmt.Errorf("failed to get participant details: %w", err)
 	}
 
-	// 找到对应的参与者
-	var targetParticipant *blockchain.ContractParticipant
 	for _, p := range participants {
-		if p.Wallet == participantAddr {
-			targetParticipant = &p
-			break
+		if p.Wallet == wallet {
+			return &p, nil
 		}
 	}
 
-	if targetParticipant == nil {
-		log.Printf("❌ Participant not found in contract data")
-		s.CreateSyncLog("participant_registered", vLog.BlockNumber, vLog.TxHash.Hex(), "failed", "participant not found")
-		return
+	return nil, fmt.Errorf("participant not found")
+}
+
+// blockTime 获取区块时间；查询失败时返回错误，该日志记为失败并在下一轮重试，不写入错误的时间
+func (s *EventService) blockTime(blockNumber uint64) (int64, error) {
+	bc := s.getBlockchainClient()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	ts, err := bc.GetBlockTime(ctx, blockNumber)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get time of block %d: %w", blockNumber, err)
+	}
+	return ts, nil
+}
+
+// handleParticipantRegistered 处理 ParticipantRegistered 事件
+func (s *EventService) handleParticipantRegistered(vLog types.Log) (logApply, error) {
+	log.Println("👤 Detected ParticipantRegistered event")
+
+	if len(vLog.Topics) < 3 {
+		return nil, fmt.Errorf("invalid ParticipantRegistered log: missing topics")
+	}
+
+	// Topic[1] is eventId (uint256)
+	eventID := new(big.Int).SetBytes(vLog.Topics[1].Bytes())
+	// Topic[2] is participant address
+	participantAddr := common.BytesToAddress(vLog.Topics[2].Bytes())
+
+	log.Printf("🆔 Event ID: %s, Participant: %s", eventID.String(), participantAddr.Hex())
+
+	// 从合约获取参与者详细信息
+	targetParticipant, err := s.findContractParticipant(eventID, participantAddr)
+	if err != nil {
+		return nil, err
 	}
 
 	// 获取链信息
@@ -170,38 +426,39 @@ func (s *EventService) handleParticipantRegistered(vLog types.Log) {
 		Network:         network,
 		ContractAddress: s.getContractAddress(vLog),
 		EventID:         eventID.String(),
-		Wallet:          targetParticipant.Wallet.Hex(),
+		Wallet:          models.AddressFrom(targetParticipant.Wallet),
 		Name:            targetParticipant.Name,
 		RegisteredAt:    targetParticipant.RegisteredAt.Int64(),
 		CheckedIn:       targetParticipant.CheckedIn,
 		CheckInTime:     targetParticipant.CheckInTime.Int64(),
 	}
 
-	// 保存到数据库
-	if err := s.repo.CreateParticipant(participant); err != nil {
-		log.Printf("❌ Failed to create participant in DB: %v", err)
-		s.CreateSyncLog("participant_registered", vLog.BlockNumber, vLog.TxHash.Hex(), "failed", err.Error())
-		return
-	}
+	return func(repo *repositories.EventRepository) (string, *DomainEvent, error) {
+		// 保存到数据库
+		if err := repo.CreateParticipant(participant); err != nil {
+			return "", nil, fmt.Errorf("failed to create participant: %w", err)
+		}
 
-	// 更新活动的参与者计数
-	event, err := s.repo.GetEventByID(eventID.String())
-	if err == nil {
-		event.ParticipantCount++
-		s.repo.UpdateEvent(event)
-	}
+		// 原子递增活动的参与者计数
+		if err := repo.IncrementParticipantCount(chainID, participant.ContractAddress, participant.EventID, 1); err != nil {
+			return "", nil, fmt.Errorf("failed to update participant count: %w", err)
+		}
 
-	log.Printf("✅ Participant saved: %s for event %s", participant.Name, participant.EventID)
-	s.CreateSyncLog("participant_registered", vLog.BlockNumber, vLog.TxHash.Hex(), "success", fmt.Sprintf("Saved participant %s", participant.Wallet))
+		return fmt.Sprintf("Saved participant %s for event %s", participant.Wallet, participant.EventID), &DomainEvent{
+			Type:    DomainParticipantRegistered,
+			EventID: participant.EventID,
+			Wallets: []string{participant.Wallet.String()},
+			Data:    participant,
+		}, nil
+	}, nil
 }
 
 // handleParticipantCheckedIn 处理 ParticipantCheckedIn 事件
-func (s *EventService) handleParticipantCheckedIn(vLog types.Log) {
+func (s *EventService) handleParticipantCheckedIn(vLog types.Log) (logApply, error) {
 	log.Println("✅ Detected ParticipantCheckedIn event")
 
 	if len(vLog.Topics) < 3 {
-		log.Println("❌ Invalid ParticipantCheckedIn log: missing topics")
-		return
+		return nil, fmt.Errorf("invalid ParticipantCheckedIn log: missing topics")
 	}
 
 	// Topic[1] is eventId (uint256)
@@ -212,60 +469,46 @@ func (s *EventService) handleParticipantCheckedIn(vLog types.Log) {
 	log.Printf("🆔 Event ID: %s, Participant: %s", eventID.String(), participantAddr.Hex())
 
 	// 从合约获取参与者详细信息
-	bc := s.getBlockchainClient()
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	participants, err := bc.GetEventParticipants(ctx, eventID)
+	targetParticipant, err := s.findContractParticipant(eventID, participantAddr)
 	if err != nil {
-		log.Printf("❌ Failed to get participant details: %v", err)
-		s.CreateSyncLog("participant_checked_in", vLog.BlockNumber, vLog.TxHash.Hex(), "failed", err.Error())
-		return
-	}
-
-	// 找到对应的参与者
-	var targetParticipant *blockchain.ContractParticipant
-	for _, p := range participants {
-		if p.Wallet == participantAddr {
-			targetParticipant = &p
-			break
-		}
+		return nil, err
 	}
 
-	if targetParticipant == nil {
-		log.Printf("❌ Participant not found in contract data")
-		s.CreateSyncLog("participant_checked_in", vLog.BlockNumber, vLog.TxHash.Hex(), "failed", "participant not found")
-		return
-	}
-
-	// 更新数据库中的参与者状态
-	var participant models.Participant
-	if err := s.repo.GetDB().Where("contract_address = ? AND event_id = ? AND wallet = ?", s.getContractAddress(vLog), eventID.String(), participantAddr.Hex()).First(&participant).Error; err != nil {
-		log.Printf("❌ Failed to find participant in DB: %v", err)
-		s.CreateSyncLog("participant_checked_in", vLog.BlockNumber, vLog.TxHash.Hex(), "failed", err.Error())
-		return
-	}
-
-	participant.CheckedIn = targetParticipant.CheckedIn
-	participant.CheckInTime = targetParticipant.CheckInTime.Int64()
+	chainID := config.AppConfig.GetActiveChainID()
+	contractAddress := s.getContractAddress(vLog)
 
-	if err := s.repo.GetDB().Save(&participant).Error; err != nil {
-		log.Printf("❌ Failed to update participant in DB: %v", err)
-		s.CreateSyncLog("participant_checked_in", vLog.BlockNumber, vLog.TxHash.Hex(), "failed", err.Error())
-		return
-	}
+	return func(repo *repositories.EventRepository) (string, *DomainEvent, error) {
+		// 更新数据库中的参与者状态
+		updated, err := repo.SetParticipantCheckIn(chainID, contractAddress, eventID.String(), models.AddressFrom(participantAddr),
+			targetParticipant.CheckedIn, targetParticipant.CheckInTime.Int64())
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to update participant: %w", err)
+		}
+		if !updated {
+			return "", nil, fmt.Errorf("%w: participant %s of event %s", errIndexedRecordMissing, participantAddr.Hex(), eventID.String())
+		}
+		if err := settleOfflineCheckIn(repo, chainID, eventID.String(), models.AddressFrom(participantAddr), vLog.TxHash.Hex()); err != nil {
+			return "", nil, err
+		}
 
-	log.Printf("✅ Participant checked in: %s for event %d", participant.Wallet, participant.EventID)
-	s.CreateSyncLog("participant_checked_in", vLog.BlockNumber, vLog.TxHash.Hex(), "success", fmt.Sprintf("Updated participant %s", participant.Wallet))
+		return fmt.Sprintf("Participant checked in: %s for event %s", participantAddr.Hex(), eventID.String()), &DomainEvent{
+			Type:    DomainParticipantCheckedIn,
+			EventID: eventID.String(),
+			Wallets: []string{participantAddr.Hex()},
+			Data: map[string]interface{}{
+				"checked_in":    targetParticipant.CheckedIn,
+				"check_in_time": targetParticipant.CheckInTime.Int64(),
+			},
+		}, nil
+	}, nil
 }
 
 // handleSponsorAdded 处理 SponsorAdded 事件
-func (s *EventService) handleSponsorAdded(vLog types.Log) {
+func (s *EventService) handleSponsorAdded(vLog types.Log) (logApply, error) {
 	log.Println("💰 Detected SponsorAdded event")
 
 	if len(vLog.Topics) < 3 {
-		log.Println("❌ Invalid SponsorAdded log: missing topics")
-		return
+		return nil, fmt.Errorf("invalid SponsorAdded log: missing topics")
 	}
 
 	// Topic[1] is eventId (uint256)
@@ -282,9 +525,7 @@ func (s *EventService) handleSponsorAdded(vLog types.Log) {
 
 	sponsors, err := bc.GetEventSponsors(ctx, eventID)
 	if err != nil {
-		log.Printf("❌ Failed to get sponsor details: %v", err)
-		s.CreateSyncLog("sponsor_added", vLog.BlockNumber, vLog.TxHash.Hex(), "failed", err.Error())
-		return
+		return nil, fmt.Errorf("failed to get sponsor details: %w", err)
 	}
 
 	// 找到对应的赞助商
@@ -297,9 +538,7 @@ func (s *EventService) handleSponsorAdded(vLog types.Log) {
 	}
 
 	if targetSponsor == nil {
-		log.Printf("❌ Sponsor not found in contract data")
-		s.CreateSyncLog("sponsor_added", vLog.BlockNumber, vLog.TxHash.Hex(), "failed", "sponsor not found")
-		return
+		return nil, fmt.Errorf("sponsor not found")
 	}
 
 	// 获取链信息
@@ -312,30 +551,33 @@ func (s *EventService) handleSponsorAdded(vLog types.Log) {
 		Network:         network,
 		ContractAddress: s.getContractAddress(vLog),
 		EventID:         eventID.String(),
-		Wallet:          targetSponsor.Wallet.Hex(),
+		Wallet:          models.AddressFrom(targetSponsor.Wallet),
 		Name:            targetSponsor.Name,
 		Amount:          targetSponsor.Amount.String(),
 		SponsoredAt:     targetSponsor.SponsoredAt.Int64(),
 	}
 
-	// 保存到数据库
-	if err := s.repo.CreateSponsor(sponsor); err != nil {
-		log.Printf("❌ Failed to create sponsor in DB: %v", err)
-		s.CreateSyncLog("sponsor_added", vLog.BlockNumber, vLog.TxHash.Hex(), "failed", err.Error())
-		return
-	}
+	return func(repo *repositories.EventRepository) (string, *DomainEvent, error) {
+		// 保存到数据库
+		if err := repo.CreateSponsor(sponsor); err != nil {
+			return "", nil, fmt.Errorf("failed to create sponsor: %w", err)
+		}
 
-	log.Printf("✅ Sponsor saved: %s for event %d (Amount: %s)", sponsor.Name, sponsor.EventID, sponsor.Amount)
-	s.CreateSyncLog("sponsor_added", vLog.BlockNumber, vLog.TxHash.Hex(), "success", fmt.Sprintf("Saved sponsor %s", sponsor.Wallet))
+		return fmt.Sprintf("Saved sponsor %s for event %s (Amount: %s)", sponsor.Wallet, sponsor.EventID, sponsor.Amount), &DomainEvent{
+			Type:    DomainSponsorAdded,
+			EventID: sponsor.EventID,
+			Wallets: []string{sponsor.Wallet.String()},
+			Data:    sponsor,
+		}, nil
+	}, nil
 }
 
 // handleEventCreated 处理 EventCreated 事件
-func (s *EventService) handleEventCreated(vLog types.Log) {
+func (s *EventService) handleEventCreated(vLog types.Log) (logApply, error) {
 	log.Println("🎉 Detected EventCreated event")
 
 	if len(vLog.Topics) < 2 {
-		log.Println("❌ Invalid EventCreated log: missing topics")
-		return
+		return nil, fmt.Errorf("invalid EventCreated log: missing topics")
 	}
 
 	// Topic[1] is eventId (uint256)
@@ -349,9 +591,7 @@ func (s *EventService) handleEventCreated(vLog types.Log) {
 
 	details, err := bc.GetEventDetails(ctx, eventID)
 	if err != nil {
-		log.Printf("❌ Failed to get event details: %v", err)
-		s.CreateSyncLog("event_created", vLog.BlockNumber, vLog.TxHash.Hex(), "failed", err.Error())
-		return
+		return nil, fmt.Errorf("failed to get event details: %w", err)
 	}
 
 	// 获取链信息
@@ -364,7 +604,7 @@ func (s *EventService) handleEventCreated(vLog types.Log) {
 		Network:          network,
 		ContractAddress:  s.getContractAddress(vLog),
 		EventID:          details.Id.String(), // 转换为字符串
-		Organizer:        details.Organizer.Hex(),
+		Organizer:        models.AddressFrom(details.Organizer),
 		Title:            details.Title,
 		Description:      details.Description,
 		StartTime:        details.StartTime.Int64(),
@@ -377,89 +617,85 @@ func (s *EventService) handleEventCreated(vLog types.Log) {
 		SyncedAt:         time.Now(),
 	}
 
-	// 保存到数据库
-	if err := s.repo.CreateEvent(event); err != nil {
-		log.Printf("❌ Failed to create event in DB: %v", err)
-		// 尝试更新（如果已存在）
-		if err := s.repo.UpdateEvent(event); err != nil {
-			log.Printf("❌ Failed to update event in DB: %v", err)
-			s.CreateSyncLog("event_created", vLog.BlockNumber, vLog.TxHash.Hex(), "failed", err.Error())
-			return
+	return func(repo *repositories.EventRepository) (string, *DomainEvent, error) {
+		// 已存在则更新，否则创建
+		existing, err := repo.FindEvent(chainID, event.ContractAddress, event.EventID)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to find event: %w", err)
+		}
+		if existing != nil {
+			event.ID = existing.ID
+			if err := repo.UpdateEvent(event); err != nil {
+				return "", nil, fmt.Errorf("failed to update event: %w", err)
+			}
+		} else if err := repo.CreateEvent(event); err != nil {
+			return "", nil, fmt.Errorf("failed to create event: %w", err)
 		}
-	}
 
-	log.Printf("✅ Event saved: %s (ID: %s)", event.Title, event.EventID)
-	s.CreateSyncLog("event_created", vLog.BlockNumber, vLog.TxHash.Hex(), "success", fmt.Sprintf("Saved event %s", event.EventID))
+		return fmt.Sprintf("Saved event %s (%s)", event.EventID, event.Title), &DomainEvent{
+			Type:    DomainEventCreated,
+			EventID: event.EventID,
+			Wallets: []string{event.Organizer.String()},
+			Data:    event,
+		}, nil
+	}, nil
 }
 
-// SyncEvents 同步链上的活动数据 (Deprecated: Use SubscribeEvents instead)
-func (s *EventService) SyncEvents(ctx context.Context) error {
-	log.Println("🔄 Starting event sync...")
+// handleEventClosed 处理 EventClosed 事件
+func (s *EventService) handleEventClosed(vLog types.Log) (logApply, error) {
+	log.Println("🔒 Detected EventClosed event")
 
-	// 获取最后同步的区块
-	lastBlock, err := s.repo.GetLastSyncBlock("event")
-	if err != nil {
-		log.Printf("❌ Failed to get last sync block: %v", err)
-		return err
+	if len(vLog.Topics) < 2 {
+		return nil, fmt.Errorf("invalid EventClosed log: missing topics")
 	}
 
-	log.Printf("📦 Last synced block: %d", lastBlock)
+	// Topic[1] is eventId (uint256)
+	eventID := new(big.Int).SetBytes(vLog.Topics[1].Bytes())
+	log.Printf("🆔 Event ID: %s", eventID.String())
+
+	chainID := config.AppConfig.GetActiveChainID()
+	contractAddress := s.getContractAddress(vLog)
+
+	return func(repo *repositories.EventRepository) (string, *DomainEvent, error) {
+		event, err := repo.SetEventActive(chainID, contractAddress, eventID.String(), false)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to close event: %w", err)
+		}
+		if event == nil {
+			return "", nil, fmt.Errorf("%w: event %s", errIndexedRecordMissing, eventID.String())
+		}
+
+		return fmt.Sprintf("Closed event %s (%s)", event.EventID, event.Title), &DomainEvent{
+			Type:    DomainEventClosed,
+			EventID: event.EventID,
+			Wallets: []string{event.Organizer.String()},
+			Data:    event,
+		}, nil
+	}, nil
+}
+
+// SyncEvents 从同步检查点开始补齐一次链上日志 (Deprecated: Use RunSync instead)
+func (s *EventService) SyncEvents(ctx context.Context) error {
+	log.Println("🔄 Starting event sync...")
 
-	// 获取最新区块
 	bc := s.getBlockchainClient()
 	if bc == nil {
 		log.Println("⚠️ Blockchain client not initialized, skipping sync")
 		return nil
 	}
 
-	latestBlock, err := bc.GetLatestBlockNumber(ctx)
+	// 获取最后同步的区块
+	lastBlock, err := s.loadCheckpointBlock()
 	if err != nil {
-		log.Printf("❌ Failed to get latest block: %v", err)
+		log.Printf("❌ Failed to get sync checkpoint: %v", err)
 		return err
 	}
 
-	log.Printf("📊 Latest block: %d", latestBlock)
-
-	// 如果没有新区块，跳过
-	if latestBlock <= lastBlock {
-		log.Println("✅ No new blocks to sync")
-		return nil
-	}
-
-	// 分批查询（RPC 限制最多 1000 个区块）
-	const batchSize = 1000
-	currentBlock := lastBlock
-	totalLogs := 0
-
-	for currentBlock < latestBlock {
-		toBlock := currentBlock + batchSize
-		if toBlock > latestBlock {
-			toBlock = latestBlock
-		}
-
-		log.Printf("📋 Syncing blocks %d to %d", currentBlock, toBlock)
-
-		// 从区块链获取事件日志
-		logs, err := bc.GetEventLogs(ctx, currentBlock, toBlock)
-		if err != nil {
-			log.Printf("❌ Failed to get event logs: %v", err)
-			s.CreateSyncLog("event", toBlock, "", "failed", err.Error())
-			return err
-		}
-
-		log.Printf("📋 Found %d logs in this batch", len(logs))
-		totalLogs += len(logs)
-
-		// 记录同步进度
-		s.CreateSyncLog("event", toBlock, "", "success", "")
-
-		currentBlock = toBlock
-	}
+	log.Printf("📦 Last synced block: %d", lastBlock)
 
-	if totalLogs > 0 {
-		log.Printf("✅ Synced %d events total", totalLogs)
-	} else {
-		log.Println("✅ No new events to sync")
+	if _, err := s.pollOnce(ctx, bc, lastBlock); err != nil {
+		log.Printf("❌ Event sync failed: %v", err)
+		return err
 	}
 
 	log.Println("✅ Event sync completed")
@@ -471,9 +707,9 @@ func (s *EventService) getBlockchainClient() *blockchain.BlockchainClient {
 	return blockchain.GetInstance()
 }
 
-// GetAllEvents 获取所有活动
-func (s *EventService) GetAllEvents() ([]models.Event, error) {
-	return s.repo.GetAllEvents()
+// ListEvents 分页查询活动
+func (s *EventService) ListEvents(filter repositories.EventFilter, page repositories.PageQuery) (*repositories.Page[models.Event], error) {
+	return s.repo.ListEvents(filter, page)
 }
 
 // GetEventByID 根据 ID 获取活动
@@ -486,26 +722,39 @@ func (s *EventService) GetEventByDBID(id uint64) (*models.Event, error) {
 	return s.repo.GetEventByDBID(id)
 }
 
-// GetEventsByOrganizer 根据组织者获取活动
-func (s *EventService) GetEventsByOrganizer(organizer string) ([]models.Event, error) {
-	return s.repo.GetEventsByOrganizer(organizer)
+// ListEventParticipants 分页查询活动的参与者
+func (s *EventService) ListEventParticipants(filter repositories.ParticipantFilter, page repositories.PageQuery) (*repositories.Page[models.Participant], error) {
+	return s.repo.ListParticipants(filter, page)
+}
+
+// ListEventSponsors 分页查询活动的赞助商
+func (s *EventService) ListEventSponsors(filter repositories.SponsorFilter, page repositories.PageQuery) (*repositories.Page[models.Sponsor], error) {
+	return s.repo.ListSponsors(filter, page)
+}
+
+// ListTickets 分页查询 NFT 门票
+func (s *EventService) ListTickets(filter repositories.TicketFilter, page repositories.PageQuery) (*repositories.Page[models.NFTTicket], error) {
+	return s.repo.ListTickets(filter, page)
+}
+
+// GetEventsByKeys 批量获取活动（GraphQL dataloader 使用）
+func (s *EventService) GetEventsByKeys(keys []repositories.EventKey) ([]models.Event, error) {
+	return s.repo.GetEventsByKeys(keys)
 }
 
-// GetEventParticipants 获取活动的参与者
-func (s *EventService) GetEventParticipants(eventID string) ([]models.Participant, error) {
-	return s.repo.GetParticipantsByEvent(eventID)
+// GetParticipantsByEventKeys 批量获取多个活动的参与者
+func (s *EventService) GetParticipantsByEventKeys(keys []repositories.EventKey) ([]models.Participant, error) {
+	return s.repo.GetParticipantsByEventKeys(keys)
 }
 
-// GetEventSponsors 获取活动的赞助商
-func (s *EventService) GetEventSponsors(eventID string) ([]models.Sponsor, error) {
-	return s.repo.GetSponsorsByEvent(eventID)
+// GetSponsorsByEventKeys 批量获取多个活动的赞助商
+func (s *EventService) GetSponsorsByEventKeys(keys []repositories.EventKey) ([]models.Sponsor, error) {
+	return s.repo.GetSponsorsByEventKeys(keys)
 }
 
-// GetEventTickets 获取活动的 NFT 门票
-func (s *EventService) GetEventTickets(eventID string) ([]models.NFTTicket, error) {
-	var tickets []models.NFTTicket
-	err := s.repo.GetDB().Where("event_id = ?", eventID).Find(&tickets).Error
-	return tickets, err
+// GetTicketsByEventKeys 批量获取多个活动的门票
+func (s *EventService) GetTicketsByEventKeys(keys []repositories.EventKey) ([]models.NFTTicket, error) {
+	return s.repo.GetTicketsByEventKeys(keys)
 }
 
 // CreateSyncLog 创建同步日志
@@ -549,12 +798,11 @@ func (s *EventService) GetSyncStats() (map[string]interface{}, error) {
 }
 
 // handleTicketIssued 处理 TicketIssued 事件
-func (s *EventService) handleTicketIssued(vLog types.Log) {
+func (s *EventService) handleTicketIssued(vLog types.Log) (logApply, error) {
 	log.Println("🎫 Detected TicketIssued event")
 
 	if len(vLog.Topics) < 4 {
-		log.Printf("⚠️ Invalid TicketIssued event topics length: %d", len(vLog.Topics))
-		return
+		return nil, fmt.Errorf("invalid TicketIssued event topics length: %d", len(vLog.Topics))
 	}
 
 	// Topic[1] is eventId (uint256)
@@ -573,9 +821,7 @@ func (s *EventService) handleTicketIssued(vLog types.Log) {
 
 	ticket, err := bc.GetTicket(ctx, tokenID)
 	if err != nil {
-		log.Printf("❌ Failed to get ticket details from contract: %v", err)
-		s.CreateSyncLog("ticket_issued", vLog.BlockNumber, vLog.TxHash.Hex(), "failed", err.Error())
-		return
+		return nil, fmt.Errorf("failed to get ticket details from contract: %w", err)
 	}
 
 	// 获取链信息
@@ -589,7 +835,7 @@ func (s *EventService) handleTicketIssued(vLog types.Log) {
 		ContractAddress: s.getContractAddress(vLog),
 		TokenID:         ticket.TokenID.String(),
 		EventID:         ticket.EventID.String(),
-		Holder:          ticket.Holder.Hex(),
+		Holder:          models.AddressFrom(ticket.Holder),
 		EventTitle:      ticket.EventTitle,
 		Location:        ticket.Location,
 		StartTime:       ticket.StartTime.Int64(),
@@ -598,63 +844,115 @@ func (s *EventService) handleTicketIssued(vLog types.Log) {
 		IssuedAt:        ticket.IssuedAt.Int64(),
 	}
 
-	// 保存到数据库
-	if err := s.repo.GetDB().Create(nftTicket).Error; err != nil {
-		log.Printf("❌ Failed to save NFT ticket: %v", err)
-		s.CreateSyncLog("ticket_issued", vLog.BlockNumber, vLog.TxHash.Hex(), "failed", err.Error())
-		return
-	}
+	return func(repo *repositories.EventRepository) (string, *DomainEvent, error) {
+		// 保存到数据库
+		if err := repo.CreateNFTTicket(nftTicket); err != nil {
+			return "", nil, fmt.Errorf("failed to save NFT ticket: %w", err)
+		}
 
-	log.Printf("✅ NFT Ticket saved: Token ID %d for event %d, holder %s", nftTicket.TokenID, nftTicket.EventID, nftTicket.Holder)
-	s.CreateSyncLog("ticket_issued", vLog.BlockNumber, vLog.TxHash.Hex(), "success", fmt.Sprintf("Saved NFT ticket %d", nftTicket.TokenID))
+		return fmt.Sprintf("Saved NFT ticket %s for event %s, holder %s", nftTicket.TokenID, nftTicket.EventID, nftTicket.Holder), &DomainEvent{
+			Type:    DomainTicketIssued,
+			EventID: nftTicket.EventID,
+			Wallets: []string{nftTicket.Holder.String()},
+			TokenID: nftTicket.TokenID,
+			Data:    nftTicket,
+		}, nil
+	}, nil
 }
 
 // handleTicketUsed 处理 TicketUsed 事件
-func (s *EventService) handleTicketUsed(vLog types.Log) {
+func (s *EventService) handleTicketUsed(vLog types.Log) (logApply, error) {
 	log.Printf("📝 Processing TicketUsed event, Block: %d, TxHash: %s", vLog.BlockNumber, vLog.TxHash.Hex())
 
-	// 检查是否已处理
-	var existingLog models.SyncLog
-	if err := s.repo.GetDB().Where("tx_hash = ? AND event_type = ?", vLog.TxHash.Hex(), "ticket_used").First(&existingLog).Error; err == nil {
-		log.Printf("⏭️  Event already processed: %s", vLog.TxHash.Hex())
-		return
-	}
-
 	// TicketUsed 事件只有一个参数: tokenId (indexed)
 	// Topics[0]: 事件签名
 	// Topics[1]: tokenId
 	if len(vLog.Topics) < 2 {
-		log.Printf("❌ Invalid TicketUsed event: insufficient topics")
-		s.CreateSyncLog("ticket_used", vLog.BlockNumber, vLog.TxHash.Hex(), "failed", "Insufficient topics")
-		return
+		return nil, fmt.Errorf("invalid TicketUsed event: insufficient topics")
 	}
 
 	tokenID := new(big.Int).SetBytes(vLog.Topics[1][:])
 	tokenIDStr := tokenID.String()
 	log.Printf("🎫 Token ID from event: %s", tokenIDStr)
 
-	// 查询 NFT ticket (使用合约地址+tokenId定位)
-	var nftTicket models.NFTTicket
-	if err := s.repo.GetDB().Where("contract_address = ? AND token_id = ?", s.getContractAddress(vLog), tokenIDStr).First(&nftTicket).Error; err != nil {
-		log.Printf("❌ Failed to find NFT ticket: %v", err)
-		s.CreateSyncLog("ticket_used", vLog.BlockNumber, vLog.TxHash.Hex(), "failed", fmt.Sprintf("Ticket not found: %s", tokenIDStr))
-		return
-	}
+	chainID := config.AppConfig.GetActiveChainID()
 
-	// 检查是否已经被使用
-	if nftTicket.Used {
-		log.Printf("⚠️  Ticket %s already marked as used", tokenIDStr)
-		s.CreateSyncLog("ticket_used", vLog.BlockNumber, vLog.TxHash.Hex(), "success", fmt.Sprintf("Ticket %s already used", tokenIDStr))
-		return
+	return func(repo *repositories.EventRepository) (string, *DomainEvent, error) {
+		// TicketUsed 由 NFTTicket 合约触发，门票记录在触发 TicketIssued 的 Hackathon 合约下，按链和 tokenId 定位
+		ticket, err := repo.MarkTicketUsed(chainID, tokenIDStr)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to mark ticket as used: %w", err)
+		}
+		if ticket == nil {
+			return "", nil, fmt.Errorf("%w: ticket %s", errIndexedRecordMissing, tokenIDStr)
+		}
+		if err := reconcileOfflineTicket(repo, ticket); err != nil {
+			return "", nil, err
+		}
+
+		return fmt.Sprintf("Marked ticket %s as used", tokenIDStr), &DomainEvent{
+			Type:    DomainTicketUsed,
+			EventID: ticket.EventID,
+			Wallets: []string{ticket.Holder.String()},
+			TokenID: ticket.TokenID,
+			Data:    ticket,
+		}, nil
+	}, nil
+}
+
+// handleTicketTransferred 处理 TicketTransferred 事件
+func (s *EventService) handleTicketTransferred(vLog types.Log) (logApply, error) {
+	log.Println("🔁 Detected TicketTransferred event")
+
+	// Topics[1]: tokenId, Topics[2]: from, Topics[3]: to
+	if len(vLog.Topics) < 4 {
+		return nil, fmt.Errorf("invalid TicketTransferred event topics length: %d", len(vLog.Topics))
 	}
 
-	// 更新票据为已使用状态
-	if err := s.repo.GetDB().Model(&nftTicket).Update("used", true).Error; err != nil {
-		log.Printf("❌ Failed to mark ticket as used: %v", err)
-		s.CreateSyncLog("ticket_used", vLog.BlockNumber, vLog.TxHash.Hex(), "failed", err.Error())
-		return
+	tokenID := new(big.Int).SetBytes(vLog.Topics[1].Bytes()).String()
+	fromAddr := common.BytesToAddress(vLog.Topics[2].Bytes())
+	toAddr := common.BytesToAddress(vLog.Topics[3].Bytes())
+
+	log.Printf("🎫 Token ID: %s, From: %s, To: %s", tokenID, fromAddr.Hex(), toAddr.Hex())
+
+	chainID := config.AppConfig.GetActiveChainID()
+	contractAddress := s.getContractAddress(vLog)
+	transferredAt, err := s.blockTime(vLog.BlockNumber)
+	if err != nil {
+		return nil, err
 	}
 
-	log.Printf("✅ Ticket marked as used: Token ID %s", tokenIDStr)
-	s.CreateSyncLog("ticket_used", vLog.BlockNumber, vLog.TxHash.Hex(), "success", fmt.Sprintf("Marked ticket %s as used", tokenIDStr))
+	return func(repo *repositories.EventRepository) (string, *DomainEvent, error) {
+		// 与 TicketUsed 相同，按链和 tokenId 定位 Hackathon 合约下的门票
+		ticket, err := repo.TransferTicket(chainID, tokenID, models.AddressFrom(toAddr))
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to transfer ticket: %w", err)
+		}
+		if ticket == nil {
+			return "", nil, fmt.Errorf("%w: ticket %s", errIndexedRecordMissing, tokenID)
+		}
+		if err := repo.CreateTicketTransfer(&models.TicketTransfer{
+			ChainID:         chainID,
+			Network:         config.AppConfig.GetActiveNetworkName(),
+			ContractAddress: contractAddress,
+			EventID:         ticket.EventID,
+			TokenID:         tokenID,
+			FromAddress:     models.AddressFrom(fromAddr),
+			ToAddress:       models.AddressFrom(toAddr),
+			BlockNumber:     vLog.BlockNumber,
+			TxHash:          vLog.TxHash.Hex(),
+			LogIndex:        vLog.Index,
+			TransferredAt:   transferredAt,
+		}); err != nil {
+			return "", nil, fmt.Errorf("failed to record ticket transfer: %w", err)
+		}
+
+		return fmt.Sprintf("Transferred ticket %s from %s to %s", tokenID, fromAddr.Hex(), toAddr.Hex()), &DomainEvent{
+			Type:    DomainTicketTransferred,
+			EventID: ticket.EventID,
+			Wallets: []string{fromAddr.Hex(), toAddr.Hex()},
+			TokenID: ticket.TokenID,
+			Data:    ticket,
+		}, nil
+	}, nil
 }
@@ -0,0 +1,99 @@
+package services
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"hackathon-backend/config"
+	"hackathon-backend/models"
+	"hackathon-backend/repositories"
+	"hackathon-backend/testdb"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestPublishRedactsParticipant(t *testing.T) {
+	bus := NewEventBus()
+	service := &EventService{bus: bus}
+	wallet, _ := models.ParseAddress("0xad6F55f669eaf666b7628d7Bd482Eb000e24D687")
+
+	sub := bus.Subscribe([]string{WalletTopic(wallet.String())})
+	defer sub.Close()
+
+	participant := &models.Participant{EventID: "1", Wallet: wallet, Name: "Alice"}
+	evt := &DomainEvent{
+		Type:    DomainParticipantRegistered,
+		EventID: "1",
+		Wallets: []string{wallet.String()},
+		Data:    participant,
+	}
+	service.publish(evt)
+
+	select {
+	case got := <-sub.Events():
+		public := got.Data.(*models.Participant)
+		if public.Name != "" || public.Wallet != wallet {
+			t.Errorf("published participant %q %q, want redacted", public.Name, public.Wallet)
+		}
+	default:
+		t.Fatal("wallet subscriber did not receive the event")
+	}
+
+	// 发件箱持有的原事件保持完整
+	if evt.Data != participant || participant.Name != "Alice" || participant.Wallet != wallet {
+		t.Errorf("original event was modified: %+v", evt.Data)
+	}
+}
+
+func TestHandleTicketUsedFromTicketContract(t *testing.T) {
+	config.AppConfig = &config.Config{ActiveNetwork: "somnia"}
+	db := testdb.Open(t)
+	if err := models.AutoMigrate(db); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	service := &EventService{repo: repositories.NewEventRepository(db)}
+
+	hackathon := common.HexToAddress("0x65BBA3f213534A4Dfc54E9e0CE82E944859EEB24")
+	nftTicket := common.HexToAddress("0x3A181f96fa8a6e5757E954Bc28051a7F5C539AAb")
+	holder, _ := models.ParseAddress("0xad6F55f669eaf666b7628d7Bd482Eb000e24D687")
+	// TicketIssued 由 Hackathon 合约触发，门票记录在 Hackathon 合约地址下
+	ticket := &models.NFTTicket{ChainID: 50312, Network: "somnia", ContractAddress: hackathon.Hex(), TokenID: "7", EventID: "1", Holder: holder}
+	if err := db.Create(ticket).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	ticketUsed := func(tokenID int64) types.Log {
+		return types.Log{
+			Address:     nftTicket,
+			Topics:      []common.Hash{crypto.Keccak256Hash([]byte("TicketUsed(uint256)")), common.BigToHash(big.NewInt(tokenID))},
+			BlockNumber: 100,
+		}
+	}
+
+	apply, err := service.handleTicketUsed(ticketUsed(7))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, evt, err := apply(service.repo); err != nil || evt == nil || evt.TokenID != "7" {
+		t.Fatalf("apply = %+v, %v; want ticket 7 marked used", evt, err)
+	}
+	stored, err := service.repo.FindTicketOnChain(50312, "7")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !stored.Used {
+		t.Error("ticket not marked used")
+	}
+
+	// 未索引的门票重试也无法找到，应记为跳过而不是阻塞同步
+	apply, err = service.handleTicketUsed(ticketUsed(8))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := apply(service.repo); !errors.Is(err, errIndexedRecordMissing) {
+		t.Errorf("apply for unknown ticket = %v, want %v", err, errIndexedRecordMissing)
+	}
+}
@@ -0,0 +1,396 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"hackathon-backend/blockchain"
+	"hackathon-backend/config"
+	"hackathon-backend/models"
+
+	"github.com/ethereum/go-ethereum/common"
+	"gorm.io/gorm"
+)
+
+// 同步模式
+const (
+	SyncModeAuto      = "auto"      // 优先 WebSocket 订阅，不支持时自动降级为轮询
+	SyncModeWebSocket = "websocket" // 仅使用 WebSocket 订阅
+	SyncModePolling   = "polling"   // 通过 HTTP eth_getLogs 轮询
+)
+
+// pollBatchSize 单次 eth_getLogs 查询的最大区块跨度（RPC 限制）
+const pollBatchSize = 1000
+
+// syncRetryDelay 同步出错后的重试间隔
+const syncRetryDelay = 5 * time.Second
+
+// SyncStatus 当前同步状态
+type SyncStatus struct {
+	Mode           string `json:"mode"`            // 配置的模式
+	ActiveMode     string `json:"active_mode"`     // 实际运行的模式
+	PollInterval   int    `json:"poll_interval"`   // 轮询间隔（秒）
+	LastBlock      uint64 `json:"last_block"`      // 最后处理的区块
+	SubscriptionOK bool   `json:"subscription_ok"` // 节点是否支持订阅
+}
+
+// IsValidSyncMode 校验同步模式
+func IsValidSyncMode(mode string) bool {
+	switch mode {
+	case SyncModeAuto, SyncModeWebSocket, SyncModePolling:
+		return true
+	}
+	return false
+}
+
+// RunSync 按当前同步模式持续摄取链上日志，模式切换或出错后自动重启
+func (s *EventService) RunSync(ctx context.Context) {
+	for {
+		runCtx, cancel := context.WithCancel(ctx)
+		mode := s.startRun(cancel)
+
+		var err error
+		switch mode {
+		case SyncModePolling:
+			log.Printf("🚀 Starting polling event listener (interval %s)...", s.getPollInterval())
+			err = s.PollEvents(runCtx)
+		default:
+			log.Println("🚀 Starting WebSocket event listener...")
+			err = s.SubscribeEvents(runCtx)
+		}
+		cancel()
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		// 模式被切换，立即按新模式重启
+		if runCtx.Err() != nil {
+			log.Printf("🔄 Sync mode switched to %s", s.GetSyncMode())
+			continue
+		}
+
+		if errors.Is(err, blockchain.ErrSubscriptionsUnsupported) && s.GetSyncMode() == SyncModeAuto {
+			log.Printf("⚠️ WebSocket subscription not supported: %v", err)
+			log.Println("🔄 Falling back to polling mode...")
+			s.mu.Lock()
+			s.subscriptionsUnsupported = true
+			s.mu.Unlock()
+			continue
+		}
+
+		if err != nil {
+			log.Printf("❌ Event %s sync failed: %v. Retrying in %s...", mode, err, syncRetryDelay)
+			s.mu.Lock()
+			s.lastSyncErr = err.Error()
+			s.mu.Unlock()
+		}
+
+		select {
+		case <-time.After(syncRetryDelay):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// startRun 确定本轮运行的模式并记录取消函数，供运行时切换模式使用
+func (s *EventService) startRun(cancel context.CancelFunc) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.cancelRun = cancel
+	s.activeMode = s.resolveSyncModeLocked()
+	return s.activeMode
+}
+
+// resolveSyncModeLocked 将 auto 模式解析为具体模式（调用方需持有锁）
+func (s *EventService) resolveSyncModeLocked() string {
+	if s.syncMode != SyncModeAuto {
+		return s.syncMode
+	}
+
+	bc := s.getBlockchainClient()
+	if s.subscriptionsUnsupported || bc == nil || !bc.SupportsSubscriptions() {
+		return SyncModePolling
+	}
+	return SyncModeWebSocket
+}
+
+// GetSyncMode 获取配置的同步模式
+func (s *EventService) GetSyncMode() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.syncMode
+}
+
+// SetSyncMode 运行时切换同步模式，pollInterval 为 0 时保持原轮询间隔
+func (s *EventService) SetSyncMode(mode string, pollInterval int) error {
+	if !IsValidSyncMode(mode) {
+		return fmt.Errorf("invalid sync mode: %s", mode)
+	}
+	if pollInterval < 0 {
+		return fmt.Errorf("invalid poll interval: %d", pollInterval)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.syncMode = mode
+	s.subscriptionsUnsupported = false
+	if pollInterval > 0 {
+		s.pollInterval = time.Duration(pollInterval) * time.Second
+	}
+
+	// 中断当前运行中的监听，RunSync 会按新模式重启
+	if s.cancelRun != nil {
+		s.cancelRun()
+	}
+
+	log.Printf("🔧 Sync mode set to %s (poll interval %s)", s.syncMode, s.pollInterval)
+	return nil
+}
+
+// GetSyncStatus 获取当前同步状态
+func (s *EventService) GetSyncStatus() *SyncStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	bc := s.getBlockchainClient()
+	return &SyncStatus{
+		Mode:           s.syncMode,
+		ActiveMode:     s.activeMode,
+		PollInterval:   int(s.pollInterval / time.Second),
+		LastBlock:      s.lastBlock,
+		SubscriptionOK: bc != nil && bc.SupportsSubscriptions() && !s.subscriptionsUnsupported,
+	}
+}
+
+// PollEvents 通过 HTTP eth_getLogs 轮询链上事件
+func (s *EventService) PollEvents(ctx context.Context) error {
+	bc := s.getBlockchainClient()
+	if bc == nil {
+		return fmt.Errorf("blockchain client not initialized")
+	}
+
+	// 从同步检查点所在区块继续（已处理的日志由 processed_logs 去重），首次启动时从最新区块开始
+	lastBlock, err := s.loadCheckpointBlock()
+	if err != nil {
+		return fmt.Errorf("failed to load sync checkpoint: %w", err)
+	}
+
+	fromBlock := lastBlock
+	if lastBlock == 0 {
+		latest, err := bc.GetLatestBlockNumber(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get latest block: %w", err)
+		}
+		fromBlock = latest + 1
+		s.advanceContractCheckpoints(ctx, bc, latest)
+	}
+
+	log.Printf("✅ Polling for events from block %d...", fromBlock)
+
+	for {
+		next, err := s.pollOnce(ctx, bc, fromBlock)
+		if err != nil {
+			return err
+		}
+		fromBlock = next
+
+		select {
+		case <-time.After(s.getPollInterval()):
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// pollOnce 拉取 fromBlock 到最新区块之间的日志，返回下一次的起始区块；
+// 日志处理失败时返回该日志所在区块，检查点不会越过该区块
+func (s *EventService) pollOnce(ctx context.Context, bc *blockchain.BlockchainClient, fromBlock uint64) (uint64, error) {
+	latest, err := bc.GetLatestBlockNumber(ctx)
+	if err != nil {
+		return fromBlock, fmt.Errorf("failed to get latest block: %w", err)
+	}
+
+	for fromBlock <= latest {
+		toBlock := fromBlock + pollBatchSize - 1
+		if toBlock > latest {
+			toBlock = latest
+		}
+
+		logs, err := bc.GetEventLogs(ctx, fromBlock, toBlock)
+		if err != nil {
+			return fromBlock, fmt.Errorf("failed to get event logs (%d-%d): %w", fromBlock, toBlock, err)
+		}
+
+		if len(logs) > 0 {
+			log.Printf("📋 Found %d logs in blocks %d to %d", len(logs), fromBlock, toBlock)
+		}
+		for _, vLog := range logs {
+			// 停在第一个失败的日志所在区块，下次从该区块重新拉取（已处理的日志由 processed_logs 去重）
+			if err := s.processLog(vLog); err != nil {
+				return vLog.BlockNumber, err
+			}
+		}
+
+		s.advanceContractCheckpoints(ctx, bc, toBlock)
+		fromBlock = toBlock + 1
+	}
+
+	s.mu.Lock()
+	s.lastPollAt = time.Now()
+	s.lastSyncErr = ""
+	s.mu.Unlock()
+
+	return fromBlock, nil
+}
+
+// setSubscribed 记录 WebSocket 订阅状态
+func (s *EventService) setSubscribed(subscribed bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.subscribed = subscribed
+	if subscribed {
+		s.lastSyncErr = ""
+	}
+}
+
+// markLogReceived 记录最后一次收到日志的时间
+func (s *EventService) markLogReceived() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastLogAt = time.Now()
+}
+
+func (s *EventService) getPollInterval() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.pollInterval
+}
+
+func (s *EventService) getLastBlock() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastBlock
+}
+
+// setLastBlock 记录已处理的最高区块（只前进不后退）
+func (s *EventService) setLastBlock(block uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if block > s.lastBlock {
+		s.lastBlock = block
+	}
+}
+
+// loadCheckpointBlock 加载当前链已完成处理的区块（取所有合约检查点的最小值）
+func (s *EventService) loadCheckpointBlock() (uint64, error) {
+	if block := s.getLastBlock(); block > 0 {
+		return block, nil
+	}
+
+	bc := s.getBlockchainClient()
+	if bc == nil {
+		return 0, fmt.Errorf("blockchain client not initialized")
+	}
+
+	chainID := config.AppConfig.GetActiveChainID()
+	var lastBlock uint64
+	for i, addr := range []common.Address{bc.GetHackathonAddress(), bc.GetNFTTicketAddress()} {
+		checkpoint, err := s.repo.GetCheckpoint(chainID, addr.Hex())
+		if err != nil {
+			return 0, err
+		}
+		if checkpoint == nil {
+			return 0, nil
+		}
+		if i == 0 || checkpoint.LastBlock < lastBlock {
+			lastBlock = checkpoint.LastBlock
+		}
+	}
+
+	s.setLastBlock(lastBlock)
+	return lastBlock, nil
+}
+
+// advanceContractCheckpoints 将所有监听合约的检查点推进到指定区块（同一事务）
+func (s *EventService) advanceContractCheckpoints(ctx context.Context, bc *blockchain.BlockchainClient, block uint64) {
+	s.setLastBlock(block)
+
+	blockHash := ""
+	if hash, err := bc.GetBlockHash(ctx, block); err != nil {
+		log.Printf("⚠️ Failed to get hash of block %d: %v", block, err)
+	} else {
+		blockHash = hash.Hex()
+	}
+
+	chainID := config.AppConfig.GetActiveChainID()
+	network := config.AppConfig.GetActiveNetworkName()
+
+	err := s.repo.GetDB().Transaction(func(tx *gorm.DB) error {
+		repo := s.repo.WithTx(tx)
+		for _, addr := range []common.Address{bc.GetHackathonAddress(), bc.GetNFTTicketAddress()} {
+			checkpoint := &models.SyncCheckpoint{
+				ChainID:         chainID,
+				Network:         network,
+				ContractAddress: addr.Hex(),
+				LastBlock:       block,
+				BlockHash:       blockHash,
+			}
+			if err := repo.AdvanceCheckpoint(checkpoint); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("❌ Failed to advance sync checkpoints to block %d: %v", block, err)
+	}
+}
+
+// CheckpointLag 某个检查点的索引延迟
+type CheckpointLag struct {
+	models.SyncCheckpoint
+	HeadBlock *uint64 `json:"head_block"` // 链上最新区块（仅当前活动网络可用）
+	Lag       *uint64 `json:"lag"`        // 最新区块与检查点的差值
+}
+
+// GetIndexingLag 获取各网络各合约的同步检查点及索引延迟
+func (s *EventService) GetIndexingLag(ctx context.Context) ([]CheckpointLag, error) {
+	checkpoints, err := s.repo.GetCheckpoints()
+	if err != nil {
+		return nil, err
+	}
+
+	// 只有当前活动网络有 RPC 连接，可以获取链上最新区块
+	var head *uint64
+	if bc := s.getBlockchainClient(); bc != nil {
+		if latest, err := bc.GetLatestBlockNumber(ctx); err != nil {
+			log.Printf("⚠️ Failed to get latest block: %v", err)
+		} else {
+			head = &latest
+		}
+	}
+
+	activeChainID := config.AppConfig.GetActiveChainID()
+	result := make([]CheckpointLag, 0, len(checkpoints))
+	for _, checkpoint := range checkpoints {
+		item := CheckpointLag{SyncCheckpoint: checkpoint}
+		if head != nil && checkpoint.ChainID == activeChainID {
+			lag := uint64(0)
+			if *head > checkpoint.LastBlock {
+				lag = *head - checkpoint.LastBlock
+			}
+			item.HeadBlock = head
+			item.Lag = &lag
+		}
+		result = append(result, item)
+	}
+
+	return result, nil
+}
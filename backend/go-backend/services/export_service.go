@@ -0,0 +1,215 @@
+package services
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"hackathon-backend/config"
+	"hackathon-backend/models"
+	"hackathon-backend/repositories"
+)
+
+// 导出的数据集
+const (
+	ExportParticipants = "participants"
+	ExportSponsors     = "sponsors"
+	ExportTickets      = "tickets"
+)
+
+// 导出格式
+const (
+	ExportFormatCSV  = "csv"
+	ExportFormatXLSX = "xlsx"
+)
+
+// exportBatchSize 每批从数据库读取的行数，每批写完后刷新到客户端
+const exportBatchSize = 500
+
+var ErrInvalidExport = errors.New("invalid export")
+
+// exportRowWriter CSV 和 XLSX 共用的行写入接口
+type exportRowWriter interface {
+	WriteRow(cells []string) error
+	Flush() error
+	Close() error
+}
+
+// Export 一次导出：先确定响应头，再调用 Write 流式写入
+type Export struct {
+	Dataset     string
+	Format      string
+	ContentType string
+	Filename    string
+
+	event *models.Event
+	repo  *repositories.ExportRepository
+}
+
+// ExportService 导出活动的参与者、赞助和门票
+type ExportService struct {
+	repo *repositories.ExportRepository
+}
+
+func NewExportService(repo *repositories.ExportRepository) *ExportService {
+	return &ExportService{repo: repo}
+}
+
+// NewExport 校验数据集和格式，format 为空时导出 CSV
+func (s *ExportService) NewExport(event *models.Event, dataset string, format string) (*Export, error) {
+	switch dataset {
+	case ExportParticipants, ExportSponsors, ExportTickets:
+	default:
+		return nil, fmt.Errorf("%w: dataset must be participants, sponsors or tickets", ErrInvalidExport)
+	}
+
+	export := &Export{Dataset: dataset, Format: format, event: event, repo: s.repo}
+	switch format {
+	case "", ExportFormatCSV:
+		export.Format = ExportFormatCSV
+		export.ContentType = "text/csv; charset=utf-8"
+	case ExportFormatXLSX:
+		export.ContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	default:
+		return nil, fmt.Errorf("%w: format must be csv or xlsx", ErrInvalidExport)
+	}
+	export.Filename = fmt.Sprintf("%s-event-%s-%s.%s", event.Network, event.EventID, dataset, export.Format)
+	return export, nil
+}
+
+// Write 分批读取并写入 w，每批写完后刷新；开始写入后出错时输出已不完整，由调用方中止连接
+func (e *Export) Write(w io.Writer) error {
+	var rw exportRowWriter
+	var err error
+	switch e.Format {
+	case ExportFormatXLSX:
+		rw, err = newXLSXWriter(w, e.Dataset)
+	default:
+		rw, err = newCSVWriter(w)
+	}
+	if err != nil {
+		return err
+	}
+
+	flush := func() error {
+		if err := rw.Flush(); err != nil {
+			return err
+		}
+		if f, ok := w.(http.Flusher); ok {
+			f.Flush()
+		}
+		return nil
+	}
+
+	switch e.Dataset {
+	case ExportParticipants:
+		err = e.writeParticipants(rw, flush)
+	case ExportSponsors:
+		err = e.writeSponsors(rw, flush)
+	case ExportTickets:
+		err = e.writeTickets(rw, flush)
+	}
+	if err != nil {
+		return err
+	}
+	return rw.Close()
+}
+
+func (e *Export) writeParticipants(rw exportRowWriter, flush func() error) error {
+	if err := rw.WriteRow([]string{"name", "wallet", "registered_at", "checked_in", "check_in_time", "check_in_status"}); err != nil {
+		return err
+	}
+	return e.repo.EachParticipant(e.event, exportBatchSize, func(participants []models.Participant) error {
+		for _, p := range participants {
+			checkInTime := ""
+			if p.CheckedIn {
+				checkInTime = formatExportTime(p.CheckInTime)
+			}
+			row := []string{p.Name, p.Wallet.String(), formatExportTime(p.RegisteredAt), strconv.FormatBool(p.CheckedIn), checkInTime, p.CheckInStatus}
+			if err := rw.WriteRow(row); err != nil {
+				return err
+			}
+		}
+		return flush()
+	})
+}
+
+func (e *Export) writeSponsors(rw exportRowWriter, flush func() error) error {
+	if err := rw.WriteRow([]string{"name", "wallet", "amount", "unit", "amount_wei", "sponsored_at"}); err != nil {
+		return err
+	}
+	return e.repo.EachSponsor(e.event, exportBatchSize, func(sponsors []models.Sponsor) error {
+		for _, sp := range sponsors {
+			amount, ok := new(big.Int).SetString(sp.Amount, 10)
+			if !ok {
+				amount = new(big.Int)
+			}
+			row := []string{sp.Name, sp.Wallet.String(), formatUnits(amount, nativeTokenDecimals), config.NativeTokenSymbol(sp.Network),
+				amount.String(), formatExportTime(sp.SponsoredAt)}
+			if err := rw.WriteRow(row); err != nil {
+				return err
+			}
+		}
+		return flush()
+	})
+}
+
+func (e *Export) writeTickets(rw exportRowWriter, flush func() error) error {
+	if err := rw.WriteRow([]string{"token_id", "holder", "used", "issued_at", "contract_address"}); err != nil {
+		return err
+	}
+	return e.repo.EachTicket(e.event, exportBatchSize, func(tickets []models.NFTTicket) error {
+		for _, t := range tickets {
+			row := []string{t.TokenID, t.Holder.String(), strconv.FormatBool(t.Used), formatExportTime(t.IssuedAt), t.ContractAddress}
+			if err := rw.WriteRow(row); err != nil {
+				return err
+			}
+		}
+		return flush()
+	})
+}
+
+// formatExportTime Unix 秒格式化为 RFC 3339（UTC），0 为空
+func formatExportTime(ts int64) string {
+	if ts <= 0 {
+		return ""
+	}
+	return time.Unix(ts, 0).UTC().Format(time.RFC3339)
+}
+
+// csvWriter 写入 UTF-8 BOM 以便 Excel 正确识别中文
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func newCSVWriter(w io.Writer) (*csvWriter, error) {
+	if _, err := io.WriteString(w, "\xEF\xBB\xBF"); err != nil {
+		return nil, err
+	}
+	return &csvWriter{w: csv.NewWriter(w)}, nil
+}
+
+// WriteRow 以 = + - @ 开头的单元格加 ' 前缀，防止在表格软件中被当作公式执行
+func (c *csvWriter) WriteRow(cells []string) error {
+	for i, cell := range cells {
+		if cell != "" && strings.ContainsRune("=+-@\t\r", rune(cell[0])) {
+			cells[i] = "'" + cell
+		}
+	}
+	return c.w.Write(cells)
+}
+
+func (c *csvWriter) Flush() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func (c *csvWriter) Close() error {
+	return c.Flush()
+}
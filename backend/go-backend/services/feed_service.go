@@ -0,0 +1,330 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+
+	"hackathon-backend/config"
+	"hackathon-backend/models"
+	"hackathon-backend/repositories"
+)
+
+// 订阅源类型
+const (
+	FeedNew      = "new"      // 最近索引的活动
+	FeedUpcoming = "upcoming" // 即将开始的活动
+)
+
+// 订阅源格式
+const (
+	FeedFormatAtom = "atom"
+	FeedFormatRSS  = "rss"
+)
+
+const (
+	// feedWindow 最近创建 / 即将开始的时间范围
+	feedWindow = 30 * 24 * time.Hour
+	// feedMaxEntries 单个订阅源最多包含的活动数
+	feedMaxEntries = 50
+)
+
+var ErrInvalidFeed = errors.New("invalid feed")
+
+// FeedQuery 订阅源请求
+type FeedQuery struct {
+	Kind     string
+	Format   string
+	Network  string
+	Location string
+}
+
+// Feed 生成好的订阅源，ETag 由订阅源内容计算，内容不变时保持不变
+type Feed struct {
+	ContentType string
+	ETag        string
+	Updated     time.Time // 条目中最新的修改时间，没有条目时为零值
+
+	query   FeedQuery
+	selfURL string // 订阅源自身地址，用于 Atom id 和 self 链接
+	title   string
+	entries []models.Event
+}
+
+// FeedService 生成最近创建和即将开始的活动的 Atom / RSS 订阅源
+type FeedService struct {
+	repo *repositories.FeedRepository
+}
+
+func NewFeedService(repo *repositories.FeedRepository) *FeedService {
+	return &FeedService{repo: repo}
+}
+
+// GetFeed 读取订阅源条目并计算 ETag
+func (s *FeedService) GetFeed(query FeedQuery) (*Feed, error) {
+	feed := &Feed{query: query}
+	switch query.Format {
+	case FeedFormatAtom:
+		feed.ContentType = "application/atom+xml; charset=utf-8"
+	case FeedFormatRSS:
+		feed.ContentType = "application/rss+xml; charset=utf-8"
+	default:
+		return nil, fmt.Errorf("%w: format must be atom or rss", ErrInvalidFeed)
+	}
+
+	filter := repositories.FeedFilter{Location: query.Location}
+	if query.Network != "" {
+		chainID, ok := config.ChainIDForNetwork(query.Network)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownNetwork, query.Network)
+		}
+		filter.ChainID = chainID
+	}
+
+	now := time.Now()
+	var err error
+	switch query.Kind {
+	case FeedNew:
+		feed.title = "HackChain: new hackathons"
+		feed.entries, err = s.repo.GetEventsCreatedSince(filter, now.Add(-feedWindow), feedMaxEntries)
+	case FeedUpcoming:
+		feed.title = "HackChain: upcoming hackathons"
+		feed.entries, err = s.repo.GetEventsStartingBetween(filter, now.Unix(), now.Add(feedWindow).Unix(), feedMaxEntries)
+	default:
+		return nil, fmt.Errorf("%w: feed must be new or upcoming", ErrInvalidFeed)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if query.Network != "" {
+		feed.title += " on " + query.Network
+	}
+	if query.Location != "" {
+		feed.title += " in " + query.Location
+	}
+
+	feed.selfURL = feedSelfURL(query)
+
+	// 自身地址、条目集合或任一条目的修改时间变化时 ETag 随之变化
+	hash := sha256.New()
+	fmt.Fprintln(hash, feed.selfURL)
+	for _, e := range feed.entries {
+		fmt.Fprintf(hash, "%d|%d\n", e.ID, e.UpdatedAt.UnixNano())
+		if e.UpdatedAt.After(feed.Updated) {
+			feed.Updated = e.UpdatedAt
+		}
+	}
+	feed.ETag = `W/"` + hex.EncodeToString(hash.Sum(nil))[:32] + `"`
+	return feed, nil
+}
+
+// feedSelfURL 由配置的后端地址和规范化的过滤条件拼出订阅源地址，不依赖请求头
+func feedSelfURL(query FeedQuery) string {
+	params := url.Values{}
+	if query.Network != "" {
+		params.Set("network", query.Network)
+	}
+	if query.Location != "" {
+		params.Set("location", query.Location)
+	}
+	self := config.AppConfig.PublicAPIURL + "/api/feeds/" + query.Kind + "." + query.Format
+	if len(params) > 0 {
+		self += "?" + params.Encode()
+	}
+	return self
+}
+
+// Write 输出 Atom 或 RSS XML
+func (f *Feed) Write(w io.Writer) error {
+	var doc interface{}
+	if f.query.Format == FeedFormatAtom {
+		doc = f.atom()
+	} else {
+		doc = f.rss()
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// feedEntryID 活动条目的永久标识
+func feedEntryID(e *models.Event) string {
+	return fmt.Sprintf("tag:hackchain,2025:events/%d/%s/%s", e.ChainID, strings.ToLower(e.ContractAddress), e.EventID)
+}
+
+// feedSummary 条目摘要：时间、地点和网络
+func feedSummary(e *models.Event) string {
+	parts := []string{}
+	if e.StartTime > 0 {
+		period := time.Unix(e.StartTime, 0).UTC().Format("2006-01-02 15:04 MST")
+		if e.EndTime > e.StartTime {
+			period += " – " + time.Unix(e.EndTime, 0).UTC().Format("2006-01-02 15:04 MST")
+		}
+		parts = append(parts, period)
+	}
+	if e.Location != "" {
+		parts = append(parts, e.Location)
+	}
+	parts = append(parts, e.Network)
+	if !e.Active {
+		parts = append(parts, "closed")
+	}
+	return strings.Join(parts, " · ")
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomText struct {
+	Type string `xml:"type,attr,omitempty"`
+	Body string `xml:",chardata"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+type atomEntry struct {
+	ID        string         `xml:"id"`
+	Title     atomText       `xml:"title"`
+	Link      atomLink       `xml:"link"`
+	Published string         `xml:"published,omitempty"`
+	Updated   string         `xml:"updated"`
+	Author    *atomPerson    `xml:"author,omitempty"`
+	Category  []atomCategory `xml:"category"`
+	Summary   atomText       `xml:"summary"`
+	Content   *atomText      `xml:"content,omitempty"`
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Author  atomPerson  `xml:"author"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+func (f *Feed) atom() *atomFeed {
+	updated := f.Updated
+	if updated.IsZero() {
+		updated = time.Unix(0, 0)
+	}
+	feed := &atomFeed{
+		ID:      f.selfURL,
+		Title:   f.title,
+		Updated: updated.UTC().Format(time.RFC3339),
+		Links: []atomLink{
+			{Href: f.selfURL, Rel: "self", Type: "application/atom+xml"},
+			{Href: config.AppConfig.FrontendURL + "/events/browse", Rel: "alternate", Type: "text/html"},
+		},
+		Author:  atomPerson{Name: "HackChain"},
+		Entries: make([]atomEntry, 0, len(f.entries)),
+	}
+	for i := range f.entries {
+		e := &f.entries[i]
+		entry := atomEntry{
+			ID:       feedEntryID(e),
+			Title:    atomText{Type: "text", Body: e.Title},
+			Link:     atomLink{Href: config.AppConfig.EventURL(e.EventID), Rel: "alternate", Type: "text/html"},
+			Updated:  e.UpdatedAt.UTC().Format(time.RFC3339),
+			Category: []atomCategory{{Term: e.Network}},
+			Summary:  atomText{Type: "text", Body: feedSummary(e)},
+		}
+		if !e.CreatedAt.IsZero() {
+			entry.Published = e.CreatedAt.UTC().Format(time.RFC3339)
+		}
+		if e.Organizer != "" {
+			entry.Author = &atomPerson{Name: e.Organizer.String()}
+		}
+		if e.Description != "" {
+			entry.Content = &atomText{Type: "text", Body: e.Description}
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+	return feed
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link"`
+	GUID        rssGUID `xml:"guid"`
+	PubDate     string  `xml:"pubDate,omitempty"`
+	Category    string  `xml:"category"`
+	Description string  `xml:"description"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	TTL           int       `xml:"ttl"`
+	AtomLink      atomLink  `xml:"http://www.w3.org/2005/Atom link"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+func (f *Feed) rss() *rssFeed {
+	channel := rssChannel{
+		Title:       f.title,
+		Link:        config.AppConfig.FrontendURL + "/events/browse",
+		Description: f.title,
+		TTL:         60,
+		AtomLink:    atomLink{Href: f.selfURL, Rel: "self", Type: "application/rss+xml"},
+		Items:       make([]rssItem, 0, len(f.entries)),
+	}
+	if !f.Updated.IsZero() {
+		channel.LastBuildDate = f.Updated.UTC().Format(time.RFC1123Z)
+	}
+	for i := range f.entries {
+		e := &f.entries[i]
+		description := feedSummary(e)
+		if e.Description != "" {
+			description += "\n\n" + e.Description
+		}
+		item := rssItem{
+			Title:       e.Title,
+			Link:        config.AppConfig.EventURL(e.EventID),
+			GUID:        rssGUID{IsPermaLink: false, Value: feedEntryID(e)},
+			Category:    e.Network,
+			Description: description,
+		}
+		if !e.CreatedAt.IsZero() {
+			item.PubDate = e.CreatedAt.UTC().Format(time.RFC1123Z)
+		}
+		channel.Items = append(channel.Items, item)
+	}
+	return &rssFeed{Version: "2.0", Channel: channel}
+}
@@ -0,0 +1,32 @@
+package services
+
+import (
+	"testing"
+
+	"hackathon-backend/config"
+)
+
+func TestFeedSelfURL(t *testing.T) {
+	config.AppConfig = &config.Config{PublicAPIURL: "https://api.example"}
+
+	tests := []struct {
+		name  string
+		query FeedQuery
+		want  string
+	}{
+		{name: "no filters", query: FeedQuery{Kind: FeedNew, Format: FeedFormatAtom}, want: "https://api.example/api/feeds/new.atom"},
+		{
+			name:  "filters sorted and escaped",
+			query: FeedQuery{Kind: FeedUpcoming, Format: FeedFormatRSS, Network: "somnia", Location: "São Paulo & co"},
+			want:  "https://api.example/api/feeds/upcoming.rss?location=S%C3%A3o+Paulo+%26+co&network=somnia",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := feedSelfURL(tt.query); got != tt.want {
+				t.Errorf("feedSelfURL = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
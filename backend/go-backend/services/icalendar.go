@@ -0,0 +1,135 @@
+package services
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"hackathon-backend/config"
+	"hackathon-backend/models"
+)
+
+// icsTimeFormat RFC 5545 UTC 时间格式
+const icsTimeFormat = "20060102T150405Z"
+
+// icsRegistrationClosed 已关闭活动在 DESCRIPTION 中的说明
+const icsRegistrationClosed = "报名已截止"
+
+// icsMaxLineOctets RFC 5545 每行最多 75 个字节，超出时折行
+const icsMaxLineOctets = 75
+
+// icsWriter 输出 CRLF 换行并按字节折行的 iCalendar 内容行
+type icsWriter struct {
+	w   *bufio.Writer
+	err error
+}
+
+// line 写入一个内容行，value 需已转义
+func (w *icsWriter) line(name string, value string) {
+	if w.err != nil {
+		return
+	}
+	content := name + ":" + value
+	// 在 UTF-8 字符边界处折行，续行以空格开头，空格计入长度
+	limit := icsMaxLineOctets
+	for len(content) > limit && w.err == nil {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(content[cut]) {
+			cut--
+		}
+		_, w.err = w.w.WriteString(content[:cut] + "\r\n ")
+		content = content[cut:]
+		limit = icsMaxLineOctets - 1
+	}
+	if w.err == nil {
+		_, w.err = w.w.WriteString(content + "\r\n")
+	}
+}
+
+// icsEscape 按 RFC 5545 TEXT 规则转义
+func icsEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`).Replace(s)
+}
+
+func icsTime(t time.Time) string {
+	return t.UTC().Format(icsTimeFormat)
+}
+
+// Calendar 一个 iCalendar 日历
+type Calendar struct {
+	Name   string
+	Events []models.Event
+}
+
+// Write 输出 RFC 5545 日历；未设置开始时间的活动跳过
+func (c *Calendar) Write(out io.Writer) error {
+	w := &icsWriter{w: bufio.NewWriter(out)}
+	now := time.Now()
+
+	w.line("BEGIN", "VCALENDAR")
+	w.line("VERSION", "2.0")
+	w.line("PRODID", "-//HackChain//Hackathon Events//ZH")
+	w.line("CALSCALE", "GREGORIAN")
+	w.line("METHOD", "PUBLISH")
+	w.line("X-WR-CALNAME", icsEscape(c.Name))
+	// 订阅的日历应用按该间隔刷新，活动修改或关闭后随之更新
+	w.line("REFRESH-INTERVAL;VALUE=DURATION", "PT1H")
+	w.line("X-PUBLISHED-TTL", "PT1H")
+
+	for i := range c.Events {
+		e := &c.Events[i]
+		if e.StartTime <= 0 {
+			continue
+		}
+		start := time.Unix(e.StartTime, 0)
+		end := start
+		if e.EndTime > e.StartTime {
+			end = time.Unix(e.EndTime, 0)
+		}
+		updated := e.UpdatedAt
+		if updated.IsZero() {
+			updated = now
+		}
+
+		w.line("BEGIN", "VEVENT")
+		w.line("UID", fmt.Sprintf("%d-%s-%s@hackchain", e.ChainID, strings.ToLower(e.ContractAddress), e.EventID))
+		w.line("DTSTAMP", icsTime(updated))
+		if !e.CreatedAt.IsZero() {
+			w.line("CREATED", icsTime(e.CreatedAt))
+		}
+		w.line("LAST-MODIFIED", icsTime(updated))
+		w.line("DTSTART", icsTime(start))
+		w.line("DTEND", icsTime(end))
+		w.line("SUMMARY", icsEscape(e.Title))
+		if description := icsDescription(e, now); description != "" {
+			w.line("DESCRIPTION", icsEscape(description))
+		}
+		if e.Location != "" {
+			w.line("LOCATION", icsEscape(e.Location))
+		}
+		w.line("URL", config.AppConfig.EventURL(e.EventID))
+		w.line("CATEGORIES", icsEscape(e.Network))
+		w.line("STATUS", "CONFIRMED")
+		w.line("END", "VEVENT")
+	}
+
+	w.line("END", "VCALENDAR")
+	if w.err != nil {
+		return w.err
+	}
+	return w.w.Flush()
+}
+
+// icsDescription 活动描述；结束前被组织者关闭（EventClosed 只截止报名，活动照常举行）时附加说明
+func icsDescription(e *models.Event, now time.Time) string {
+	if e.Active || (e.EndTime > 0 && e.EndTime <= now.Unix()) {
+		return e.Description
+	}
+	if e.Description == "" {
+		return icsRegistrationClosed
+	}
+	return e.Description + "\n\n" + icsRegistrationClosed
+}
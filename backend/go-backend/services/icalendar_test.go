@@ -0,0 +1,60 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"hackathon-backend/config"
+	"hackathon-backend/models"
+)
+
+func TestCalendarClosedEventStaysConfirmed(t *testing.T) {
+	config.AppConfig = &config.Config{FrontendURL: "https://app.example"}
+	now := time.Now()
+
+	tests := []struct {
+		name            string
+		active          bool
+		end             time.Time
+		wantDescription string
+	}{
+		{name: "open", active: true, end: now.Add(48 * time.Hour), wantDescription: "DESCRIPTION:Bring a laptop"},
+		{name: "closed before end", end: now.Add(48 * time.Hour), wantDescription: `DESCRIPTION:Bring a laptop\n\n` + icsRegistrationClosed},
+		{name: "closed after end", end: now.Add(-time.Hour), wantDescription: "DESCRIPTION:Bring a laptop"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calendar := &Calendar{Name: "test", Events: []models.Event{{
+				EventID:     "1",
+				Title:       "Demo Day",
+				Description: "Bring a laptop",
+				StartTime:   tt.end.Add(-24 * time.Hour).Unix(),
+				EndTime:     tt.end.Unix(),
+				Active:      tt.active,
+			}}}
+			var out strings.Builder
+			if err := calendar.Write(&out); err != nil {
+				t.Fatal(err)
+			}
+			// 展开折行后逐行比较
+			lines := strings.Split(strings.ReplaceAll(out.String(), "\r\n ", ""), "\r\n")
+			if !containsLine(lines, "STATUS:CONFIRMED") {
+				t.Errorf("missing STATUS:CONFIRMED in\n%s", out.String())
+			}
+			if !containsLine(lines, tt.wantDescription) {
+				t.Errorf("missing %q in\n%s", tt.wantDescription, out.String())
+			}
+		})
+	}
+}
+
+func containsLine(lines []string, want string) bool {
+	for _, line := range lines {
+		if line == want {
+			return true
+		}
+	}
+	return false
+}
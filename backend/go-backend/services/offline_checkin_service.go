@@ -0,0 +1,433 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"time"
+
+	"hackathon-backend/config"
+	"hackathon-backend/models"
+	"hackathon-backend/repositories"
+
+	"gorm.io/gorm"
+)
+
+// OfflineUploadMaxRecords 单次上传的最大离线签到条数
+const OfflineUploadMaxRecords = 500
+
+// offlineClockSkew 允许设备时钟超前服务器的时间
+const offlineClockSkew = 5 * time.Minute
+
+const offlineSettleBatchSize = 50
+
+// 离线签到上传条目结果
+const (
+	OfflineItemAccepted  = "accepted"
+	OfflineItemDuplicate = "duplicate"
+	OfflineItemRejected  = "rejected"
+)
+
+var (
+	ErrInvalidOfflineRecord = errors.New("invalid offline check-in record")
+	ErrDeviceNotAuthorized  = errors.New("device is not authorized for this event")
+	ErrDeviceNotFound       = errors.New("check-in device not found")
+)
+
+// OfflineCheckInRecord 扫码设备离线记录并用设备密钥签名的签到
+type OfflineCheckInRecord struct {
+	ChainID     uint64 `json:"chain_id"`
+	EventID     string `json:"event_id"`
+	Participant string `json:"participant"`
+	TokenID     string `json:"token_id,omitempty"`
+	ScannedAt   int64  `json:"scanned_at"`
+	Nonce       string `json:"nonce"`
+	Device      string `json:"device"`
+	Signature   string `json:"signature"`
+}
+
+// Message 设备对该文本执行 personal_sign
+func (r *OfflineCheckInRecord) Message() string {
+	participant := models.Address(r.Participant).Canonical()
+	return fmt.Sprintf("HackChain Offline Check-In\nChain ID: %d\nEvent ID: %s\nParticipant: %s\nToken ID: %s\nScanned At: %d\nNonce: %s",
+		r.ChainID, r.EventID, participant, r.TokenID, r.ScannedAt, r.Nonce)
+}
+
+// OfflineUploadItem 单条离线签到的处理结果
+type OfflineUploadItem struct {
+	Index       int                    `json:"index"`
+	Participant string                 `json:"participant"`
+	Status      string                 `json:"status"` // accepted, duplicate, rejected
+	CheckIn     *models.OfflineCheckIn `json:"check_in,omitempty"`
+	Error       string                 `json:"error,omitempty"`
+}
+
+// OfflineUploadResult 离线签到上传结果
+type OfflineUploadResult struct {
+	Accepted  int                 `json:"accepted"`
+	Duplicate int                 `json:"duplicate"`
+	Rejected  int                 `json:"rejected"`
+	Items     []OfflineUploadItem `json:"items"`
+}
+
+// OfflineCheckInService 接收扫码设备离线记录的签到：先在 participants 中标记为临时签到，
+// 再通过中继器上链，并根据链上 ParticipantCheckedIn/TicketUsed 日志结算或标记冲突
+type OfflineCheckInService struct {
+	repo           *repositories.CheckInRepository
+	eventRepo      *repositories.EventRepository
+	relayerService *RelayerService
+	chainID        uint64
+	settleInterval time.Duration
+}
+
+func NewOfflineCheckInService(repo *repositories.CheckInRepository, eventRepo *repositories.EventRepository, relayerService *RelayerService) *OfflineCheckInService {
+	settleInterval := time.Duration(config.AppConfig.OfflineSettleInterval) * time.Second
+	if settleInterval <= 0 {
+		settleInterval = 15 * time.Second
+	}
+
+	return &OfflineCheckInService{
+		repo:           repo,
+		eventRepo:      eventRepo,
+		relayerService: relayerService,
+		chainID:        config.AppConfig.GetActiveChainID(),
+		settleInterval: settleInterval,
+	}
+}
+
+// AuthorizeDevice 授权扫码设备密钥，调用方需已完成活动管理权限校验
+func (s *OfflineCheckInService) AuthorizeDevice(event *models.Event, address models.Address, label string, authorizedBy string) (*models.CheckInDevice, error) {
+	device := &models.CheckInDevice{
+		ChainID:      event.ChainID,
+		EventID:      event.EventID,
+		Address:      address,
+		Label:        label,
+		AuthorizedBy: authorizedBy,
+	}
+	if err := s.repo.SaveDevice(device); err != nil {
+		return nil, err
+	}
+
+	log.Printf("📱 Authorized check-in device %s for event %s (by %s)", device.Address, event.EventID, authorizedBy)
+	return s.repo.FindDevice(device.ChainID, device.EventID, device.Address)
+}
+
+// GetDevices 获取活动的扫码设备
+func (s *OfflineCheckInService) GetDevices(event *models.Event) ([]models.CheckInDevice, error) {
+	return s.repo.GetDevices(event.ChainID, event.EventID)
+}
+
+// RevokeDevice 撤销扫码设备，撤销前扫描的记录仍可上传
+func (s *OfflineCheckInService) RevokeDevice(event *models.Event, address models.Address) error {
+	revoked, err := s.repo.RevokeDevice(event.ChainID, event.EventID, address, time.Now())
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return ErrDeviceNotFound
+	}
+	return nil
+}
+
+// ListOfflineCheckIns 获取活动的离线签到记录，status 为空时返回全部
+func (s *OfflineCheckInService) ListOfflineCheckIns(event *models.Event, status string) ([]models.OfflineCheckIn, error) {
+	return s.repo.GetOfflineCheckIns(event.ChainID, event.EventID, status)
+}
+
+// Upload 逐条校验设备签名并记录离线签到，参与者立即显示为临时签到；单条失败不影响其他条目
+func (s *OfflineCheckInService) Upload(records []OfflineCheckInRecord) (*OfflineUploadResult, error) {
+	if len(records) == 0 || len(records) > OfflineUploadMaxRecords {
+		return nil, fmt.Errorf("%w: upload must contain 1-%d records", ErrInvalidOfflineRecord, OfflineUploadMaxRecords)
+	}
+
+	result := &OfflineUploadResult{Items: make([]OfflineUploadItem, len(records))}
+	for i := range records {
+		item := OfflineUploadItem{Index: i, Participant: records[i].Participant}
+
+		checkIn, inserted, err := s.record(&records[i])
+		switch {
+		case err != nil && !isOfflineValidationError(err):
+			return nil, err
+		case err != nil:
+			item.Status = OfflineItemRejected
+			item.Error = err.Error()
+			result.Rejected++
+		case !inserted:
+			item.Status = OfflineItemDuplicate
+			item.CheckIn = checkIn
+			result.Duplicate++
+		default:
+			item.Status = OfflineItemAccepted
+			item.CheckIn = checkIn
+			result.Accepted++
+		}
+		result.Items[i] = item
+	}
+
+	log.Printf("📴 Offline check-in upload: %d accepted, %d duplicate, %d rejected", result.Accepted, result.Duplicate, result.Rejected)
+	return result, nil
+}
+
+// record 校验并保存单条离线签到，参与者已有离线签到时返回已有记录和 false
+func (s *OfflineCheckInService) record(rec *OfflineCheckInRecord) (*models.OfflineCheckIn, bool, error) {
+	if rec.ChainID != s.chainID {
+		return nil, false, fmt.Errorf("%w: chain %d is not the active network", ErrInvalidOfflineRecord, rec.ChainID)
+	}
+	wallet, participantErr := models.ParseAddress(rec.Participant)
+	device, deviceErr := models.ParseAddress(rec.Device)
+	if rec.EventID == "" || participantErr != nil || deviceErr != nil ||
+		len(rec.Nonce) < 16 || len(rec.Nonce) > 64 || rec.Signature == "" || rec.ScannedAt <= 0 {
+		return nil, false, fmt.Errorf("%w: missing or malformed fields", ErrInvalidOfflineRecord)
+	}
+	if rec.TokenID != "" {
+		if id, ok := new(big.Int).SetString(rec.TokenID, 10); !ok || id.Sign() < 0 {
+			return nil, false, fmt.Errorf("%w: invalid token id", ErrInvalidOfflineRecord)
+		}
+	}
+	if time.Unix(rec.ScannedAt, 0).After(time.Now().Add(offlineClockSkew)) {
+		return nil, false, fmt.Errorf("%w: scanned_at is in the future", ErrInvalidOfflineRecord)
+	}
+
+	recovered, err := RecoverPersonalSigner(rec.Message(), rec.Signature)
+	if err != nil || recovered != device.Common() {
+		return nil, false, fmt.Errorf("%w: signature mismatch", ErrInvalidOfflineRecord)
+	}
+
+	authorized, err := s.repo.FindDevice(rec.ChainID, rec.EventID, device)
+	if err != nil {
+		return nil, false, err
+	}
+	if authorized == nil || (authorized.RevokedAt != nil && rec.ScannedAt >= authorized.RevokedAt.Unix()) {
+		return nil, false, ErrDeviceNotAuthorized
+	}
+
+	participant, err := s.eventRepo.FindParticipant(rec.ChainID, rec.EventID, wallet)
+	if err != nil {
+		return nil, false, err
+	}
+	if participant == nil {
+		return nil, false, ErrParticipantNotFound
+	}
+	if participant.CheckedIn && participant.CheckInStatus != models.CheckInStatusProvisional {
+		return nil, false, ErrAlreadyCheckedIn
+	}
+
+	if rec.TokenID != "" && rec.TokenID != "0" {
+		ticket, err := s.eventRepo.FindTicketOnChain(rec.ChainID, rec.TokenID)
+		if err != nil {
+			return nil, false, err
+		}
+		switch {
+		case ticket == nil:
+			return nil, false, ErrTicketNotFound
+		case ticket.EventID != rec.EventID:
+			return nil, false, fmt.Errorf("%w: ticket belongs to event %s", ErrTicketUnusable, ticket.EventID)
+		case !ticket.Holder.Equal(participant.Wallet):
+			return nil, false, fmt.Errorf("%w: ticket is not held by the participant", ErrTicketUnusable)
+		case ticket.Used:
+			return nil, false, fmt.Errorf("%w: ticket already used", ErrTicketUnusable)
+		}
+	}
+
+	checkIn := &models.OfflineCheckIn{
+		ChainID:     rec.ChainID,
+		EventID:     rec.EventID,
+		Participant: participant.Wallet,
+		TokenID:     rec.TokenID,
+		Device:      device,
+		ScannedAt:   rec.ScannedAt,
+		Nonce:       rec.Nonce,
+		Signature:   rec.Signature,
+		Status:      models.OfflineCheckInProvisional,
+	}
+	inserted, err := s.repo.CreateOfflineCheckIn(checkIn)
+	if err != nil {
+		return nil, false, err
+	}
+	if !inserted {
+		// 多台设备扫描同一参与者或设备重复上传，保留第一条
+		existing, err := s.repo.FindOfflineCheckIn(rec.ChainID, rec.EventID, participant.Wallet)
+		if err != nil {
+			return nil, false, err
+		}
+		if existing == nil {
+			return nil, false, fmt.Errorf("%w: nonce already used", ErrInvalidOfflineRecord)
+		}
+		return existing, false, nil
+	}
+
+	if _, err := s.eventRepo.SetProvisionalCheckIn(participant.ID, rec.ScannedAt); err != nil {
+		return nil, false, err
+	}
+	return checkIn, true, nil
+}
+
+// isOfflineValidationError 是否为单条记录的校验错误
+func isOfflineValidationError(err error) bool {
+	for _, target := range []error{ErrInvalidOfflineRecord, ErrDeviceNotAuthorized, ErrParticipantNotFound, ErrAlreadyCheckedIn, ErrTicketNotFound, ErrTicketUnusable} {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// Run 定时将临时签到交给中继器上链，并根据中继任务结果标记冲突。
+// 中继器未启用或活动未委托时记录保持 provisional，等待组织者自行提交后由链上日志结算
+func (s *OfflineCheckInService) Run(ctx context.Context) {
+	if !s.relayerService.Status().Enabled {
+		log.Println("📴 Relayer disabled, offline check-ins settle from on-chain logs only")
+		return
+	}
+
+	log.Printf("📴 Offline check-in settlement started (interval %s)", s.settleInterval)
+
+	ticker := time.NewTicker(s.settleInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.submitProvisional(); err != nil {
+			log.Printf("❌ Offline check-in submit failed: %v", err)
+		}
+		if err := s.checkSubmitted(); err != nil {
+			log.Printf("❌ Offline check-in status check failed: %v", err)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// submitProvisional 为委托给中继器的活动创建中继签到任务
+func (s *OfflineCheckInService) submitProvisional() error {
+	events, err := s.eventRepo.GetEventsByOrganizer(models.Address(s.relayerService.Status().Address))
+	if err != nil {
+		return err
+	}
+	eventsByID := make(map[string]*models.Event)
+	var eventIDs []string
+	for i := range events {
+		if events[i].ChainID == s.chainID {
+			eventsByID[events[i].EventID] = &events[i]
+			eventIDs = append(eventIDs, events[i].EventID)
+		}
+	}
+	if len(eventIDs) == 0 {
+		return nil
+	}
+
+	checkIns, err := s.repo.GetOfflineCheckInsByStatus(s.chainID, models.OfflineCheckInProvisional, eventIDs, offlineSettleBatchSize)
+	if err != nil {
+		return err
+	}
+
+	for i := range checkIns {
+		checkIn := &checkIns[i]
+		job, err := s.relayerService.EnqueueCheckIn(eventsByID[checkIn.EventID], RelayCheckInInput{
+			EventID:     checkIn.EventID,
+			Participant: checkIn.Participant.String(),
+			TokenID:     checkIn.TokenID,
+		}, checkIn.Device.String())
+		switch {
+		case errors.Is(err, ErrRelayPending):
+			// 组织者已手动提交中继签到，等待链上日志结算
+			continue
+		case err != nil && isRelayValidationError(err):
+			if err := s.markConflict(checkIn, err.Error()); err != nil {
+				return err
+			}
+			continue
+		case err != nil:
+			return fmt.Errorf("offline check-in %d: %w", checkIn.ID, err)
+		}
+
+		checkIn.Status = models.OfflineCheckInSubmitted
+		checkIn.RelayJobID = &job.ID
+		if err := s.repo.UpdateOfflineCheckIn(checkIn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkSubmitted 中继交易失败时标记冲突；成功的由 ParticipantCheckedIn 日志结算
+func (s *OfflineCheckInService) checkSubmitted() error {
+	checkIns, err := s.repo.GetOfflineCheckInsByStatus(s.chainID, models.OfflineCheckInSubmitted, nil, offlineSettleBatchSize)
+	if err != nil {
+		return err
+	}
+
+	for i := range checkIns {
+		checkIn := &checkIns[i]
+		if checkIn.RelayJobID == nil {
+			continue
+		}
+		job, err := s.relayerService.GetCheckIn(*checkIn.RelayJobID)
+		if err != nil {
+			return err
+		}
+		if job.Status != models.RelayStatusFailed {
+			continue
+		}
+		if err := s.markConflict(checkIn, fmt.Sprintf("relayed check-in failed: %s", job.Error)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// markConflict 标记离线签到无法上链，并撤销参与者的临时签到
+func (s *OfflineCheckInService) markConflict(checkIn *models.OfflineCheckIn, reason string) error {
+	return s.eventRepo.GetDB().Transaction(func(tx *gorm.DB) error {
+		return markOfflineConflict(repositories.NewCheckInRepository(tx), s.eventRepo.WithTx(tx), checkIn, reason)
+	})
+}
+
+// markOfflineConflict 在给定事务中标记冲突并撤销临时签到
+func markOfflineConflict(repo *repositories.CheckInRepository, eventRepo *repositories.EventRepository, checkIn *models.OfflineCheckIn, reason string) error {
+	checkIn.Status = models.OfflineCheckInConflict
+	checkIn.Error = reason
+	if err := repo.UpdateOfflineCheckIn(checkIn); err != nil {
+		return err
+	}
+	if err := eventRepo.ClearProvisionalCheckIn(checkIn.ChainID, checkIn.EventID, checkIn.Participant); err != nil {
+		return err
+	}
+	log.Printf("⚠️ Offline check-in %d for %s (event %s) conflicts: %s", checkIn.ID, checkIn.Participant, checkIn.EventID, reason)
+	return nil
+}
+
+// settleOfflineCheckIn 收到 ParticipantCheckedIn 时结算参与者的离线签到，在日志事务中调用
+func settleOfflineCheckIn(repo *repositories.EventRepository, chainID uint64, eventID string, participant models.Address, txHash string) error {
+	checkIn, err := repositories.NewCheckInRepository(repo.GetDB()).SettleOfflineCheckIn(chainID, eventID, participant, txHash)
+	if err != nil {
+		return fmt.Errorf("failed to settle offline check-in: %w", err)
+	}
+	if checkIn != nil {
+		log.Printf("🧾 Settled offline check-in %d for %s (event %s), tx %s", checkIn.ID, participant, eventID, txHash)
+	}
+	return nil
+}
+
+// reconcileOfflineTicket 收到 TicketUsed 时，引用该门票但参与者不是持有者的离线签到已无法上链，标记为冲突；在日志事务中调用
+func reconcileOfflineTicket(repo *repositories.EventRepository, ticket *models.NFTTicket) error {
+	checkInRepo := repositories.NewCheckInRepository(repo.GetDB())
+	checkIns, err := checkInRepo.GetUnsettledOfflineCheckInsByToken(ticket.ChainID, ticket.EventID, ticket.TokenID)
+	if err != nil {
+		return fmt.Errorf("failed to load offline check-ins for ticket: %w", err)
+	}
+	for i := range checkIns {
+		if checkIns[i].Participant.Equal(ticket.Holder) {
+			continue
+		}
+		if err := markOfflineConflict(checkInRepo, repo, &checkIns[i], fmt.Sprintf("ticket %s was used on chain by %s", ticket.TokenID, ticket.Holder)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
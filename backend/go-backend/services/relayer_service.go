@@ -0,0 +1,482 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"sync"
+	"time"
+
+	"hackathon-backend/blockchain"
+	"hackathon-backend/config"
+	"hackathon-backend/models"
+	"hackathon-backend/repositories"
+)
+
+const relayBatchSize = 20
+
+// RelayBatchMaxItems 单次批量签到的最大条数
+const RelayBatchMaxItems = 200
+
+// 批量签到条目状态
+const (
+	RelayItemQueued   = "queued"
+	RelayItemRejected = "rejected"
+)
+
+var (
+	ErrRelayerDisabled     = errors.New("relayer is not enabled")
+	ErrInvalidRelayRequest = errors.New("invalid relay request")
+	ErrNotDelegated        = errors.New("event organizer has not delegated check-ins to the relayer")
+	ErrRelayJobNotFound    = errors.New("relay job not found")
+	ErrParticipantNotFound = errors.New("participant not found")
+	ErrAlreadyCheckedIn    = errors.New("participant already checked in")
+	ErrRelayPending        = errors.New("a relayed check-in for this participant is already pending")
+)
+
+// RelayCheckInInput 中继签到请求
+type RelayCheckInInput struct {
+	EventID     string
+	Participant string
+	TokenID     string
+}
+
+// RelayBatchItem 批量签到中单条的结果
+type RelayBatchItem struct {
+	Index       int    `json:"index"`
+	Participant string `json:"participant"`
+	TokenID     string `json:"token_id,omitempty"`
+	Status      string `json:"status"` // queued, rejected
+	JobID       *uint  `json:"job_id,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+// RelayBatchResult 批量签到结果
+type RelayBatchResult struct {
+	BatchID  string           `json:"batch_id"`
+	Accepted int              `json:"accepted"`
+	Rejected int              `json:"rejected"`
+	Items    []RelayBatchItem `json:"items"`
+}
+
+// RelayerStatus 中继器状态
+type RelayerStatus struct {
+	Enabled bool   `json:"enabled"`
+	Address string `json:"address,omitempty"` // 组织者需用该地址创建活动才能使用中继签到
+	ChainID uint64 `json:"chain_id,omitempty"`
+}
+
+// RelayerService 管理中继签到队列：按顺序提交交易，并根据交易管理器的记录跟踪结果
+type RelayerService struct {
+	repo         *repositories.RelayRepository
+	eventRepo    *repositories.EventRepository
+	relayer      *blockchain.Relayer // 未启用时为 nil
+	chainID      uint64
+	pollInterval time.Duration
+
+	// 保证交易按 nonce 顺序提交
+	mu sync.Mutex
+	// 唤醒提交协程
+	wake chan struct{}
+}
+
+func NewRelayerService(repo *repositories.RelayRepository, eventRepo *repositories.EventRepository, relayer *blockchain.Relayer) *RelayerService {
+	pollInterval := time.Duration(config.AppConfig.RelayerPollInterval) * time.Second
+	if pollInterval <= 0 {
+		pollInterval = 3 * time.Second
+	}
+
+	return &RelayerService{
+		repo:         repo,
+		eventRepo:    eventRepo,
+		relayer:      relayer,
+		chainID:      config.AppConfig.GetActiveChainID(),
+		pollInterval: pollInterval,
+		wake:         make(chan struct{}, 1),
+	}
+}
+
+// Status 获取中继器状态
+func (s *RelayerService) Status() RelayerStatus {
+	if s.relayer == nil {
+		return RelayerStatus{}
+	}
+	return RelayerStatus{Enabled: true, Address: s.relayer.Address().Hex(), ChainID: s.chainID}
+}
+
+// EnqueueCheckIn 校验请求后加入签到队列，调用方需已完成活动管理权限校验
+func (s *RelayerService) EnqueueCheckIn(event *models.Event, input RelayCheckInInput, requestedBy string) (*models.RelayedCheckIn, error) {
+	if err := s.checkDelegated(event); err != nil {
+		return nil, err
+	}
+
+	job, err := s.validateCheckIn(event, input)
+	if err != nil {
+		return nil, err
+	}
+	job.RequestedBy = requestedBy
+	if err := s.repo.CreateCheckIn(job); err != nil {
+		return nil, err
+	}
+
+	s.notify()
+	return job, nil
+}
+
+// EnqueueBatch 逐条校验批量签到并将通过的条目加入队列，返回每条的结果。
+// 合约的 checkInParticipant 校验 msg.sender 为组织者，无法通过 Multicall 合约聚合，
+// 因此每位参与者仍是一笔交易，由中继器以连续 nonce 依次广播，通常会被打包进同一个或相邻区块
+func (s *RelayerService) EnqueueBatch(event *models.Event, items []RelayCheckInInput, requestedBy string) (*RelayBatchResult, error) {
+	if err := s.checkDelegated(event); err != nil {
+		return nil, err
+	}
+	if len(items) == 0 || len(items) > RelayBatchMaxItems {
+		return nil, fmt.Errorf("%w: batch must contain 1-%d items", ErrInvalidRelayRequest, RelayBatchMaxItems)
+	}
+
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, err
+	}
+	result := &RelayBatchResult{BatchID: hex.EncodeToString(buf), Items: make([]RelayBatchItem, len(items))}
+
+	var jobs []models.RelayedCheckIn
+	var jobIndexes []int
+	seen := map[models.Address]int{}
+	for i, input := range items {
+		item := RelayBatchItem{Index: i, Participant: input.Participant, TokenID: input.TokenID, Status: RelayItemRejected}
+
+		job, err := s.validateCheckIn(event, input)
+		switch {
+		case err == nil:
+			if first, dup := seen[job.Participant]; dup {
+				item.Error = fmt.Sprintf("duplicate of item %d", first)
+				break
+			}
+			seen[job.Participant] = i
+			job.RequestedBy = requestedBy
+			job.BatchID = result.BatchID
+			jobs = append(jobs, *job)
+			jobIndexes = append(jobIndexes, i)
+		case isRelayValidationError(err):
+			item.Error = err.Error()
+		default:
+			return nil, err
+		}
+		result.Items[i] = item
+	}
+
+	if err := s.repo.CreateCheckIns(jobs); err != nil {
+		return nil, err
+	}
+	for j, i := range jobIndexes {
+		id := jobs[j].ID
+		result.Items[i].Participant = jobs[j].Participant.String()
+		result.Items[i].Status = RelayItemQueued
+		result.Items[i].JobID = &id
+	}
+	result.Accepted = len(jobs)
+	result.Rejected = len(items) - len(jobs)
+
+	if len(jobs) > 0 {
+		s.notify()
+	}
+	log.Printf("🛰️ Relay batch %s for event %s: %d queued, %d rejected", result.BatchID, event.EventID, result.Accepted, result.Rejected)
+	return result, nil
+}
+
+// GetBatch 获取批量签到中的所有任务
+func (s *RelayerService) GetBatch(batchID string) ([]models.RelayedCheckIn, error) {
+	jobs, err := s.repo.GetCheckInsByBatch(batchID)
+	if err != nil {
+		return nil, err
+	}
+	if len(jobs) == 0 {
+		return nil, ErrRelayJobNotFound
+	}
+	return jobs, nil
+}
+
+// checkDelegated 中继器已启用且活动组织者为委托地址
+func (s *RelayerService) checkDelegated(event *models.Event) error {
+	if s.relayer == nil {
+		return ErrRelayerDisabled
+	}
+	if !event.Organizer.Equal(models.AddressFrom(s.relayer.Address())) {
+		return ErrNotDelegated
+	}
+	return nil
+}
+
+// validateCheckIn 根据已索引的 Participant 和 NFTTicket 校验签到请求，返回待入队的任务
+func (s *RelayerService) validateCheckIn(event *models.Event, input RelayCheckInInput) (*models.RelayedCheckIn, error) {
+	participant, err := models.ParseAddress(input.Participant)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRelayRequest, err)
+	}
+	if input.TokenID != "" {
+		if id, ok := new(big.Int).SetString(input.TokenID, 10); !ok || id.Sign() < 0 {
+			return nil, fmt.Errorf("%w: invalid token id", ErrInvalidRelayRequest)
+		}
+	}
+
+	existing, err := s.eventRepo.FindParticipant(event.ChainID, event.EventID, participant)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, ErrParticipantNotFound
+	}
+	// 离线签到的临时状态仍需上链
+	if existing.CheckedIn && existing.CheckInStatus != models.CheckInStatusProvisional {
+		return nil, ErrAlreadyCheckedIn
+	}
+
+	if input.TokenID != "" && input.TokenID != "0" {
+		ticket, err := s.eventRepo.FindTicketOnChain(event.ChainID, input.TokenID)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case ticket == nil:
+			return nil, ErrTicketNotFound
+		case ticket.EventID != event.EventID:
+			return nil, fmt.Errorf("%w: ticket belongs to event %s", ErrTicketUnusable, ticket.EventID)
+		case !ticket.Holder.Equal(participant):
+			return nil, fmt.Errorf("%w: ticket is not held by the participant", ErrTicketUnusable)
+		case ticket.Used:
+			return nil, fmt.Errorf("%w: ticket already used", ErrTicketUnusable)
+		}
+	}
+
+	pending, err := s.repo.FindPendingCheckIn(event.ChainID, event.EventID, participant)
+	if err != nil {
+		return nil, err
+	}
+	if pending != nil {
+		return nil, fmt.Errorf("%w (job %d)", ErrRelayPending, pending.ID)
+	}
+
+	return &models.RelayedCheckIn{
+		ChainID:     event.ChainID,
+		EventID:     event.EventID,
+		Participant: participant,
+		TokenID:     input.TokenID,
+		Relayer:     s.relayer.Address().Hex(),
+		Status:      models.RelayStatusQueued,
+	}, nil
+}
+
+// isRelayValidationError 是否为单条请求的校验错误（批量时记为该条被拒绝，而不是整批失败）
+func isRelayValidationError(err error) bool {
+	for _, target := range []error{ErrInvalidRelayRequest, ErrParticipantNotFound, ErrAlreadyCheckedIn, ErrRelayPending, ErrTicketNotFound, ErrTicketUnusable} {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetCheckIn 获取中继签到任务
+func (s *RelayerService) GetCheckIn(id uint) (*models.RelayedCheckIn, error) {
+	job, err := s.repo.GetCheckIn(id)
+	if err != nil {
+		return nil, err
+	}
+	if job == nil {
+		return nil, ErrRelayJobNotFound
+	}
+	return job, nil
+}
+
+// Run 定时或被唤醒时提交排队的签到交易，并同步已提交交易的状态；重启后从数据库恢复
+func (s *RelayerService) Run(ctx context.Context) {
+	if s.relayer == nil {
+		return
+	}
+
+	log.Printf("🛰️ Check-in relayer started (poll interval %s)", s.pollInterval)
+
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		s.process(ctx)
+
+		select {
+		case <-s.wake:
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// notify 唤醒提交协程
+func (s *RelayerService) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// process 执行一轮提交和回执查询
+func (s *RelayerService) process(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.submitQueued(ctx); err != nil {
+		log.Printf("❌ Relayer submit failed: %v", err)
+	}
+	if err := s.checkReceipts(); err != nil {
+		log.Printf("❌ Relayer receipt check failed: %v", err)
+	}
+}
+
+// submitQueued 按创建顺序提交排队的任务，遇到节点错误时停止本轮
+func (s *RelayerService) submitQueued(ctx context.Context) error {
+	jobs, err := s.repo.GetCheckInsByStatus(s.chainID, models.RelayStatusQueued, relayBatchSize)
+	if err != nil {
+		return err
+	}
+
+	for i := range jobs {
+		if err := s.submit(ctx, &jobs[i]); err != nil {
+			return fmt.Errorf("job %d: %w", jobs[i].ID, err)
+		}
+	}
+	return nil
+}
+
+// submit 模拟执行后通过交易管理器发送；合约会拒绝的任务直接标记为失败，不消耗 gas
+func (s *RelayerService) submit(ctx context.Context, job *models.RelayedCheckIn) error {
+	// 上次发送后、更新任务前进程退出时，交易已记录在交易管理器中，直接关联
+	reference := fmt.Sprintf("relayed_checkin:%d", job.ID)
+	record, err := s.relayer.FindTransaction(reference)
+	if err != nil {
+		return err
+	}
+	if record != nil && record.Status != models.TxStatusFailed {
+		return s.markSubmitted(job, record)
+	}
+
+	call, err := relayCheckInCall(job)
+	if err != nil {
+		return s.fail(job, err)
+	}
+
+	rpcCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	if err := s.relayer.SimulateCheckIn(rpcCtx, call); err != nil {
+		if errors.Is(err, blockchain.ErrCheckInReverted) {
+			return s.fail(job, err)
+		}
+		return err
+	}
+	record, err = s.relayer.SubmitCheckIn(rpcCtx, call, reference)
+	if err != nil {
+		if errors.Is(err, blockchain.ErrCheckInReverted) || errors.Is(err, blockchain.ErrBroadcastFailed) {
+			return s.fail(job, err)
+		}
+		return err
+	}
+
+	log.Printf("🛰️ Relayed check-in for %s (event %s): tx %s", job.Participant, job.EventID, record.TxHash)
+	return s.markSubmitted(job, record)
+}
+
+// markSubmitted 关联交易记录
+func (s *RelayerService) markSubmitted(job *models.RelayedCheckIn, record *models.ChainTransaction) error {
+	now := time.Now()
+	job.Status = models.RelayStatusSubmitted
+	job.TxID = &record.ID
+	job.TxHash = record.TxHash
+	job.Nonce = record.Nonce
+	job.SubmittedAt = &now
+	return s.repo.UpdateCheckIn(job)
+}
+
+// checkReceipts 根据交易管理器中的交易状态更新已提交的任务
+func (s *RelayerService) checkReceipts() error {
+	jobs, err := s.repo.GetCheckInsByStatus(s.chainID, models.RelayStatusSubmitted, relayBatchSize)
+	if err != nil {
+		return err
+	}
+
+	for i := range jobs {
+		job := &jobs[i]
+		if job.TxID == nil {
+			continue
+		}
+
+		record, err := s.relayer.Transaction(*job.TxID)
+		if err != nil {
+			return fmt.Errorf("job %d: %w", job.ID, err)
+		}
+		if record == nil {
+			if err := s.fail(job, errors.New("transaction record not found")); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := s.settle(job, record); err != nil {
+			return fmt.Errorf("job %d: %w", job.ID, err)
+		}
+	}
+	return nil
+}
+
+// settle 同步交易状态与哈希（加价重发后哈希会变化）
+func (s *RelayerService) settle(job *models.RelayedCheckIn, record *models.ChainTransaction) error {
+	switch record.Status {
+	case models.TxStatusPending:
+		if job.TxHash == record.TxHash {
+			return nil
+		}
+		job.TxHash = record.TxHash
+		return s.repo.UpdateCheckIn(job)
+	case models.TxStatusConfirmed:
+		job.Status = models.RelayStatusConfirmed
+		log.Printf("✅ Relayed check-in %d confirmed in block %d", job.ID, record.BlockNumber)
+	default:
+		job.Status = models.RelayStatusFailed
+		job.Error = record.Error
+		log.Printf("⚠️ Relayed check-in %d %s: %s", job.ID, record.Status, record.Error)
+	}
+	job.TxHash = record.TxHash
+	job.BlockNumber = record.BlockNumber
+	job.GasUsed = record.GasUsed
+	job.ConfirmedAt = record.ConfirmedAt
+	return s.repo.UpdateCheckIn(job)
+}
+
+// fail 将任务标记为失败
+func (s *RelayerService) fail(job *models.RelayedCheckIn, cause error) error {
+	job.Status = models.RelayStatusFailed
+	job.Error = cause.Error()
+	log.Printf("⚠️ Relayed check-in %d failed: %v", job.ID, cause)
+	return s.repo.UpdateCheckIn(job)
+}
+
+// relayCheckInCall 将任务转换为合约调用参数
+func relayCheckInCall(job *models.RelayedCheckIn) (blockchain.CheckInCall, error) {
+	eventID, ok := new(big.Int).SetString(job.EventID, 10)
+	if !ok {
+		return blockchain.CheckInCall{}, fmt.Errorf("%w: invalid event id %q", ErrInvalidRelayRequest, job.EventID)
+	}
+	call := blockchain.CheckInCall{EventID: eventID, Participant: job.Participant.Common()}
+	if job.TokenID != "" {
+		tokenID, ok := new(big.Int).SetString(job.TokenID, 10)
+		if !ok {
+			return blockchain.CheckInCall{}, fmt.Errorf("%w: invalid token id %q", ErrInvalidRelayRequest, job.TokenID)
+		}
+		call.TokenID = tokenID
+	}
+	return call, nil
+}
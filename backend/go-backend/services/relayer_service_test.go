@@ -0,0 +1,223 @@
+package services
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"hackathon-backend/blockchain"
+	"hackathon-backend/config"
+	"hackathon-backend/models"
+	"hackathon-backend/repositories"
+	"hackathon-backend/testdb"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/crypto"
+	"gorm.io/gorm"
+)
+
+// checkInStubCode 代替 Hackathon 合约的部署代码（沙箱内没有 solc）：构造函数把部署者记为组织者，
+// 运行时与 onlyEventOrganizer 一致，msg.sender 不是组织者时 revert，否则按 checkInParticipant
+// 的参数位置触发 ParticipantCheckedIn(eventId, participant)
+func checkInStubCode() []byte {
+	runtime := []byte{
+		0x33, 0x60, 0x00, 0x54, 0x14, // CALLER SLOAD(0) EQ
+		0x60, 0x0c, 0x57, // JUMPI 0x0c
+		0x60, 0x00, 0x80, 0xfd, // REVERT(0, 0)
+		0x5b,             // JUMPDEST
+		0x60, 0x24, 0x35, // CALLDATALOAD(0x24) participant
+		0x60, 0x04, 0x35, // CALLDATALOAD(0x04) eventId
+		0x7f, // PUSH32 ParticipantCheckedIn
+	}
+	runtime = append(runtime, crypto.Keccak256([]byte("ParticipantCheckedIn(uint256,address)"))...)
+	runtime = append(runtime, 0x60, 0x00, 0x80, 0xa3, 0x00) // LOG3(0, 0, sig, eventId, participant) STOP
+
+	init := []byte{
+		0x33, 0x60, 0x00, 0x55, // SSTORE(0, CALLER)
+		0x60, byte(len(runtime)), 0x80, 0x60, 0x0f, 0x60, 0x00, 0x39, // CODECOPY(0, 0x0f, len)
+		0x60, 0x00, 0xf3, // RETURN(0, len)
+	}
+	return append(init, runtime...)
+}
+
+type relayerFixture struct {
+	sim        *backends.SimulatedBackend
+	db         *gorm.DB
+	txm        *blockchain.TxManager
+	service    *RelayerService
+	event      *models.Event
+	relayerKey *ecdsa.PrivateKey
+	otherKey   *ecdsa.PrivateKey
+}
+
+func newRelayerFixture(t *testing.T) *relayerFixture {
+	t.Helper()
+	config.AppConfig = &config.Config{ActiveNetwork: "somnia", RelayerPollInterval: 1}
+
+	relayerKey, relayerAddr := newTestKey(t)
+	otherKey, otherAddr := newTestKey(t)
+	funds := new(big.Int).Mul(big.NewInt(100), big.NewInt(1e18))
+	sim := backends.NewSimulatedBackend(core.GenesisAlloc{
+		relayerAddr: {Balance: funds},
+		otherAddr:   {Balance: funds},
+	}, 30_000_000)
+	t.Cleanup(func() { sim.Close() })
+
+	db := testdb.Open(t, &models.Event{}, &models.Participant{}, &models.NFTTicket{}, &models.RelayedCheckIn{}, &models.ChainTransaction{})
+	event := &models.Event{
+		ChainID:   50312,
+		Network:   "somnia",
+		EventID:   "1",
+		Organizer: models.AddressFrom(relayerAddr),
+		Title:     "Demo Day",
+		Active:    true,
+	}
+	if err := db.Create(event).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	return &relayerFixture{
+		sim:        sim,
+		db:         db,
+		event:      event,
+		relayerKey: relayerKey,
+		otherKey:   otherKey,
+		txm: blockchain.NewTxManager(sim, repositories.NewTransactionRepository(db), big.NewInt(1337), "somnia",
+			blockchain.DefaultFeePolicy(1337), time.Second),
+	}
+}
+
+// deploy 以 key 部署签到合约并启动中继服务，合约组织者为 key 对应的地址
+func (f *relayerFixture) deploy(t *testing.T, key *ecdsa.PrivateKey) common.Address {
+	t.Helper()
+	auth, err := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	if err != nil {
+		t.Fatal(err)
+	}
+	address, _, _, err := bind.DeployContract(auth, abi.ABI{}, checkInStubCode(), f.sim)
+	if err != nil {
+		t.Fatalf("deploy: %v", err)
+	}
+	f.sim.Commit()
+
+	relayer, err := blockchain.NewRelayer(f.txm, f.relayerKey, address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.service = NewRelayerService(repositories.NewRelayRepository(f.db), repositories.NewEventRepository(f.db), relayer)
+	return address
+}
+
+// register 为活动添加已索引的参与者
+func (f *relayerFixture) register(t *testing.T) common.Address {
+	t.Helper()
+	_, wallet := newTestKey(t)
+	participant := &models.Participant{ChainID: f.event.ChainID, Network: f.event.Network, EventID: f.event.EventID, Wallet: models.AddressFrom(wallet)}
+	if err := f.db.Create(participant).Error; err != nil {
+		t.Fatal(err)
+	}
+	return wallet
+}
+
+func (f *relayerFixture) job(t *testing.T, id uint) *models.RelayedCheckIn {
+	t.Helper()
+	job, err := f.service.GetCheckIn(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return job
+}
+
+func TestRelayerServiceConfirmsCheckIn(t *testing.T) {
+	ctx := context.Background()
+	f := newRelayerFixture(t)
+	f.deploy(t, f.relayerKey)
+	participant := f.register(t)
+
+	queued, err := f.service.EnqueueCheckIn(f.event, RelayCheckInInput{EventID: "1", Participant: participant.Hex()}, "test")
+	if err != nil {
+		t.Fatalf("EnqueueCheckIn: %v", err)
+	}
+
+	f.service.process(ctx)
+	job := f.job(t, queued.ID)
+	if job.Status != models.RelayStatusSubmitted || job.TxID == nil {
+		t.Fatalf("after submit: status %s tx %v, error %q", job.Status, job.TxID, job.Error)
+	}
+
+	f.sim.Commit()
+	if err := f.txm.Check(ctx); err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	f.service.process(ctx)
+
+	job = f.job(t, queued.ID)
+	if job.Status != models.RelayStatusConfirmed || job.BlockNumber == 0 {
+		t.Fatalf("job status %s in block %d, want confirmed", job.Status, job.BlockNumber)
+	}
+	record, err := f.txm.GetTransaction(*job.TxID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record.Status != models.TxStatusConfirmed || record.Purpose != "relay_checkin" || record.TxHash != job.TxHash {
+		t.Fatalf("chain transaction %s (%s, %s), want confirmed relay_checkin %s", record.Status, record.Purpose, record.TxHash, job.TxHash)
+	}
+
+	receipt, err := f.sim.TransactionReceipt(ctx, common.HexToHash(job.TxHash))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(receipt.Logs) != 1 || receipt.Logs[0].Topics[1] != common.BigToHash(big.NewInt(1)) ||
+		receipt.Logs[0].Topics[2] != common.BytesToHash(participant.Bytes()) {
+		t.Errorf("unexpected logs: %+v", receipt.Logs)
+	}
+}
+
+func TestRelayerServiceFailsRevertingCheckIn(t *testing.T) {
+	ctx := context.Background()
+	f := newRelayerFixture(t)
+	// 链上组织者不是委托地址（数据库记录落后于链上状态），合约会拒绝签到
+	f.deploy(t, f.otherKey)
+	participant := f.register(t)
+
+	queued, err := f.service.EnqueueCheckIn(f.event, RelayCheckInInput{EventID: "1", Participant: participant.Hex()}, "test")
+	if err != nil {
+		t.Fatalf("EnqueueCheckIn: %v", err)
+	}
+	f.service.process(ctx)
+
+	job := f.job(t, queued.ID)
+	if job.Status != models.RelayStatusFailed || !strings.Contains(job.Error, blockchain.ErrCheckInReverted.Error()) {
+		t.Fatalf("job status %s error %q, want failed with %v", job.Status, job.Error, blockchain.ErrCheckInReverted)
+	}
+	if job.TxID != nil {
+		t.Errorf("reverting check-in was broadcast as tx %d", *job.TxID)
+	}
+	var count int64
+	if err := f.db.Model(&models.ChainTransaction{}).Count(&count).Error; err != nil {
+		t.Fatal(err)
+	}
+	if count != 0 {
+		t.Errorf("%d chain transactions recorded, want 0", count)
+	}
+}
+
+func TestRelayerServiceRejectsUndelegatedEvent(t *testing.T) {
+	f := newRelayerFixture(t)
+	f.deploy(t, f.relayerKey)
+	participant := f.register(t)
+
+	f.event.Organizer = models.AddressFrom(crypto.PubkeyToAddress(f.otherKey.PublicKey))
+	_, err := f.service.EnqueueCheckIn(f.event, RelayCheckInInput{EventID: "1", Participant: participant.Hex()}, "test")
+	if !errors.Is(err, ErrNotDelegated) {
+		t.Fatalf("EnqueueCheckIn error = %v, want %v", err, ErrNotDelegated)
+	}
+}
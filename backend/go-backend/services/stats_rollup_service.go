@@ -0,0 +1,396 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"hackathon-backend/config"
+	"hackathon-backend/models"
+	"hackathon-backend/repositories"
+)
+
+// 平台统计汇总周期
+const (
+	IntervalWeek  = "week"
+	IntervalMonth = "month"
+)
+
+// rollupIntervals 汇总任务计算的周期类型
+var rollupIntervals = []string{IntervalDay, IntervalWeek, IntervalMonth}
+
+// timeseriesDefaultPeriods 未指定 from 时返回的周期数
+const timeseriesDefaultPeriods = 12
+
+var (
+	ErrInvalidRollupInterval = errors.New("interval must be day, week or month")
+	ErrUnknownNetwork        = errors.New("unknown network")
+)
+
+// RollupResult 一次汇总的结果
+type RollupResult struct {
+	Since      int64     `json:"since"`   // 重新计算的起始时间（Unix 秒）
+	Periods    int       `json:"periods"` // 写入的汇总行数
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+}
+
+// TimeseriesPoint 时间序列中的一个周期
+type TimeseriesPoint struct {
+	PeriodStart        int64    `json:"t"`
+	EventsStarted      int64    `json:"events_started"`
+	ActiveParticipants int64    `json:"active_participants"`
+	Registrations      int64    `json:"registrations"`
+	CheckIns           int64    `json:"check_ins"`
+	CheckInConversion  *float64 `json:"check_in_conversion"` // 该周期开始的活动的签到人数 / 报名人数，无报名时为 null
+	SponsorCount       int64    `json:"sponsor_count"`
+	SponsorAmountWei   string   `json:"sponsor_amount_wei"`
+	SponsorAmount      string   `json:"sponsor_amount"` // 换算为原生代币单位
+}
+
+// NetworkTimeseries 单条链的时间序列
+type NetworkTimeseries struct {
+	ChainID uint64            `json:"chain_id"`
+	Network string            `json:"network"`
+	Unit    string            `json:"unit"`
+	Points  []TimeseriesPoint `json:"points"`
+}
+
+// Timeseries 平台统计时间序列
+type Timeseries struct {
+	Interval    string              `json:"interval"`
+	From        int64               `json:"from"`
+	To          int64               `json:"to"`
+	Networks    []NetworkTimeseries `json:"networks"`
+	RolledUpAt  *time.Time          `json:"rolled_up_at"` // 最近一次汇总时间，尚未汇总时为 null
+	GeneratedAt time.Time           `json:"generated_at"`
+}
+
+// StatsRollupService 定期将活动、参与者和赞助数据汇总到 stat_rollups，统计接口只读汇总表
+type StatsRollupService struct {
+	repo     *repositories.StatsRepository
+	interval time.Duration
+	lookback time.Duration
+
+	// 防止定时任务与手动触发并发执行
+	mu sync.Mutex
+}
+
+func NewStatsRollupService(repo *repositories.StatsRepository) *StatsRollupService {
+	cfg := config.AppConfig
+	return &StatsRollupService{
+		repo:     repo,
+		interval: time.Duration(cfg.StatsRollupInterval) * time.Minute,
+		lookback: time.Duration(cfg.StatsRollupLookbackDays) * 24 * time.Hour,
+	}
+}
+
+// Run 定期重新计算最近的周期；汇总表为空时先全量计算
+func (s *StatsRollupService) Run(ctx context.Context) {
+	if s.interval <= 0 {
+		log.Println("⚠️ Stats rollup disabled")
+		return
+	}
+
+	log.Printf("📊 Stats rollup every %s (lookback %s)", s.interval, s.lookback)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		since := s.LookbackStart()
+		if count, err := s.repo.CountRollups(); err == nil && count == 0 {
+			since = time.Unix(0, 0)
+		}
+		if _, err := s.Rollup(since); err != nil {
+			log.Printf("❌ Stats rollup failed: %v", err)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// LookbackStart 定时汇总重新计算的起始时间
+func (s *StatsRollupService) LookbackStart() time.Time {
+	return time.Now().Add(-s.lookback)
+}
+
+// Rollup 重新计算 since 所在周期及之后的所有汇总
+func (s *StatsRollupService) Rollup(since time.Time) (*RollupResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := &RollupResult{StartedAt: time.Now()}
+
+	// 每种周期从 since 所在周期的起点开始，数据按最早的起点加载
+	periodStarts := make(map[string]int64, len(rollupIntervals))
+	earliest := since.Unix()
+	for _, interval := range rollupIntervals {
+		start := periodStart(since, interval).Unix()
+		periodStarts[interval] = start
+		if start < earliest {
+			earliest = start
+		}
+	}
+	result.Since = earliest
+
+	rollups, err := s.aggregate(periodStarts, earliest)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.ReplaceRollups(periodStarts, rollups); err != nil {
+		return nil, fmt.Errorf("failed to save stat rollups: %w", err)
+	}
+
+	result.Periods = len(rollups)
+	result.FinishedAt = time.Now()
+	log.Printf("📊 Stats rollup since %s: %d periods in %s", time.Unix(earliest, 0).UTC().Format(time.RFC3339),
+		result.Periods, result.FinishedAt.Sub(result.StartedAt).Round(time.Millisecond))
+	return result, nil
+}
+
+type rollupKey struct {
+	interval string
+	period   int64
+	chainID  uint64
+}
+
+// rollupBuilder 在内存中累加各周期的统计
+type rollupBuilder struct {
+	periodStarts map[string]int64
+	rollups      map[rollupKey]*models.StatRollup
+	wallets      map[rollupKey]map[string]bool
+	amounts      map[rollupKey]*big.Int
+}
+
+// each 对时间戳 ts 所在的每种周期调用 fn，早于重新计算起点的周期跳过
+func (b *rollupBuilder) each(ts int64, chainID uint64, network string, fn func(key rollupKey, r *models.StatRollup)) {
+	if ts <= 0 {
+		return
+	}
+	for _, interval := range rollupIntervals {
+		period := periodStart(time.Unix(ts, 0), interval).Unix()
+		if period < b.periodStarts[interval] {
+			continue
+		}
+		key := rollupKey{interval: interval, period: period, chainID: chainID}
+		r, ok := b.rollups[key]
+		if !ok {
+			r = &models.StatRollup{Interval: interval, PeriodStart: period, ChainID: chainID, Network: network}
+			b.rollups[key] = r
+		}
+		if r.Network == "" {
+			r.Network = network
+		}
+		fn(key, r)
+	}
+}
+
+func (b *rollupBuilder) addWallet(key rollupKey, wallet string) {
+	if b.wallets[key] == nil {
+		b.wallets[key] = make(map[string]bool)
+	}
+	b.wallets[key][strings.ToLower(wallet)] = true
+}
+
+// aggregate 加载 earliest 之后的数据并按周期和链汇总
+func (s *StatsRollupService) aggregate(periodStarts map[string]int64, earliest int64) ([]models.StatRollup, error) {
+	b := &rollupBuilder{
+		periodStarts: periodStarts,
+		rollups:      make(map[rollupKey]*models.StatRollup),
+		wallets:      make(map[rollupKey]map[string]bool),
+		amounts:      make(map[rollupKey]*big.Int),
+	}
+
+	events, err := s.repo.GetEventsStartedSince(earliest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load events: %w", err)
+	}
+	eventIDs := make([]string, 0, len(events))
+	for _, e := range events {
+		b.each(e.StartTime, e.ChainID, e.Network, func(_ rollupKey, r *models.StatRollup) { r.EventsStarted++ })
+		eventIDs = append(eventIDs, e.EventID)
+	}
+
+	// 签到转化率按活动开始的周期归集
+	counts, err := s.repo.GetParticipantCounts(eventIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load participant counts: %w", err)
+	}
+	countsByEvent := make(map[string]repositories.EventParticipantCount, len(counts))
+	for _, c := range counts {
+		countsByEvent[fmt.Sprintf("%d/%s/%s", c.ChainID, c.ContractAddress, c.EventID)] = c
+	}
+	for _, e := range events {
+		c, ok := countsByEvent[fmt.Sprintf("%d/%s/%s", e.ChainID, e.ContractAddress, e.EventID)]
+		if !ok {
+			continue
+		}
+		b.each(e.StartTime, e.ChainID, e.Network, func(_ rollupKey, r *models.StatRollup) {
+			r.CohortRegistrations += c.Registered
+			r.CohortCheckIns += c.CheckedIn
+		})
+	}
+
+	participants, err := s.repo.GetParticipantsActiveSince(earliest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load participants: %w", err)
+	}
+	for _, p := range participants {
+		b.each(p.RegisteredAt, p.ChainID, p.Network, func(key rollupKey, r *models.StatRollup) {
+			r.Registrations++
+			b.addWallet(key, p.Wallet)
+		})
+		if p.CheckedIn {
+			b.each(p.CheckInTime, p.ChainID, p.Network, func(key rollupKey, r *models.StatRollup) {
+				r.CheckIns++
+				b.addWallet(key, p.Wallet)
+			})
+		}
+	}
+
+	sponsors, err := s.repo.GetSponsorsSince(earliest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load sponsors: %w", err)
+	}
+	for _, sp := range sponsors {
+		amount, ok := new(big.Int).SetString(sp.Amount, 10)
+		if !ok {
+			amount = new(big.Int)
+		}
+		b.each(sp.SponsoredAt, sp.ChainID, sp.Network, func(key rollupKey, r *models.StatRollup) {
+			r.SponsorCount++
+			if b.amounts[key] == nil {
+				b.amounts[key] = new(big.Int)
+			}
+			b.amounts[key].Add(b.amounts[key], amount)
+		})
+	}
+
+	rollups := make([]models.StatRollup, 0, len(b.rollups))
+	for key, r := range b.rollups {
+		r.ActiveParticipants = int64(len(b.wallets[key]))
+		r.SponsorAmount = "0"
+		if amount := b.amounts[key]; amount != nil {
+			r.SponsorAmount = amount.String()
+		}
+		rollups = append(rollups, *r)
+	}
+	return rollups, nil
+}
+
+// GetTimeseries 读取汇总表；network 为空时返回所有链，from/to 为 0 时默认最近 12 个周期
+func (s *StatsRollupService) GetTimeseries(interval string, network string, from int64, to int64) (*Timeseries, error) {
+	if interval == "" {
+		interval = IntervalWeek
+	}
+	if !isRollupInterval(interval) {
+		return nil, ErrInvalidRollupInterval
+	}
+
+	query := repositories.StatsQuery{Interval: interval}
+	if network != "" {
+		chainID, ok := config.ChainIDForNetwork(network)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownNetwork, network)
+		}
+		query.ChainID = chainID
+	}
+
+	now := time.Now()
+	if to <= 0 {
+		to = now.Unix()
+	}
+	if from <= 0 {
+		from = periodStart(time.Unix(to, 0), interval).Unix()
+		for i := 1; i < timeseriesDefaultPeriods; i++ {
+			from = periodStart(time.Unix(from-1, 0), interval).Unix()
+		}
+	}
+	query.From = periodStart(time.Unix(from, 0), interval).Unix()
+	query.To = to
+
+	rollups, err := s.repo.GetRollups(query)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Timeseries{Interval: interval, From: query.From, To: query.To, Networks: []NetworkTimeseries{}, GeneratedAt: now}
+	byChain := make(map[uint64]*NetworkTimeseries)
+	for _, r := range rollups {
+		series, ok := byChain[r.ChainID]
+		if !ok {
+			result.Networks = append(result.Networks, NetworkTimeseries{
+				ChainID: r.ChainID,
+				Network: r.Network,
+				Unit:    config.NativeTokenSymbol(r.Network),
+				Points:  []TimeseriesPoint{},
+			})
+			series = &result.Networks[len(result.Networks)-1]
+			byChain[r.ChainID] = series
+		}
+		series.Points = append(series.Points, toTimeseriesPoint(r))
+
+		if result.RolledUpAt == nil || r.UpdatedAt.After(*result.RolledUpAt) {
+			updatedAt := r.UpdatedAt
+			result.RolledUpAt = &updatedAt
+		}
+	}
+	sort.Slice(result.Networks, func(i, j int) bool { return result.Networks[i].ChainID < result.Networks[j].ChainID })
+	return result, nil
+}
+
+func toTimeseriesPoint(r models.StatRollup) TimeseriesPoint {
+	amount, ok := new(big.Int).SetString(r.SponsorAmount, 10)
+	if !ok {
+		amount = new(big.Int)
+	}
+	point := TimeseriesPoint{
+		PeriodStart:        r.PeriodStart,
+		EventsStarted:      r.EventsStarted,
+		ActiveParticipants: r.ActiveParticipants,
+		Registrations:      r.Registrations,
+		CheckIns:           r.CheckIns,
+		SponsorCount:       r.SponsorCount,
+		SponsorAmountWei:   amount.String(),
+		SponsorAmount:      formatUnits(amount, nativeTokenDecimals),
+	}
+	if r.CohortRegistrations > 0 {
+		conversion := float64(r.CohortCheckIns) / float64(r.CohortRegistrations)
+		point.CheckInConversion = &conversion
+	}
+	return point
+}
+
+func isRollupInterval(interval string) bool {
+	for _, i := range rollupIntervals {
+		if i == interval {
+			return true
+		}
+	}
+	return false
+}
+
+// periodStart 返回 t 所在周期的起点（UTC），周从周一开始
+func periodStart(t time.Time, interval string) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	switch interval {
+	case IntervalWeek:
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	case IntervalMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return day
+	}
+}
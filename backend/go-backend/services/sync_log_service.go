@@ -0,0 +1,182 @@
+package services
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"hackathon-backend/config"
+	"hackathon-backend/models"
+	"hackathon-backend/repositories"
+)
+
+// syncLogArchiveBatchSize 归档时每批读取的日志条数
+const syncLogArchiveBatchSize = 1000
+
+// CompactionResult 一次同步日志压缩的结果
+type CompactionResult struct {
+	Expired     int64     `json:"expired"`      // 过期日志条数
+	Deleted     int64     `json:"deleted"`      // 删除条数
+	ArchiveFile string    `json:"archive_file"` // 归档文件路径（未归档时为空）
+	StartedAt   time.Time `json:"started_at"`
+	FinishedAt  time.Time `json:"finished_at"`
+}
+
+type SyncLogService struct {
+	repo *repositories.SyncLogRepository
+
+	successRetention time.Duration
+	failureRetention time.Duration
+	interval         time.Duration
+	archiveDir       string
+
+	// 防止定时任务与手动触发并发执行
+	mu sync.Mutex
+}
+
+func NewSyncLogService(repo *repositories.SyncLogRepository) *SyncLogService {
+	cfg := config.AppConfig
+	return &SyncLogService{
+		repo:             repo,
+		successRetention: time.Duration(cfg.SyncLogSuccessRetentionDays) * 24 * time.Hour,
+		failureRetention: time.Duration(cfg.SyncLogFailureRetentionDays) * 24 * time.Hour,
+		interval:         time.Duration(cfg.SyncLogCompactInterval) * time.Minute,
+		archiveDir:       cfg.SyncLogArchiveDir,
+	}
+}
+
+// Run 定期压缩过期的同步日志
+func (s *SyncLogService) Run(ctx context.Context) {
+	if s.interval <= 0 {
+		log.Println("⚠️ Sync log compaction disabled")
+		return
+	}
+
+	log.Printf("🧹 Sync log compaction every %s (success retention %s, failure retention %s)",
+		s.interval, s.successRetention, s.failureRetention)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.Compact(); err != nil {
+			log.Printf("❌ Sync log compaction failed: %v", err)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Compact 归档过期日志，汇总为每日统计后删除
+func (s *SyncLogService) Compact() (*CompactionResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	result := &CompactionResult{StartedAt: now}
+
+	maxID, err := s.repo.GetMaxID()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get max sync log id: %w", err)
+	}
+
+	expiry := repositories.SyncLogExpiry{
+		SuccessBefore: now.Add(-s.successRetention),
+		FailureBefore: now.Add(-s.failureRetention),
+		MaxID:         maxID,
+	}
+
+	result.Expired, err = s.repo.CountExpired(expiry)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count expired sync logs: %w", err)
+	}
+	if result.Expired == 0 {
+		result.FinishedAt = time.Now()
+		return result, nil
+	}
+
+	// 先归档，归档失败则不删除
+	if s.archiveDir != "" {
+		result.ArchiveFile, err = s.archive(expiry, now)
+		if err != nil {
+			return nil, fmt.Errorf("failed to archive sync logs: %w", err)
+		}
+	}
+
+	result.Deleted, err = s.repo.CompactExpired(expiry)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compact sync logs: %w", err)
+	}
+
+	result.FinishedAt = time.Now()
+	log.Printf("🧹 Compacted %d sync logs (archive: %s)", result.Deleted, result.ArchiveFile)
+	return result, nil
+}
+
+// archive 将过期日志导出为 gzip 压缩的 JSONL 文件，返回文件路径
+func (s *SyncLogService) archive(expiry repositories.SyncLogExpiry, now time.Time) (string, error) {
+	if err := os.MkdirAll(s.archiveDir, 0o755); err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(s.archiveDir, fmt.Sprintf("sync_logs_%s_%d.jsonl.gz", now.Format("20060102T150405"), expiry.MaxID))
+	tmpPath := path + ".tmp"
+
+	file, err := os.Create(tmpPath)
+	if err != nil {
+		return "", err
+	}
+	// 出错时清理临时文件
+	defer os.Remove(tmpPath)
+	defer file.Close()
+
+	gz := gzip.NewWriter(file)
+	writer := bufio.NewWriter(gz)
+	encoder := json.NewEncoder(writer)
+
+	err = s.repo.EachExpired(expiry, syncLogArchiveBatchSize, func(logs []models.SyncLog) error {
+		for i := range logs {
+			if err := encoder.Encode(&logs[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if err := writer.Flush(); err != nil {
+		return "", err
+	}
+	if err := gz.Close(); err != nil {
+		return "", err
+	}
+	if err := file.Sync(); err != nil {
+		return "", err
+	}
+	if err := file.Close(); err != nil {
+		return "", err
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// GetDailyStats 获取同步日志每日汇总
+func (s *SyncLogService) GetDailyStats(network string, since string) ([]models.SyncLogDailyStat, error) {
+	return s.repo.GetDailyStats(network, since)
+}
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"hackathon-backend/config"
+	"hackathon-backend/models"
+	"hackathon-backend/repositories"
+)
+
+var ErrInvalidAddress = models.ErrInvalidAddress
+
+// WalletSummary 钱包活动计数
+type WalletSummary struct {
+	Registered int      `json:"registered"`
+	CheckedIn  int      `json:"checked_in"`
+	Sponsored  int      `json:"sponsored"`
+	Organized  int      `json:"organized"`
+	Tickets    int      `json:"tickets"`
+	Networks   []string `json:"networks"` // 有活动记录的网络
+}
+
+// WalletProfile 钱包在各链上的报名、签到、赞助、组织和持票记录
+type WalletProfile struct {
+	Address       models.Address                     `json:"address"` // EIP-55 校验和地址
+	Summary       WalletSummary                      `json:"summary"`
+	Registrations []repositories.WalletParticipation `json:"registrations"`
+	Sponsorships  []repositories.WalletSponsorship   `json:"sponsorships"`
+	SponsorTotals []SponsorTotal                     `json:"sponsor_totals"` // 按原生代币汇总的赞助金额
+	Organized     []models.Event                     `json:"organized"`
+	Tickets       []models.NFTTicket                 `json:"tickets"`
+	GeneratedAt   time.Time                          `json:"generated_at"`
+}
+
+type WalletService struct {
+	repo *repositories.WalletRepository
+}
+
+func NewWalletService(repo *repositories.WalletRepository) *WalletService {
+	return &WalletService{repo: repo}
+}
+
+// GetProfile 汇总钱包的活动记录，network 为空时包含所有链
+func (s *WalletService) GetProfile(address string, network string) (*WalletProfile, error) {
+	wallet, err := models.ParseAddress(address)
+	if err != nil {
+		return nil, err
+	}
+	var chainID uint64
+	if network != "" {
+		id, ok := config.ChainIDForNetwork(network)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownNetwork, network)
+		}
+		chainID = id
+	}
+
+	profile := &WalletProfile{Address: wallet, GeneratedAt: time.Now()}
+	if profile.Registrations, err = s.repo.GetParticipations(wallet, chainID); err != nil {
+		return nil, err
+	}
+	if profile.Sponsorships, err = s.repo.GetSponsorships(wallet, chainID); err != nil {
+		return nil, err
+	}
+	if profile.Organized, err = s.repo.GetOrganizedEvents(wallet, chainID); err != nil {
+		return nil, err
+	}
+	if profile.Tickets, err = s.repo.GetHeldTickets(wallet, chainID); err != nil {
+		return nil, err
+	}
+
+	networks := make(map[string]bool)
+	amounts := make([]repositories.SponsorAmount, 0, len(profile.Sponsorships))
+	for _, p := range profile.Registrations {
+		networks[p.Network] = true
+		if p.CheckedIn {
+			profile.Summary.CheckedIn++
+		}
+	}
+	for _, sp := range profile.Sponsorships {
+		networks[sp.Network] = true
+		amounts = append(amounts, repositories.SponsorAmount{Network: sp.Network, Amount: sp.Amount})
+	}
+	for _, e := range profile.Organized {
+		networks[e.Network] = true
+	}
+	for _, t := range profile.Tickets {
+		networks[t.Network] = true
+	}
+	delete(networks, "")
+
+	profile.SponsorTotals = sumSponsorAmounts(amounts)
+	profile.Summary.Registered = len(profile.Registrations)
+	profile.Summary.Sponsored = len(profile.Sponsorships)
+	profile.Summary.Organized = len(profile.Organized)
+	profile.Summary.Tickets = len(profile.Tickets)
+	profile.Summary.Networks = make([]string, 0, len(networks))
+	for network := range networks {
+		profile.Summary.Networks = append(profile.Summary.Networks, network)
+	}
+	sort.Strings(profile.Summary.Networks)
+
+	return profile, nil
+}
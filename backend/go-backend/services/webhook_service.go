@@ -0,0 +1,597 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"hackathon-backend/config"
+	"hackathon-backend/models"
+	"hackathon-backend/repositories"
+
+	"gorm.io/gorm"
+)
+
+const (
+	webhookDeliveryBatchSize = 50
+	webhookMaxRetryDelay     = 6 * time.Hour
+	webhookResponseBodyLimit = 2048 // 投递日志中保存的响应内容长度上限
+	webhookSignatureHeader   = "X-Webhook-Signature"
+	webhookTimestampHeader   = "X-Webhook-Timestamp"
+	webhookEventHeader       = "X-Webhook-Event"
+	webhookDeliveryHeader    = "X-Webhook-Delivery"
+	webhookSecretBytes       = 32
+	webhookUserAgent         = "HackChain-Webhook/1.0"
+)
+
+// webhookEventTypes 可订阅的事件类型
+var webhookEventTypes = map[string]bool{
+	DomainEventCreated:          true,
+	DomainEventClosed:           true,
+	DomainParticipantRegistered: true,
+	DomainParticipantCheckedIn:  true,
+	DomainSponsorAdded:          true,
+	DomainTicketIssued:          true,
+	DomainTicketUsed:            true,
+	DomainTicketTransferred:     true,
+}
+
+var (
+	// ErrWebhookNotFound 订阅或投递不存在
+	ErrWebhookNotFound = errors.New("webhook not found")
+	// ErrInvalidWebhook 订阅参数不合法
+	ErrInvalidWebhook = errors.New("invalid webhook")
+)
+
+// WebhookInput 创建或更新订阅的参数
+type WebhookInput struct {
+	Organizer  models.Address
+	EventID    string
+	URL        string
+	Secret     string
+	EventTypes []string
+	Active     *bool
+}
+
+// WebhookPayload 投递给订阅方的请求体
+type WebhookPayload struct {
+	Type      string       `json:"type"`
+	CreatedAt time.Time    `json:"created_at"`
+	Event     *DomainEvent `json:"event"`
+}
+
+type WebhookService struct {
+	repo      *repositories.WebhookRepository
+	eventRepo *repositories.EventRepository
+	client    *http.Client
+
+	maxAttempts  int
+	retryBase    time.Duration
+	pollInterval time.Duration
+
+	// 防止并发投递同一批记录
+	mu sync.Mutex
+	// 唤醒投递协程
+	wake chan struct{}
+}
+
+func NewWebhookService(repo *repositories.WebhookRepository, eventRepo *repositories.EventRepository) *WebhookService {
+	cfg := config.AppConfig
+
+	maxAttempts := cfg.WebhookMaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = 8
+	}
+	retryBase := time.Duration(cfg.WebhookRetryBase) * time.Second
+	if retryBase <= 0 {
+		retryBase = 10 * time.Second
+	}
+	pollInterval := time.Duration(cfg.WebhookPollInterval) * time.Second
+	if pollInterval <= 0 {
+		pollInterval = 5 * time.Second
+	}
+	timeout := time.Duration(cfg.WebhookTimeout) * time.Second
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+
+	return &WebhookService{
+		repo:      repo,
+		eventRepo: eventRepo,
+		client: &http.Client{
+			Timeout:   timeout,
+			Transport: newWebhookTransport(cfg.WebhookAllowPrivate),
+			// 不跟随重定向，避免签名请求被转发到其他地址
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		maxAttempts:  maxAttempts,
+		retryBase:    retryBase,
+		pollInterval: pollInterval,
+		wake:         make(chan struct{}, 1),
+	}
+}
+
+// Run 在后台投递到期的记录（投递记录由日志写入事务通过 EnqueueTx 生成），被唤醒或定时扫描，包括等待重试的记录
+func (s *WebhookService) Run(ctx context.Context) {
+	log.Printf("🪝 Webhook dispatcher started (max attempts %d, retry base %s)", s.maxAttempts, s.retryBase)
+
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		s.deliverDue()
+
+		select {
+		case <-s.wake:
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Notify 唤醒投递协程
+func (s *WebhookService) Notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// EnqueueTx 在日志写入事务中为匹配的订阅创建待投递记录（发件箱），与日志数据一起提交或回滚
+func (s *WebhookService) EnqueueTx(tx *gorm.DB, evt *DomainEvent) error {
+	if evt.EventID == "" {
+		return nil
+	}
+	repo := s.repo.WithTx(tx)
+
+	var organizer models.Address
+	event, err := s.eventRepo.WithTx(tx).FindEventOnChain(evt.ChainID, evt.EventID)
+	if err != nil {
+		return err
+	}
+	if event != nil {
+		organizer = event.Organizer
+	}
+
+	subs, err := repo.FindMatchingSubscriptions(evt.EventID, organizer)
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(WebhookPayload{Type: evt.Type, CreatedAt: evt.OccurredAt, Event: evt})
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var deliveries []models.WebhookDelivery
+	for _, sub := range subs {
+		if !sub.Accepts(evt.Type) {
+			continue
+		}
+		deliveries = append(deliveries, models.WebhookDelivery{
+			SubscriptionID: sub.ID,
+			EventType:      evt.Type,
+			EventID:        evt.EventID,
+			Payload:        string(payload),
+			Status:         models.WebhookDeliveryPending,
+			NextAttemptAt:  now,
+		})
+	}
+
+	return repo.CreateDeliveries(deliveries)
+}
+
+// deliverDue 投递所有到期的记录
+func (s *WebhookService) deliverDue() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for {
+		deliveries, err := s.repo.GetDueDeliveries(time.Now(), webhookDeliveryBatchSize)
+		if err != nil {
+			log.Printf("❌ Failed to load due webhook deliveries: %v", err)
+			return
+		}
+
+		for i := range deliveries {
+			// 无法记录结果时停止本轮，避免反复取到同一批记录
+			if err := s.attempt(&deliveries[i]); err != nil {
+				log.Printf("❌ Webhook delivery %d: %v", deliveries[i].ID, err)
+				return
+			}
+		}
+
+		if len(deliveries) < webhookDeliveryBatchSize {
+			return
+		}
+	}
+}
+
+// attempt 发送一次请求并根据结果更新投递状态
+func (s *WebhookService) attempt(delivery *models.WebhookDelivery) error {
+	sub, err := s.repo.GetSubscription(delivery.SubscriptionID)
+	if err != nil {
+		return fmt.Errorf("failed to load subscription %d: %w", delivery.SubscriptionID, err)
+	}
+
+	now := time.Now()
+	delivery.Attempts++
+	delivery.LastAttemptAt = &now
+	record := &models.WebhookDeliveryAttempt{DeliveryID: delivery.ID, Attempt: delivery.Attempts}
+
+	if sub == nil || !sub.Active {
+		// 订阅已删除或停用，不再重试
+		delivery.Status = models.WebhookDeliveryFailed
+		delivery.LastError = "subscription is deleted or inactive"
+		record.Error = delivery.LastError
+	} else {
+		status, body, err := s.send(sub, delivery, now)
+		record.DurationMs = time.Since(now).Milliseconds()
+		record.ResponseStatus = status
+		record.ResponseBody = body
+		delivery.ResponseStatus = status
+
+		switch {
+		case err == nil && status >= 200 && status < 300:
+			delivery.Status = models.WebhookDeliverySucceeded
+			delivery.LastError = ""
+		default:
+			if err != nil {
+				delivery.LastError = err.Error()
+			} else {
+				delivery.LastError = fmt.Sprintf("unexpected response status %d", status)
+			}
+			record.Error = delivery.LastError
+
+			if delivery.Attempts >= s.maxAttempts {
+				delivery.Status = models.WebhookDeliveryFailed
+			} else {
+				delivery.NextAttemptAt = now.Add(s.retryDelay(delivery.Attempts))
+			}
+		}
+	}
+
+	if err := s.repo.RecordAttempt(delivery, record); err != nil {
+		return fmt.Errorf("failed to record attempt: %w", err)
+	}
+
+	switch delivery.Status {
+	case models.WebhookDeliverySucceeded:
+		log.Printf("🪝 Delivered webhook %d (%s) to subscription %d", delivery.ID, delivery.EventType, delivery.SubscriptionID)
+	case models.WebhookDeliveryFailed:
+		log.Printf("❌ Webhook delivery %d failed after %d attempts: %s", delivery.ID, delivery.Attempts, delivery.LastError)
+	default:
+		log.Printf("⚠️ Webhook delivery %d attempt %d failed, retrying at %s: %s",
+			delivery.ID, delivery.Attempts, delivery.NextAttemptAt.Format(time.RFC3339), delivery.LastError)
+	}
+	return nil
+}
+
+// send 发送签名后的请求，返回响应状态码和截断后的响应内容
+func (s *WebhookService) send(sub *models.WebhookSubscription, delivery *models.WebhookDelivery, now time.Time) (int, string, error) {
+	body := []byte(delivery.Payload)
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+
+	req, err := http.NewRequest(http.MethodPost, sub.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", webhookUserAgent)
+	req.Header.Set(webhookEventHeader, delivery.EventType)
+	req.Header.Set(webhookDeliveryHeader, strconv.FormatUint(uint64(delivery.ID), 10))
+	req.Header.Set(webhookTimestampHeader, timestamp)
+	req.Header.Set(webhookSignatureHeader, SignWebhookPayload(sub.Secret, timestamp, body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, webhookResponseBodyLimit))
+	return resp.StatusCode, string(respBody), nil
+}
+
+// retryDelay 指数退避：retryBase * 2^(attempts-1)，最长 webhookMaxRetryDelay
+func (s *WebhookService) retryDelay(attempts int) time.Duration {
+	delay := s.retryBase
+	for i := 1; i < attempts && delay < webhookMaxRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > webhookMaxRetryDelay {
+		delay = webhookMaxRetryDelay
+	}
+	return delay
+}
+
+// SignWebhookPayload 计算签名：sha256=hex(HMAC-SHA256(secret, timestamp + "." + body))
+func SignWebhookPayload(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// CreateSubscription 创建订阅，未提供密钥时自动生成
+func (s *WebhookService) CreateSubscription(input WebhookInput) (*models.WebhookSubscription, error) {
+	if err := validateWebhookURL(context.Background(), input.URL); err != nil {
+		return nil, err
+	}
+	eventTypes, err := normalizeWebhookEventTypes(input.EventTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.EventID != "" {
+		event, err := s.eventRepo.FindEventOnChain(config.AppConfig.GetActiveChainID(), input.EventID)
+		if err != nil {
+			return nil, err
+		}
+		if event == nil {
+			return nil, fmt.Errorf("%w: event %s not found", ErrInvalidWebhook, input.EventID)
+		}
+		if !event.Organizer.Equal(input.Organizer) {
+			return nil, fmt.Errorf("%w: event %s is not organized by %s", ErrInvalidWebhook, input.EventID, input.Organizer)
+		}
+	}
+
+	secret := input.Secret
+	if secret == "" {
+		if secret, err = generateWebhookSecret(); err != nil {
+			return nil, err
+		}
+	}
+
+	sub := &models.WebhookSubscription{
+		Organizer:  input.Organizer,
+		EventID:    input.EventID,
+		URL:        input.URL,
+		Secret:     secret,
+		EventTypes: eventTypes,
+		Active:     input.Active == nil || *input.Active,
+	}
+	if err := s.repo.CreateSubscription(sub); err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+// UpdateSubscription 更新订阅的地址、密钥、事件类型或启用状态
+func (s *WebhookService) UpdateSubscription(id uint, input WebhookInput) (*models.WebhookSubscription, error) {
+	sub, err := s.GetSubscription(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.URL != "" {
+		if err := validateWebhookURL(context.Background(), input.URL); err != nil {
+			return nil, err
+		}
+		sub.URL = input.URL
+	}
+	if input.Secret != "" {
+		sub.Secret = input.Secret
+	}
+	if input.EventTypes != nil {
+		if sub.EventTypes, err = normalizeWebhookEventTypes(input.EventTypes); err != nil {
+			return nil, err
+		}
+	}
+	if input.Active != nil {
+		sub.Active = *input.Active
+	}
+
+	if err := s.repo.UpdateSubscription(sub); err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+// DeleteSubscription 删除订阅
+func (s *WebhookService) DeleteSubscription(id uint) error {
+	if _, err := s.GetSubscription(id); err != nil {
+		return err
+	}
+	return s.repo.DeleteSubscription(id)
+}
+
+// GetSubscription 获取订阅
+func (s *WebhookService) GetSubscription(id uint) (*models.WebhookSubscription, error) {
+	sub, err := s.repo.GetSubscription(id)
+	if err != nil {
+		return nil, err
+	}
+	if sub == nil {
+		return nil, ErrWebhookNotFound
+	}
+	return sub, nil
+}
+
+// GetSubscriptionsByOrganizer 获取组织者的订阅
+func (s *WebhookService) GetSubscriptionsByOrganizer(organizer models.Address) ([]models.WebhookSubscription, error) {
+	return s.repo.GetSubscriptionsByOrganizer(organizer)
+}
+
+// ListDeliveries 分页获取订阅的投递记录
+func (s *WebhookService) ListDeliveries(filter repositories.DeliveryFilter, page repositories.PageQuery) (*repositories.Page[models.WebhookDelivery], error) {
+	if _, err := s.GetSubscription(filter.SubscriptionID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListDeliveries(filter, page)
+}
+
+// GetDeliveryAttempts 获取投递的请求日志
+func (s *WebhookService) GetDeliveryAttempts(subscriptionID uint, deliveryID uint) ([]models.WebhookDeliveryAttempt, error) {
+	delivery, err := s.repo.GetDelivery(subscriptionID, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	if delivery == nil {
+		return nil, ErrWebhookNotFound
+	}
+	return s.repo.GetAttempts(deliveryID)
+}
+
+// Redeliver 以相同的请求体创建一条新的投递并立即触发投递
+func (s *WebhookService) Redeliver(subscriptionID uint, deliveryID uint) (*models.WebhookDelivery, error) {
+	original, err := s.repo.GetDelivery(subscriptionID, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	if original == nil {
+		return nil, ErrWebhookNotFound
+	}
+
+	delivery := &models.WebhookDelivery{
+		SubscriptionID: original.SubscriptionID,
+		EventType:      original.EventType,
+		EventID:        original.EventID,
+		Payload:        original.Payload,
+		Status:         models.WebhookDeliveryPending,
+		NextAttemptAt:  time.Now(),
+		RedeliveryOf:   &original.ID,
+	}
+	if err := s.repo.CreateDelivery(delivery); err != nil {
+		return nil, err
+	}
+
+	s.Notify()
+	return delivery, nil
+}
+
+// validateWebhookURL 只允许 http/https 地址，且域名解析出的地址都必须是公网地址
+func validateWebhookURL(ctx context.Context, raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return fmt.Errorf("%w: url must be an absolute http(s) URL", ErrInvalidWebhook)
+	}
+	if config.AppConfig.WebhookAllowPrivate {
+		return nil
+	}
+
+	host := u.Hostname()
+	if ip := net.ParseIP(host); ip != nil {
+		if !isPublicIP(ip) {
+			return fmt.Errorf("%w: url must not point to a private or loopback address", ErrInvalidWebhook)
+		}
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil || len(addrs) == 0 {
+		return fmt.Errorf("%w: url host %s cannot be resolved", ErrInvalidWebhook, host)
+	}
+	for _, addr := range addrs {
+		if !isPublicIP(addr.IP) {
+			return fmt.Errorf("%w: url host %s resolves to a private or loopback address", ErrInvalidWebhook, host)
+		}
+	}
+	return nil
+}
+
+// webhookBlockedNets 标准库判断之外需要拒绝的保留网段
+var webhookBlockedNets = func() []*net.IPNet {
+	var nets []*net.IPNet
+	for _, cidr := range []string{
+		"0.0.0.0/8",     // 本网络
+		"100.64.0.0/10", // CGNAT
+		"192.0.0.0/24",  // IETF 协议分配
+		"198.18.0.0/15", // 基准测试
+		"64:ff9b::/96",  // NAT64，可映射到内网 IPv4
+	} {
+		_, n, _ := net.ParseCIDR(cidr)
+		nets = append(nets, n)
+	}
+	return nets
+}()
+
+// isPublicIP 是否为可投递的公网地址
+func isPublicIP(ip net.IP) bool {
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4
+	}
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+	for _, n := range webhookBlockedNets {
+		if n.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// newWebhookTransport 投递使用的 Transport：不走代理，并在建立连接前校验实际连接的 IP，
+// 防止域名在注册校验后被重新解析到内网地址（DNS 重绑定）
+func newWebhookTransport(allowPrivate bool) *http.Transport {
+	dialer := &net.Dialer{Timeout: 10 * time.Second, KeepAlive: 30 * time.Second}
+	if !allowPrivate {
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !isPublicIP(ip) {
+				return fmt.Errorf("webhook destination %s is not a public address", host)
+			}
+			return nil
+		}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return transport
+}
+
+// normalizeWebhookEventTypes 校验并拼接事件类型，空列表表示所有类型
+func normalizeWebhookEventTypes(types []string) (string, error) {
+	seen := map[string]bool{}
+	var out []string
+	for _, t := range types {
+		t = strings.TrimSpace(t)
+		if t == "" || seen[t] {
+			continue
+		}
+		if !webhookEventTypes[t] {
+			return "", fmt.Errorf("%w: unknown event type %q", ErrInvalidWebhook, t)
+		}
+		seen[t] = true
+		out = append(out, t)
+	}
+	return strings.Join(out, ","), nil
+}
+
+// generateWebhookSecret 生成随机签名密钥
+func generateWebhookSecret() (string, error) {
+	buf := make([]byte, webhookSecretBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,60 @@
+// Package testdb 为测试提供内存 SQLite 数据库，只应由 _test.go 引用
+package testdb
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+var counter atomic.Uint64
+
+// Open 打开独立的内存数据库并迁移给定模型，测试结束时自动关闭
+func Open(t testing.TB, models ...interface{}) *gorm.DB {
+	t.Helper()
+
+	// 每个测试使用独立的共享缓存库，连接池中的多个连接看到同一份数据
+	dsn := fmt.Sprintf("file:testdb%d?mode=memory&cache=shared", counter.Add(1))
+	db, err := gorm.Open(dialector{sqlite.Open(dsn)}, &gorm.Config{
+		Logger:                                   logger.Default.LogMode(logger.Silent),
+		DisableForeignKeyConstraintWhenMigrating: true,
+	})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+
+	if err := db.AutoMigrate(models...); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return db
+}
+
+// dialector 包装 SQLite 方言，跳过 MySQL 专用的 FULLTEXT 索引
+type dialector struct {
+	gorm.Dialector
+}
+
+func (d dialector) Migrator(db *gorm.DB) gorm.Migrator {
+	return migrator{d.Dialector.Migrator(db)}
+}
+
+type migrator struct {
+	gorm.Migrator
+}
+
+func (m migrator) CreateIndex(value interface{}, name string) error {
+	if strings.Contains(name, "fulltext") {
+		return nil
+	}
+	return m.Migrator.CreateIndex(value, name)
+}